@@ -0,0 +1,437 @@
+// Package config loads the application's settings from an optional JSON file
+// and the environment into one typed struct, and validates them at startup.
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Environments accepted in APP_ENV. Production refuses default secrets and
+// insecure cookies.
+const (
+	Development = "development"
+	Production  = "production"
+)
+
+// Defaults that are only acceptable outside production.
+const (
+	DefaultJWTSecret  = "devsecret"
+	DefaultDBPassword = "postgres"
+)
+
+// Config is the application's configuration. The JSON field names are the
+// keys of the config file; each field's env var is listed in envVars.
+type Config struct {
+	Env       string    `json:"env"`
+	Store     string    `json:"store"`
+	DB        DB        `json:"db"`
+	HTTP      HTTP      `json:"http"`
+	Cookies   Cookies   `json:"cookies"`
+	Voting    Voting    `json:"voting"`
+	Templates Templates `json:"templates"`
+	Secrets   Secrets   `json:"secrets"`
+	Log       Log       `json:"log"`
+	Metrics   Metrics   `json:"metrics"`
+}
+
+// DB is the Postgres connection, its pool and the per-request query budget.
+// URL, when set, wins over the individual connection fields.
+type DB struct {
+	URL             string   `json:"url"`
+	Host            string   `json:"host"`
+	Port            int      `json:"port"`
+	User            string   `json:"user"`
+	Password        string   `json:"password"`
+	Name            string   `json:"name"`
+	SSLMode         string   `json:"sslmode"`
+	MaxOpenConns    int      `json:"max_open_conns"`
+	MaxIdleConns    int      `json:"max_idle_conns"`
+	ConnMaxLifetime Duration `json:"conn_max_lifetime"`
+	ConnMaxIdleTime Duration `json:"conn_max_idle_time"`
+	QueryTimeout    Duration `json:"query_timeout"`
+}
+
+// DSN returns URL or a lib/pq keyword DSN built from the other fields.
+func (d DB) DSN() string {
+	if d.URL != "" {
+		return d.URL
+	}
+	return fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s sslmode=%s",
+		d.Host, d.Port, d.User, d.Password, d.Name, d.SSLMode)
+}
+
+// HTTP is the listen address, the server timeouts and the optional TLS
+// certificate. ShutdownTimeout bounds how long in-flight requests may drain
+// after SIGTERM.
+type HTTP struct {
+	Addr              string   `json:"addr"`
+	ReadTimeout       Duration `json:"read_timeout"`
+	ReadHeaderTimeout Duration `json:"read_header_timeout"`
+	WriteTimeout      Duration `json:"write_timeout"`
+	IdleTimeout       Duration `json:"idle_timeout"`
+	ShutdownTimeout   Duration `json:"shutdown_timeout"`
+	TLSCertFile       string   `json:"tls_cert_file"`
+	TLSKeyFile        string   `json:"tls_key_file"`
+}
+
+type Cookies struct {
+	Secure bool `json:"secure"`
+}
+
+// Voting holds the deadline of the ceremony seeded by STORE=memory (zero
+// means a month after startup) and the timezone deadlines are shown in.
+type Voting struct {
+	Deadline time.Time `json:"deadline"`
+	Timezone string    `json:"timezone"`
+}
+
+// Location loads Timezone; Validate has already checked that it exists.
+func (v Voting) Location() *time.Location {
+	loc, err := time.LoadLocation(v.Timezone)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+// Templates are embedded in the binary. Dir, when set, loads them from that
+// directory instead, and Reload parses them again on every request (for
+// development; Reload without Dir reads ./templates).
+type Templates struct {
+	Dir    string `json:"dir"`
+	Reload bool   `json:"reload"`
+}
+
+// Log selects the log format (json or text) and the minimum level (debug,
+// info, warn or error).
+type Log struct {
+	Format string `json:"format"`
+	Level  string `json:"level"`
+}
+
+// Metrics exposes /metrics on its own listener (Addr, e.g. 127.0.0.1:9090)
+// and/or on the main one to scrapers sending Token as a Bearer token. With
+// neither set the metrics are not served.
+type Metrics struct {
+	Addr  string `json:"addr"`
+	Token string `json:"token"`
+}
+
+type Secrets struct {
+	JWT                 string `json:"jwt"`
+	AdminBootstrapToken string `json:"admin_bootstrap_token"`
+}
+
+// Duration is a time.Duration written as a Go duration string ("5s") in the
+// config file.
+type Duration time.Duration
+
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(d).String())
+}
+
+func (d *Duration) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return fmt.Errorf("duration must be a string like \"5s\": %w", err)
+	}
+	v, err := time.ParseDuration(s)
+	if err != nil {
+		return err
+	}
+	*d = Duration(v)
+	return nil
+}
+
+// Default returns the development configuration.
+func Default() Config {
+	return Config{
+		Env:   Development,
+		Store: "postgres",
+		DB: DB{
+			Host:            "localhost",
+			Port:            5432,
+			User:            "postgres",
+			Password:        DefaultDBPassword,
+			Name:            "moviesdb",
+			SSLMode:         "disable",
+			MaxOpenConns:    25,
+			MaxIdleConns:    25,
+			ConnMaxLifetime: Duration(30 * time.Minute),
+			ConnMaxIdleTime: Duration(5 * time.Minute),
+			QueryTimeout:    Duration(5 * time.Second),
+		},
+		HTTP: HTTP{
+			Addr:              ":8080",
+			ReadTimeout:       Duration(15 * time.Second),
+			ReadHeaderTimeout: Duration(5 * time.Second),
+			WriteTimeout:      Duration(30 * time.Second),
+			IdleTimeout:       Duration(2 * time.Minute),
+			ShutdownTimeout:   Duration(20 * time.Second),
+		},
+		Voting:  Voting{Timezone: "America/Sao_Paulo"},
+		Secrets: Secrets{JWT: DefaultJWTSecret},
+		Log:     Log{Format: "json", Level: "info"},
+	}
+}
+
+// Load returns Default, overlaid with the JSON file at path (if path is not
+// empty) and then with the environment, and validated.
+func Load(path string) (Config, error) {
+	cfg := Default()
+	if path != "" {
+		if err := cfg.readFile(path); err != nil {
+			return Config{}, err
+		}
+	}
+	if err := cfg.readEnv(os.LookupEnv); err != nil {
+		return Config{}, err
+	}
+	if err := cfg.Validate(); err != nil {
+		return Config{}, err
+	}
+	return cfg, nil
+}
+
+func (c *Config) readFile(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("config: %w", err)
+	}
+	defer f.Close()
+	dec := json.NewDecoder(f)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(c); err != nil {
+		return fmt.Errorf("config: %s: %w", path, err)
+	}
+	return nil
+}
+
+// envVar binds an environment variable to a config field.
+type envVar struct {
+	name string
+	set  func(string) error
+}
+
+func (c *Config) envVars() []envVar {
+	return []envVar{
+		{"APP_ENV", str(&c.Env)},
+		{"STORE", str(&c.Store)},
+		{"DATABASE_URL", str(&c.DB.URL)},
+		{"PG_HOST", str(&c.DB.Host)},
+		{"PG_PORT", integer(&c.DB.Port)},
+		{"PG_USER", str(&c.DB.User)},
+		{"PG_PASSWORD", str(&c.DB.Password)},
+		{"PG_DB", str(&c.DB.Name)},
+		{"PG_SSLMODE", str(&c.DB.SSLMode)},
+		{"DB_MAX_OPEN_CONNS", integer(&c.DB.MaxOpenConns)},
+		{"DB_MAX_IDLE_CONNS", integer(&c.DB.MaxIdleConns)},
+		{"DB_CONN_MAX_LIFETIME", duration(&c.DB.ConnMaxLifetime)},
+		{"DB_CONN_MAX_IDLE_TIME", duration(&c.DB.ConnMaxIdleTime)},
+		{"DB_TIMEOUT", duration(&c.DB.QueryTimeout)},
+		{"HTTP_ADDR", str(&c.HTTP.Addr)},
+		{"HTTP_READ_TIMEOUT", duration(&c.HTTP.ReadTimeout)},
+		{"HTTP_READ_HEADER_TIMEOUT", duration(&c.HTTP.ReadHeaderTimeout)},
+		{"HTTP_WRITE_TIMEOUT", duration(&c.HTTP.WriteTimeout)},
+		{"HTTP_IDLE_TIMEOUT", duration(&c.HTTP.IdleTimeout)},
+		{"HTTP_SHUTDOWN_TIMEOUT", duration(&c.HTTP.ShutdownTimeout)},
+		{"TLS_CERT_FILE", str(&c.HTTP.TLSCertFile)},
+		{"TLS_KEY_FILE", str(&c.HTTP.TLSKeyFile)},
+		{"COOKIE_SECURE", boolean(&c.Cookies.Secure)},
+		{"VOTING_DEADLINE", timestamp(&c.Voting.Deadline)},
+		{"TIMEZONE", str(&c.Voting.Timezone)},
+		{"TEMPLATE_DIR", str(&c.Templates.Dir)},
+		{"TEMPLATE_RELOAD", boolean(&c.Templates.Reload)},
+		{"JWT_SECRET", str(&c.Secrets.JWT)},
+		{"ADMIN_BOOTSTRAP_TOKEN", str(&c.Secrets.AdminBootstrapToken)},
+		{"LOG_FORMAT", str(&c.Log.Format)},
+		{"LOG_LEVEL", str(&c.Log.Level)},
+		{"METRICS_ADDR", str(&c.Metrics.Addr)},
+		{"METRICS_TOKEN", str(&c.Metrics.Token)},
+	}
+}
+
+// readEnv applies every non-empty variable returned by lookup.
+func (c *Config) readEnv(lookup func(string) (string, bool)) error {
+	for _, v := range c.envVars() {
+		s, ok := lookup(v.name)
+		if !ok || s == "" {
+			continue
+		}
+		if err := v.set(s); err != nil {
+			return fmt.Errorf("config: %s: %w", v.name, err)
+		}
+	}
+	return nil
+}
+
+func str(p *string) func(string) error {
+	return func(s string) error { *p = s; return nil }
+}
+
+func integer(p *int) func(string) error {
+	return func(s string) error {
+		n, err := strconv.Atoi(s)
+		if err != nil {
+			return fmt.Errorf("want an integer, got %q", s)
+		}
+		*p = n
+		return nil
+	}
+}
+
+func boolean(p *bool) func(string) error {
+	return func(s string) error {
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return fmt.Errorf("want true or false, got %q", s)
+		}
+		*p = b
+		return nil
+	}
+}
+
+func duration(p *Duration) func(string) error {
+	return func(s string) error {
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return fmt.Errorf("want a duration like 5s, got %q", s)
+		}
+		*p = Duration(d)
+		return nil
+	}
+}
+
+func timestamp(p *time.Time) func(string) error {
+	return func(s string) error {
+		t, err := time.Parse(time.RFC3339, s)
+		if err != nil {
+			return fmt.Errorf("want an RFC 3339 time, got %q", s)
+		}
+		*p = t
+		return nil
+	}
+}
+
+// Validate reports every invalid setting at once. In production it also
+// refuses the development secrets and cookies without the Secure flag.
+func (c Config) Validate() error {
+	var errs []string
+	bad := func(format string, args ...interface{}) {
+		errs = append(errs, fmt.Sprintf(format, args...))
+	}
+
+	if c.Env != Development && c.Env != Production {
+		bad("env %q must be %s or %s", c.Env, Development, Production)
+	}
+	if c.Store != "postgres" && c.Store != "memory" {
+		bad("store %q must be postgres or memory", c.Store)
+	}
+	if c.Store == "postgres" && c.DB.URL == "" && (c.DB.Host == "" || c.DB.Name == "" || c.DB.Port <= 0) {
+		bad("db: url or host, port and name are required")
+	}
+	if c.DB.MaxOpenConns < 0 || c.DB.MaxIdleConns < 0 {
+		bad("db: pool sizes must not be negative")
+	}
+	if c.DB.MaxOpenConns > 0 && c.DB.MaxIdleConns > c.DB.MaxOpenConns {
+		bad("db: max_idle_conns (%d) exceeds max_open_conns (%d)", c.DB.MaxIdleConns, c.DB.MaxOpenConns)
+	}
+	for _, d := range []struct {
+		name string
+		d    Duration
+	}{
+		{"db.conn_max_lifetime", c.DB.ConnMaxLifetime},
+		{"db.conn_max_idle_time", c.DB.ConnMaxIdleTime},
+		{"db.query_timeout", c.DB.QueryTimeout},
+		{"http.read_timeout", c.HTTP.ReadTimeout},
+		{"http.read_header_timeout", c.HTTP.ReadHeaderTimeout},
+		{"http.write_timeout", c.HTTP.WriteTimeout},
+		{"http.idle_timeout", c.HTTP.IdleTimeout},
+		{"http.shutdown_timeout", c.HTTP.ShutdownTimeout},
+	} {
+		if d.d < 0 {
+			bad("%s must not be negative", d.name)
+		}
+	}
+	if c.HTTP.Addr == "" {
+		bad("http.addr is required")
+	}
+	if (c.HTTP.TLSCertFile == "") != (c.HTTP.TLSKeyFile == "") {
+		bad("http: tls_cert_file and tls_key_file must be set together")
+	}
+	for _, f := range []string{c.HTTP.TLSCertFile, c.HTTP.TLSKeyFile} {
+		if f == "" {
+			continue
+		}
+		if _, err := os.Stat(f); err != nil {
+			bad("http: %v", err)
+		}
+	}
+	if _, err := time.LoadLocation(c.Voting.Timezone); err != nil || c.Voting.Timezone == "" {
+		bad("voting.timezone %q is not a known timezone", c.Voting.Timezone)
+	}
+	if c.Secrets.JWT == "" {
+		bad("secrets.jwt is required")
+	}
+	if c.Log.Format != "json" && c.Log.Format != "text" {
+		bad("log.format %q must be json or text", c.Log.Format)
+	}
+	if c.Templates.Dir != "" {
+		if fi, err := os.Stat(c.Templates.Dir); err != nil || !fi.IsDir() {
+			bad("templates.dir %q is not a directory", c.Templates.Dir)
+		}
+	}
+	if c.Metrics.Addr != "" && c.Metrics.Addr == c.HTTP.Addr {
+		bad("metrics.addr must differ from http.addr; set metrics.token to serve /metrics there")
+	}
+	switch strings.ToLower(c.Log.Level) {
+	case "debug", "info", "warn", "error":
+	default:
+		bad("log.level %q must be debug, info, warn or error", c.Log.Level)
+	}
+
+	if c.Env == Production {
+		if c.Secrets.JWT == DefaultJWTSecret || len(c.Secrets.JWT) < 32 || isPlaceholder(c.Secrets.JWT) {
+			bad("production: secrets.jwt (JWT_SECRET) must be set to a random value of at least 32 characters")
+		}
+		if c.Store == "postgres" && c.DB.URL == "" && c.DB.Password == DefaultDBPassword {
+			bad("production: db.password (PG_PASSWORD) is the default")
+		}
+		if !c.Cookies.Secure {
+			bad("production: cookies.secure (COOKIE_SECURE) must be true")
+		}
+		if c.Store == "memory" {
+			bad("production: store must be postgres")
+		}
+		if c.Templates.Reload {
+			bad("production: templates.reload (TEMPLATE_RELOAD) must be false")
+		}
+	}
+
+	if len(errs) == 0 {
+		return nil
+	}
+	return errors.New("invalid configuration:\n  " + strings.Join(errs, "\n  "))
+}
+
+// placeholderWords mark the sample values of .env.prod and similar
+// templates; a real random secret contains none of them.
+var placeholderWords = []string{"replace", "changeme", "change_me", "change-me", "placeholder", "example", "your_secret", "your-secret"}
+
+// isPlaceholder reports whether a secret was copied from a template without
+// being replaced.
+func isPlaceholder(secret string) bool {
+	s := strings.ToLower(secret)
+	for _, w := range placeholderWords {
+		if strings.Contains(s, w) {
+			return true
+		}
+	}
+	return false
+}
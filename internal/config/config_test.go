@@ -0,0 +1,108 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDefaultIsValid(t *testing.T) {
+	if err := Default().Validate(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestEnvOverridesFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	file := `{"store": "memory", "db": {"query_timeout": "2s", "max_open_conns": 10, "max_idle_conns": 5}, "http": {"addr": ":9000"}}`
+	if err := os.WriteFile(path, []byte(file), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("HTTP_ADDR", ":9090")
+	t.Setenv("DB_TIMEOUT", "")
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Store != "memory" || time.Duration(cfg.DB.QueryTimeout) != 2*time.Second || cfg.DB.MaxOpenConns != 10 {
+		t.Fatalf("file not applied: %+v", cfg)
+	}
+	if cfg.HTTP.Addr != ":9090" {
+		t.Fatalf("env did not override the file: addr = %q", cfg.HTTP.Addr)
+	}
+}
+
+func TestLoadRejectsBadValues(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(path, []byte(`{"htttp": {}}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(path); err == nil || !strings.Contains(err.Error(), "htttp") {
+		t.Fatalf("unknown key accepted: %v", err)
+	}
+
+	t.Setenv("DB_TIMEOUT", "soon")
+	if _, err := Load(""); err == nil || !strings.Contains(err.Error(), "DB_TIMEOUT") {
+		t.Fatalf("bad duration accepted: %v", err)
+	}
+}
+
+func TestProductionRefusesDefaults(t *testing.T) {
+	cfg := Default()
+	cfg.Env = Production
+	err := cfg.Validate()
+	if err == nil {
+		t.Fatal("production accepted the development defaults")
+	}
+	for _, want := range []string{"JWT_SECRET", "PG_PASSWORD", "COOKIE_SECURE"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error does not mention %s: %v", want, err)
+		}
+	}
+
+	cfg.Secrets.JWT = strings.Repeat("k", 32)
+	cfg.DB.URL = "postgresql://app:secret@db/votacao"
+	cfg.Cookies.Secure = true
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("production config rejected: %v", err)
+	}
+	cfg.Templates.Reload = true
+	if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), "TEMPLATE_RELOAD") {
+		t.Fatalf("production accepted template reloading: %v", err)
+	}
+	cfg.Templates.Reload = false
+	for _, secret := range []string{"replace_with_strong_random_secret", "CHANGEME-CHANGEME-CHANGEME-CHANGEME", ""} {
+		cfg.Secrets.JWT = secret
+		if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), "JWT_SECRET") {
+			t.Errorf("production accepted JWT_SECRET %q: %v", secret, err)
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	for _, tc := range []struct {
+		edit func(*Config)
+		want string
+	}{
+		{func(c *Config) { c.Env = "staging" }, "env"},
+		{func(c *Config) { c.Store = "sqlite" }, "store"},
+		{func(c *Config) { c.DB.MaxIdleConns = 50 }, "max_idle_conns"},
+		{func(c *Config) { c.HTTP.WriteTimeout = Duration(-time.Second) }, "http.write_timeout"},
+		{func(c *Config) { c.Voting.Timezone = "Mars/Olympus" }, "timezone"},
+		{func(c *Config) { c.Secrets.JWT = "" }, "secrets.jwt"},
+		{func(c *Config) { c.HTTP.TLSCertFile = "cert.pem" }, "tls_key_file"},
+		{func(c *Config) { c.Log.Level = "verbose" }, "log.level"},
+		{func(c *Config) { c.Metrics.Addr = c.HTTP.Addr }, "metrics.addr"},
+		{func(c *Config) { c.Templates.Dir = "/no/such/dir" }, "templates.dir"},
+		{func(c *Config) { c.HTTP.TLSCertFile, c.HTTP.TLSKeyFile = "missing.pem", "missing.key" }, "missing.pem"},
+	} {
+		cfg := Default()
+		tc.edit(&cfg)
+		if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Errorf("want error about %s, got %v", tc.want, err)
+		}
+	}
+}
@@ -4,8 +4,6 @@ import (
 	"database/sql"
 	"embed"
 	"fmt"
-	"io/fs"
-	"sort"
 	"time"
 
 	_ "github.com/lib/pq"
@@ -14,16 +12,40 @@ import (
 //go:embed migrations/*.sql
 var migrationsFS embed.FS
 
-// Open opens a Postgres connection and runs migrations to ensure the schema exists.
-func Open(dsn string) (*sql.DB, error) {
-	db, err := sql.Open("postgres", dsn)
-	if err != nil {
-		return nil, fmt.Errorf("sql.Open: %w", err)
+// Pool sizes and recycles the connection pool; zero fields keep the
+// database/sql defaults (unlimited open connections, 2 idle, no lifetime).
+type Pool struct {
+	MaxOpenConns    int
+	MaxIdleConns    int
+	ConnMaxLifetime time.Duration
+	ConnMaxIdleTime time.Duration
+}
+
+// apply sets the non-zero fields on db.
+func (p Pool) apply(db *sql.DB) {
+	if p.MaxOpenConns > 0 {
+		db.SetMaxOpenConns(p.MaxOpenConns)
 	}
-	if err := db.Ping(); err != nil {
-		db.Close()
-		return nil, fmt.Errorf("db.Ping: %w", err)
+	if p.MaxIdleConns > 0 {
+		db.SetMaxIdleConns(p.MaxIdleConns)
+	}
+	if p.ConnMaxLifetime > 0 {
+		db.SetConnMaxLifetime(p.ConnMaxLifetime)
 	}
+	if p.ConnMaxIdleTime > 0 {
+		db.SetConnMaxIdleTime(p.ConnMaxIdleTime)
+	}
+}
+
+// Open opens a Postgres connection pool configured by pool and applies
+// pending migrations. It fails when an applied migration no longer matches
+// its embedded file.
+func Open(dsn string, pool Pool) (*sql.DB, error) {
+	db, err := Connect(dsn)
+	if err != nil {
+		return nil, err
+	}
+	pool.apply(db)
 	if err := runMigrations(db); err != nil {
 		db.Close()
 		return nil, err
@@ -31,50 +53,25 @@ func Open(dsn string) (*sql.DB, error) {
 	return db, nil
 }
 
-// runMigrations executes embedded SQL migration files located in migrations/*.sql
-// in alphabetical order and records applied migrations in schema_migrations table.
-func runMigrations(db *sql.DB) error {
-	// ensure schema_migrations table exists
-	if _, err := db.Exec(`CREATE TABLE IF NOT EXISTS schema_migrations (version TEXT PRIMARY KEY, applied_at TIMESTAMPTZ NOT NULL)`); err != nil {
-		return fmt.Errorf("create schema_migrations: %w", err)
-	}
-
-	// read migration files
-	entries, err := fs.ReadDir(migrationsFS, "migrations")
+// Connect opens and pings a Postgres connection without touching the schema.
+func Connect(dsn string) (*sql.DB, error) {
+	db, err := sql.Open("postgres", dsn)
 	if err != nil {
-		return fmt.Errorf("read migrations: %w", err)
+		return nil, fmt.Errorf("sql.Open: %w", err)
 	}
-	names := make([]string, 0, len(entries))
-	for _, e := range entries {
-		if !e.IsDir() {
-			names = append(names, e.Name())
-		}
+	if err := db.Ping(); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("db.Ping: %w", err)
 	}
-	sort.Strings(names)
+	return db, nil
+}
 
-	for _, name := range names {
-		// check if applied
-		var exists bool
-		err := db.QueryRow("SELECT EXISTS(SELECT 1 FROM schema_migrations WHERE version=$1)", name).Scan(&exists)
-		if err != nil {
-			return fmt.Errorf("check migration %s: %w", name, err)
-		}
-		if exists {
-			continue
-		}
-		// read file
-		b, err := migrationsFS.ReadFile("migrations/" + name)
-		if err != nil {
-			return fmt.Errorf("read migration %s: %w", name, err)
-		}
-		// execute SQL (could contain multiple statements)
-		if _, err := db.Exec(string(b)); err != nil {
-			return fmt.Errorf("apply migration %s: %w", name, err)
-		}
-		// record migration
-		if _, err := db.Exec("INSERT INTO schema_migrations (version, applied_at) VALUES ($1, $2)", name, time.Now().UTC()); err != nil {
-			return fmt.Errorf("record migration %s: %w", name, err)
-		}
+// runMigrations applies the embedded migrations in migrations/*.sql (see Migrator).
+func runMigrations(db *sql.DB) error {
+	m, err := NewMigrator(db)
+	if err != nil {
+		return err
 	}
-	return nil
+	_, err = m.Up()
+	return err
 }
@@ -0,0 +1,345 @@
+package db
+
+import (
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Migration is one embedded schema change. The up script lives in
+// migrations/<version>.sql and the optional down script in
+// migrations/<version>.down.sql.
+type Migration struct {
+	Version  string // file name without extension, e.g. "002_add_ceremonies"
+	Up       string
+	Down     string
+	HasDown  bool
+	Checksum string // sha256 of the up script
+}
+
+// MigrationStatus describes a migration as seen by the database.
+type MigrationStatus struct {
+	Version   string
+	Applied   bool
+	AppliedAt time.Time
+	// Modified is true when the applied checksum differs from the embedded file.
+	Modified bool
+}
+
+// ErrChecksumMismatch is returned when an applied migration no longer matches
+// the embedded file (someone edited a migration after it ran).
+var ErrChecksumMismatch = errors.New("migration checksum mismatch")
+
+// legacyVersions were applied by `make migrate` with psql from the old
+// top-level migrations/ folder, outside schema_migrations. When an existing
+// database is upgraded and already has their tables they are recorded as
+// applied instead of being re-run (008 wipes the nominee slate).
+var legacyVersions = []string{
+	"007_legacy_schema",
+	"008_insert_oscar_2026_data",
+	"009_add_missing_nominees",
+	"010_fix_animated_international_nominees",
+	"011_fix_casting_nominees",
+	"012_fix_actress_supporting_role",
+	"013_fix_live_action_short_film",
+	"014_fix_animated_short_film",
+	"015_fix_original_song",
+	"016_fix_documentary_short_film",
+	"017_fix_documentary_feature_film",
+}
+
+// loadMigrations reads the migration files in dir of fsys, sorted by version.
+func loadMigrations(fsys fs.FS, dir string) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return nil, fmt.Errorf("read migrations: %w", err)
+	}
+	byVersion := make(map[string]*Migration)
+	downs := make(map[string]string)
+	for _, e := range entries {
+		name := e.Name()
+		if e.IsDir() || !strings.HasSuffix(name, ".sql") {
+			continue
+		}
+		b, err := fs.ReadFile(fsys, dir+"/"+name)
+		if err != nil {
+			return nil, fmt.Errorf("read migration %s: %w", name, err)
+		}
+		if v, ok := strings.CutSuffix(name, ".down.sql"); ok {
+			downs[v] = string(b)
+			continue
+		}
+		v := strings.TrimSuffix(name, ".sql")
+		sum := sha256.Sum256(b)
+		byVersion[v] = &Migration{Version: v, Up: string(b), Checksum: hex.EncodeToString(sum[:])}
+	}
+	for v, down := range downs {
+		m, ok := byVersion[v]
+		if !ok {
+			return nil, fmt.Errorf("down migration %s.down.sql has no up migration", v)
+		}
+		m.Down, m.HasDown = down, true
+	}
+	out := make([]Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		out = append(out, *m)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Version < out[j].Version })
+	return out, nil
+}
+
+// Migrator applies and rolls back the embedded migrations, recording each
+// applied version and its checksum in schema_migrations.
+type Migrator struct {
+	db         *sql.DB
+	migrations []Migration
+}
+
+// NewMigrator returns a Migrator for the embedded migrations.
+func NewMigrator(db *sql.DB) (*Migrator, error) {
+	ms, err := loadMigrations(migrationsFS, "migrations")
+	if err != nil {
+		return nil, err
+	}
+	return &Migrator{db: db, migrations: ms}, nil
+}
+
+// init creates schema_migrations, or upgrades the table written by the
+// previous runner (file names as versions, no checksums).
+func (m *Migrator) init() error {
+	var exists bool
+	if err := m.db.QueryRow("SELECT to_regclass('schema_migrations') IS NOT NULL").Scan(&exists); err != nil {
+		return fmt.Errorf("check schema_migrations: %w", err)
+	}
+	if !exists {
+		if _, err := m.db.Exec(`CREATE TABLE schema_migrations (version TEXT PRIMARY KEY, checksum TEXT NOT NULL, applied_at TIMESTAMPTZ NOT NULL)`); err != nil {
+			return fmt.Errorf("create schema_migrations: %w", err)
+		}
+		return nil
+	}
+	var hasChecksum bool
+	if err := m.db.QueryRow(`SELECT EXISTS(SELECT 1 FROM information_schema.columns WHERE table_name = 'schema_migrations' AND column_name = 'checksum')`).Scan(&hasChecksum); err != nil {
+		return fmt.Errorf("check schema_migrations: %w", err)
+	}
+	if hasChecksum {
+		return nil
+	}
+	return m.upgradeLegacy()
+}
+
+// upgradeLegacy converts schema_migrations from the old runner in one
+// transaction: versions lose their ".sql" suffix, rows get the checksum of the
+// embedded file, and the migrations formerly applied by `make migrate` are
+// recorded when their tables already exist.
+func (m *Migrator) upgradeLegacy() error {
+	tx, err := m.db.Begin()
+	if err != nil {
+		return fmt.Errorf("begin: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`ALTER TABLE schema_migrations ADD COLUMN checksum TEXT`); err != nil {
+		return fmt.Errorf("add checksum column: %w", err)
+	}
+	if _, err := tx.Exec(`UPDATE schema_migrations SET version = left(version, -4) WHERE version LIKE '%.sql'`); err != nil {
+		return fmt.Errorf("rename versions: %w", err)
+	}
+	for _, mig := range m.migrations {
+		if _, err := tx.Exec(`UPDATE schema_migrations SET checksum = $1 WHERE version = $2`, mig.Checksum, mig.Version); err != nil {
+			return fmt.Errorf("backfill checksum %s: %w", mig.Version, err)
+		}
+	}
+	var legacyApplied bool
+	if err := tx.QueryRow("SELECT to_regclass('winners') IS NOT NULL").Scan(&legacyApplied); err != nil {
+		return fmt.Errorf("check winners: %w", err)
+	}
+	if legacyApplied {
+		legacy := make(map[string]bool, len(legacyVersions))
+		for _, v := range legacyVersions {
+			legacy[v] = true
+		}
+		now := time.Now().UTC()
+		for _, mig := range m.migrations {
+			if !legacy[mig.Version] {
+				continue
+			}
+			if _, err := tx.Exec(`INSERT INTO schema_migrations (version, checksum, applied_at) VALUES ($1, $2, $3) ON CONFLICT (version) DO NOTHING`, mig.Version, mig.Checksum, now); err != nil {
+				return fmt.Errorf("record legacy migration %s: %w", mig.Version, err)
+			}
+		}
+	}
+	var unknown int
+	if err := tx.QueryRow(`SELECT count(*) FROM schema_migrations WHERE checksum IS NULL`).Scan(&unknown); err != nil {
+		return fmt.Errorf("check versions: %w", err)
+	}
+	if unknown > 0 {
+		return fmt.Errorf("schema_migrations has %d version(s) unknown to this build", unknown)
+	}
+	if _, err := tx.Exec(`ALTER TABLE schema_migrations ALTER COLUMN checksum SET NOT NULL`); err != nil {
+		return fmt.Errorf("checksum not null: %w", err)
+	}
+	return tx.Commit()
+}
+
+// applied returns version -> (checksum, applied_at) for applied migrations.
+func (m *Migrator) applied() (map[string]appliedRow, error) {
+	rows, err := m.db.Query("SELECT version, checksum, applied_at FROM schema_migrations")
+	if err != nil {
+		return nil, fmt.Errorf("list schema_migrations: %w", err)
+	}
+	defer rows.Close()
+	out := make(map[string]appliedRow)
+	for rows.Next() {
+		var v string
+		var r appliedRow
+		if err := rows.Scan(&v, &r.checksum, &r.at); err != nil {
+			return nil, err
+		}
+		out[v] = r
+	}
+	return out, rows.Err()
+}
+
+type appliedRow struct {
+	checksum string
+	at       time.Time
+}
+
+// Status reports every embedded migration and whether it has been applied.
+func (m *Migrator) Status() ([]MigrationStatus, error) {
+	if err := m.init(); err != nil {
+		return nil, err
+	}
+	applied, err := m.applied()
+	if err != nil {
+		return nil, err
+	}
+	out := make([]MigrationStatus, 0, len(m.migrations))
+	for _, mig := range m.migrations {
+		st := MigrationStatus{Version: mig.Version}
+		if r, ok := applied[mig.Version]; ok {
+			st.Applied, st.AppliedAt = true, r.at
+			st.Modified = r.checksum != mig.Checksum
+		}
+		out = append(out, st)
+	}
+	return out, nil
+}
+
+// Verify returns ErrChecksumMismatch when an applied migration differs from
+// its embedded file.
+func (m *Migrator) Verify() error {
+	st, err := m.Status()
+	if err != nil {
+		return err
+	}
+	var modified []string
+	for _, s := range st {
+		if s.Modified {
+			modified = append(modified, s.Version)
+		}
+	}
+	if len(modified) > 0 {
+		return fmt.Errorf("%w: %s", ErrChecksumMismatch, strings.Join(modified, ", "))
+	}
+	return nil
+}
+
+// Up verifies the applied migrations and applies the pending ones in order,
+// each in its own transaction. It returns the versions applied.
+func (m *Migrator) Up() ([]string, error) {
+	if err := m.Verify(); err != nil {
+		return nil, err
+	}
+	applied, err := m.applied()
+	if err != nil {
+		return nil, err
+	}
+	var done []string
+	for _, mig := range m.migrations {
+		if _, ok := applied[mig.Version]; ok {
+			continue
+		}
+		if err := m.apply(mig); err != nil {
+			return done, err
+		}
+		done = append(done, mig.Version)
+	}
+	return done, nil
+}
+
+// Down rolls back the most recently applied migration and returns its
+// version, or "" when nothing is applied.
+func (m *Migrator) Down() (string, error) {
+	if err := m.Verify(); err != nil {
+		return "", err
+	}
+	applied, err := m.applied()
+	if err != nil {
+		return "", err
+	}
+	for i := len(m.migrations) - 1; i >= 0; i-- {
+		mig := m.migrations[i]
+		if _, ok := applied[mig.Version]; !ok {
+			continue
+		}
+		if err := m.revert(mig); err != nil {
+			return "", err
+		}
+		return mig.Version, nil
+	}
+	return "", nil
+}
+
+// Redo rolls back the most recently applied migration and applies it again.
+func (m *Migrator) Redo() (string, error) {
+	v, err := m.Down()
+	if err != nil || v == "" {
+		return v, err
+	}
+	for _, mig := range m.migrations {
+		if mig.Version == v {
+			return v, m.apply(mig)
+		}
+	}
+	return v, nil
+}
+
+func (m *Migrator) apply(mig Migration) error {
+	tx, err := m.db.Begin()
+	if err != nil {
+		return fmt.Errorf("begin %s: %w", mig.Version, err)
+	}
+	defer tx.Rollback()
+	if _, err := tx.Exec(mig.Up); err != nil {
+		return fmt.Errorf("apply migration %s: %w", mig.Version, err)
+	}
+	if _, err := tx.Exec("INSERT INTO schema_migrations (version, checksum, applied_at) VALUES ($1, $2, $3)", mig.Version, mig.Checksum, time.Now().UTC()); err != nil {
+		return fmt.Errorf("record migration %s: %w", mig.Version, err)
+	}
+	return tx.Commit()
+}
+
+func (m *Migrator) revert(mig Migration) error {
+	if !mig.HasDown {
+		return fmt.Errorf("migration %s has no down migration", mig.Version)
+	}
+	tx, err := m.db.Begin()
+	if err != nil {
+		return fmt.Errorf("begin %s: %w", mig.Version, err)
+	}
+	defer tx.Rollback()
+	if _, err := tx.Exec(mig.Down); err != nil {
+		return fmt.Errorf("revert migration %s: %w", mig.Version, err)
+	}
+	if _, err := tx.Exec("DELETE FROM schema_migrations WHERE version=$1", mig.Version); err != nil {
+		return fmt.Errorf("unrecord migration %s: %w", mig.Version, err)
+	}
+	return tx.Commit()
+}
@@ -0,0 +1,56 @@
+package db
+
+import (
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestLoadMigrations(t *testing.T) {
+	fsys := fstest.MapFS{
+		"m/002_b.sql":      {Data: []byte("CREATE TABLE b ();")},
+		"m/001_a.sql":      {Data: []byte("CREATE TABLE a ();")},
+		"m/001_a.down.sql": {Data: []byte("DROP TABLE a;")},
+		"m/README":         {Data: []byte("ignored")},
+	}
+	ms, err := loadMigrations(fsys, "m")
+	if err != nil {
+		t.Fatalf("loadMigrations: %v", err)
+	}
+	if len(ms) != 2 || ms[0].Version != "001_a" || ms[1].Version != "002_b" {
+		t.Fatalf("unexpected migrations %+v", ms)
+	}
+	if !ms[0].HasDown || ms[0].Down != "DROP TABLE a;" || ms[1].HasDown {
+		t.Fatalf("down scripts not paired: %+v", ms)
+	}
+	if len(ms[0].Checksum) != 64 || ms[0].Checksum == ms[1].Checksum {
+		t.Fatalf("bad checksums %q %q", ms[0].Checksum, ms[1].Checksum)
+	}
+}
+
+func TestLoadMigrationsOrphanDown(t *testing.T) {
+	fsys := fstest.MapFS{"m/003_c.down.sql": {Data: []byte("DROP TABLE c;")}}
+	if _, err := loadMigrations(fsys, "m"); err == nil || !strings.Contains(err.Error(), "003_c") {
+		t.Fatalf("expected orphan down error, got %v", err)
+	}
+}
+
+// The embedded set must load and every legacy version must exist.
+func TestEmbeddedMigrations(t *testing.T) {
+	ms, err := loadMigrations(migrationsFS, "migrations")
+	if err != nil {
+		t.Fatalf("loadMigrations: %v", err)
+	}
+	have := make(map[string]bool)
+	for _, m := range ms {
+		have[m.Version] = true
+		if !m.HasDown {
+			t.Errorf("%s has no down migration", m.Version)
+		}
+	}
+	for _, v := range legacyVersions {
+		if !have[v] {
+			t.Errorf("legacy version %s is not embedded", v)
+		}
+	}
+}
@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS votes;
+DROP TABLE IF EXISTS users;
+DROP TABLE IF EXISTS nominees;
+DROP TABLE IF EXISTS movies;
+DROP TABLE IF EXISTS categories;
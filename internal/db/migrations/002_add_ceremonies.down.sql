@@ -0,0 +1,9 @@
+-- Categories of other ceremonies cannot survive the global name constraint.
+DELETE FROM categories WHERE ceremony_id <> (SELECT id FROM ceremonies WHERE year = 2026);
+
+DROP INDEX IF EXISTS categories_ceremony_id_idx;
+ALTER TABLE categories DROP CONSTRAINT IF EXISTS categories_ceremony_name_unique;
+ALTER TABLE categories ADD CONSTRAINT categories_name_unique UNIQUE (name);
+ALTER TABLE categories DROP COLUMN IF EXISTS ceremony_id;
+
+DROP TABLE IF EXISTS ceremonies;
@@ -0,0 +1,30 @@
+-- Ceremonies let the pool run several editions (2026, 2027, ...) side by side.
+-- Categories are owned by a ceremony; nominees, votes and winners are scoped
+-- through their category.
+CREATE TABLE IF NOT EXISTS ceremonies (
+  id uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+  year integer NOT NULL,
+  name text NOT NULL,
+  deadline timestamptz NOT NULL,
+  status text NOT NULL DEFAULT 'upcoming',
+  created_at timestamptz DEFAULT now(),
+  CONSTRAINT ceremonies_year_unique UNIQUE (year),
+  CONSTRAINT ceremonies_status_check CHECK (status IN ('upcoming', 'active', 'archived'))
+);
+
+-- at most one active ceremony
+CREATE UNIQUE INDEX IF NOT EXISTS ceremonies_single_active ON ceremonies (status) WHERE status = 'active';
+
+-- the existing data is the 2026 slate (deadline: 15 March 2026, 19:00 UTC-3)
+INSERT INTO ceremonies (year, name, deadline, status)
+VALUES (2026, 'Oscar 2026', '2026-03-15 19:00:00-03', 'active')
+ON CONFLICT (year) DO NOTHING;
+
+ALTER TABLE categories ADD COLUMN IF NOT EXISTS ceremony_id uuid REFERENCES ceremonies(id) ON DELETE CASCADE;
+UPDATE categories SET ceremony_id = (SELECT id FROM ceremonies WHERE year = 2026) WHERE ceremony_id IS NULL;
+ALTER TABLE categories ALTER COLUMN ceremony_id SET NOT NULL;
+
+-- category names only need to be unique within a ceremony
+ALTER TABLE categories DROP CONSTRAINT IF EXISTS categories_name_unique;
+ALTER TABLE categories ADD CONSTRAINT categories_ceremony_name_unique UNIQUE (ceremony_id, name);
+CREATE INDEX IF NOT EXISTS categories_ceremony_id_idx ON categories (ceremony_id);
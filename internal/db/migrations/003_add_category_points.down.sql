@@ -0,0 +1,2 @@
+ALTER TABLE categories DROP CONSTRAINT IF EXISTS categories_points_check;
+ALTER TABLE categories DROP COLUMN IF EXISTS points;
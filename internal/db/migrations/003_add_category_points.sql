@@ -0,0 +1,9 @@
+-- Scoring weight of each category, used by the score queries instead of a
+-- hardcoded CASE on category names. Organisers can change it through the API.
+ALTER TABLE categories ADD COLUMN IF NOT EXISTS points integer NOT NULL DEFAULT 1;
+ALTER TABLE categories ADD CONSTRAINT categories_points_check CHECK (points >= 0);
+
+-- Keep the previous weighting (Best Picture=3, leading roles=2). Both naming
+-- schemes used by the seed data are covered.
+UPDATE categories SET points = 3 WHERE name = 'Best Picture';
+UPDATE categories SET points = 2 WHERE name IN ('Best Actor', 'Best Actress', 'Actor in a Leading Role', 'Actress in a Leading Role');
@@ -0,0 +1,2 @@
+ALTER TABLE categories DROP COLUMN IF EXISTS locked;
+ALTER TABLE categories DROP COLUMN IF EXISTS locks_at;
@@ -0,0 +1,4 @@
+-- Per-category voting locks: during the live ceremony each category is locked
+-- the moment it is announced (locked=true), or at a scheduled time (locks_at).
+ALTER TABLE categories ADD COLUMN IF NOT EXISTS locks_at timestamptz;
+ALTER TABLE categories ADD COLUMN IF NOT EXISTS locked boolean NOT NULL DEFAULT false;
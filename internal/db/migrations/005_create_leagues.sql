@@ -0,0 +1,18 @@
+-- Private leagues: separate pools with their own leaderboard.
+CREATE TABLE IF NOT EXISTS leagues (
+  id uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+  name text NOT NULL,
+  owner_id uuid NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  invite_code text NOT NULL,
+  created_at timestamptz NOT NULL DEFAULT now(),
+  CONSTRAINT leagues_invite_code_unique UNIQUE (invite_code)
+);
+
+CREATE TABLE IF NOT EXISTS league_members (
+  league_id uuid NOT NULL REFERENCES leagues(id) ON DELETE CASCADE,
+  user_id uuid NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  joined_at timestamptz NOT NULL DEFAULT now(),
+  PRIMARY KEY (league_id, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS league_members_user_id_idx ON league_members (user_id);
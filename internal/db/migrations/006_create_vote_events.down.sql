@@ -0,0 +1,3 @@
+DROP TRIGGER IF EXISTS vote_events_no_update ON vote_events;
+DROP FUNCTION IF EXISTS vote_events_forbid_update();
+DROP TABLE IF EXISTS vote_events;
@@ -0,0 +1,25 @@
+-- Append-only history of vote changes, written in the same transaction as the
+-- votes upsert. No FKs to nominees/categories so history survives their edits.
+CREATE TABLE IF NOT EXISTS vote_events (
+  id bigserial PRIMARY KEY,
+  vote_id integer NOT NULL,
+  user_id uuid NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  category_id uuid NOT NULL,
+  nominated_id uuid NOT NULL,
+  previous_nominated_id uuid,
+  action text NOT NULL CHECK (action IN ('created', 'changed')),
+  created_at timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS vote_events_user_id_idx ON vote_events (user_id, created_at);
+
+-- history rows are never rewritten
+CREATE OR REPLACE FUNCTION vote_events_forbid_update() RETURNS trigger AS $$
+BEGIN
+  RAISE EXCEPTION 'vote_events is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS vote_events_no_update ON vote_events;
+CREATE TRIGGER vote_events_no_update BEFORE UPDATE ON vote_events
+  FOR EACH ROW EXECUTE FUNCTION vote_events_forbid_update();
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS winners;
+ALTER TABLE categories DROP COLUMN IF EXISTS sequence_order;
+ALTER TABLE nominees DROP COLUMN IF EXISTS url_image;
+ALTER TABLE users DROP COLUMN IF EXISTS role;
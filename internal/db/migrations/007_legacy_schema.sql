@@ -0,0 +1,22 @@
+-- Schema changes that used to live in the top-level migrations/ folder and
+-- were applied by `make migrate` (002–007 there). All statements are
+-- idempotent so databases that already ran them are unaffected.
+
+-- users.role (user/admin)
+ALTER TABLE users ADD COLUMN IF NOT EXISTS role VARCHAR(20) DEFAULT 'user';
+UPDATE users SET role = 'user' WHERE role IS NULL;
+
+-- nominees.url_image, with the default poster for rows without one
+ALTER TABLE nominees ADD COLUMN IF NOT EXISTS url_image text;
+ALTER TABLE nominees ALTER COLUMN url_image SET DEFAULT 'https://s2-gshow.glbimg.com/KIfsgPzVx8g-zWDxOSGy4llwWLw=/0x0:1080x1182/984x0/smart/filters:strip_icc()/i.s3.glbimg.com/v1/AUTH_e84042ef78cb4708aeebdf1c68c6cbd6/internal_photos/bs/2026/y/S/gpOY25TAizQq9IcyUHeg/theacademy-20260102-150058-1663833045.jpg';
+UPDATE nominees SET url_image = 'https://s2-gshow.glbimg.com/KIfsgPzVx8g-zWDxOSGy4llwWLw=/0x0:1080x1182/984x0/smart/filters:strip_icc()/i.s3.glbimg.com/v1/AUTH_e84042ef78cb4708aeebdf1c68c6cbd6/internal_photos/bs/2026/y/S/gpOY25TAizQq9IcyUHeg/theacademy-20260102-150058-1663833045.jpg'
+WHERE url_image IS NULL OR url_image = '';
+
+CREATE TABLE IF NOT EXISTS winners (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    nominated_id UUID NOT NULL REFERENCES nominees(id) ON DELETE CASCADE,
+    created_at TIMESTAMP DEFAULT NOW(),
+    UNIQUE(nominated_id)
+);
+
+ALTER TABLE categories ADD COLUMN IF NOT EXISTS sequence_order INTEGER DEFAULT 0;
@@ -2,14 +2,20 @@
 -- Delete incorrect nominees from migration 009
 
 DELETE FROM nominees WHERE category_id IN (
-  SELECT id FROM categories WHERE name IN ('Animated Feature Film', 'International Feature Film')
+  SELECT id FROM categories WHERE ceremony_id = (SELECT id FROM ceremonies WHERE year = 2026) AND name IN ('Animated Feature Film', 'International Feature Film')
 );
 
--- Delete incorrect movies added in migration 009 for these categories
-DELETE FROM movies WHERE title IN (
+-- Delete incorrect movies added in migration 009 for these categories, unless
+-- another ceremony nominates them
+DELETE FROM movies m WHERE m.title IN (
   'Flow', 'Inside Out 2', 'Memoir of a Snail', 'Wallace & Gromit: Vengeance Most Fowl', 'The Wild Robot',
   'Emilia Pérez', 'The Girl with the Needle', 'I''m Still Here', 'The Seed of the Sacred Fig', 'Universal Language',
   'Elio', 'The Day the Earth Blew Up: A Looney Tunes Movie', 'Mufasa: The Lion King', 'Moana 2', 'Lord of the Rings: The War of the Rohirrim'
+) AND NOT EXISTS (
+  SELECT 1 FROM nominees n
+  JOIN categories c ON n.category_id = c.id
+  JOIN ceremonies cer ON c.ceremony_id = cer.id
+  WHERE n.movie_id = m.id AND cer.year <> 2026
 );
 
 -- Add correct movies for Animated Feature Film 2026
@@ -18,28 +24,29 @@ INSERT INTO movies (id, title) VALUES
   (gen_random_uuid(), 'Elio'),
   (gen_random_uuid(), 'KPop Demon Hunters'),
   (gen_random_uuid(), 'Little Amélie or the Character of Rain'),
-  (gen_random_uuid(), 'Zootopia 2');
+  (gen_random_uuid(), 'Zootopia 2')
+ON CONFLICT (title) DO NOTHING;
 
 -- Animated Feature Film nominees
 INSERT INTO nominees (id, movie_id, category_id, nominee_name) 
 SELECT gen_random_uuid(), m.id, c.id, 'Arco - Ugo Bienvenu, Félix de Givry, Sophie Mas, Natalie Portman'
-FROM movies m, categories c WHERE m.title = 'Arco' AND c.name = 'Animated Feature Film';
+FROM movies m, categories c WHERE m.title = 'Arco' AND c.ceremony_id = (SELECT id FROM ceremonies WHERE year = 2026) AND c.name = 'Animated Feature Film';
 
 INSERT INTO nominees (id, movie_id, category_id, nominee_name) 
 SELECT gen_random_uuid(), m.id, c.id, 'Elio - Madeline Sharafian, Domee Shi, Adrian Molina, Mary Alice Drumm'
-FROM movies m, categories c WHERE m.title = 'Elio' AND c.name = 'Animated Feature Film';
+FROM movies m, categories c WHERE m.title = 'Elio' AND c.ceremony_id = (SELECT id FROM ceremonies WHERE year = 2026) AND c.name = 'Animated Feature Film';
 
 INSERT INTO nominees (id, movie_id, category_id, nominee_name) 
 SELECT gen_random_uuid(), m.id, c.id, 'KPop Demon Hunters - Maggie Kang, Chris Appelhans, Michelle L.M. Wong'
-FROM movies m, categories c WHERE m.title = 'KPop Demon Hunters' AND c.name = 'Animated Feature Film';
+FROM movies m, categories c WHERE m.title = 'KPop Demon Hunters' AND c.ceremony_id = (SELECT id FROM ceremonies WHERE year = 2026) AND c.name = 'Animated Feature Film';
 
 INSERT INTO nominees (id, movie_id, category_id, nominee_name) 
 SELECT gen_random_uuid(), m.id, c.id, 'Little Amélie or the Character of Rain - Maïlys Vallade, Liane-Cho Han, Nidia Santiago, Henri Magalon'
-FROM movies m, categories c WHERE m.title = 'Little Amélie or the Character of Rain' AND c.name = 'Animated Feature Film';
+FROM movies m, categories c WHERE m.title = 'Little Amélie or the Character of Rain' AND c.ceremony_id = (SELECT id FROM ceremonies WHERE year = 2026) AND c.name = 'Animated Feature Film';
 
 INSERT INTO nominees (id, movie_id, category_id, nominee_name) 
 SELECT gen_random_uuid(), m.id, c.id, 'Zootopia 2 - Jared Bush, Byron Howard, Yvett Merino'
-FROM movies m, categories c WHERE m.title = 'Zootopia 2' AND c.name = 'Animated Feature Film';
+FROM movies m, categories c WHERE m.title = 'Zootopia 2' AND c.ceremony_id = (SELECT id FROM ceremonies WHERE year = 2026) AND c.name = 'Animated Feature Film';
 
 -- Add correct movies for International Feature Film 2026
 INSERT INTO movies (id, title) VALUES
@@ -47,25 +54,26 @@ INSERT INTO movies (id, title) VALUES
   (gen_random_uuid(), 'The Secret Agent (Brazil)'),
   (gen_random_uuid(), 'Sirāt (Spain)'),
   (gen_random_uuid(), 'Kneecap (Ireland)'),
-  (gen_random_uuid(), 'September 5 (Germany)');
+  (gen_random_uuid(), 'September 5 (Germany)')
+ON CONFLICT (title) DO NOTHING;
 
 -- International Feature Film nominees
 INSERT INTO nominees (id, movie_id, category_id, nominee_name) 
 SELECT gen_random_uuid(), m.id, c.id, 'Sentimental Value (Norway)'
-FROM movies m, categories c WHERE m.title = 'Sentimental Value (Norway)' AND c.name = 'International Feature Film';
+FROM movies m, categories c WHERE m.title = 'Sentimental Value (Norway)' AND c.ceremony_id = (SELECT id FROM ceremonies WHERE year = 2026) AND c.name = 'International Feature Film';
 
 INSERT INTO nominees (id, movie_id, category_id, nominee_name) 
 SELECT gen_random_uuid(), m.id, c.id, 'The Secret Agent (Brazil)'
-FROM movies m, categories c WHERE m.title = 'The Secret Agent (Brazil)' AND c.name = 'International Feature Film';
+FROM movies m, categories c WHERE m.title = 'The Secret Agent (Brazil)' AND c.ceremony_id = (SELECT id FROM ceremonies WHERE year = 2026) AND c.name = 'International Feature Film';
 
 INSERT INTO nominees (id, movie_id, category_id, nominee_name) 
 SELECT gen_random_uuid(), m.id, c.id, 'Sirāt (Spain)'
-FROM movies m, categories c WHERE m.title = 'Sirāt (Spain)' AND c.name = 'International Feature Film';
+FROM movies m, categories c WHERE m.title = 'Sirāt (Spain)' AND c.ceremony_id = (SELECT id FROM ceremonies WHERE year = 2026) AND c.name = 'International Feature Film';
 
 INSERT INTO nominees (id, movie_id, category_id, nominee_name) 
 SELECT gen_random_uuid(), m.id, c.id, 'Kneecap (Ireland)'
-FROM movies m, categories c WHERE m.title = 'Kneecap (Ireland)' AND c.name = 'International Feature Film';
+FROM movies m, categories c WHERE m.title = 'Kneecap (Ireland)' AND c.ceremony_id = (SELECT id FROM ceremonies WHERE year = 2026) AND c.name = 'International Feature Film';
 
 INSERT INTO nominees (id, movie_id, category_id, nominee_name) 
 SELECT gen_random_uuid(), m.id, c.id, 'September 5 (Germany)'
-FROM movies m, categories c WHERE m.title = 'September 5 (Germany)' AND c.name = 'International Feature Film';
+FROM movies m, categories c WHERE m.title = 'September 5 (Germany)' AND c.ceremony_id = (SELECT id FROM ceremonies WHERE year = 2026) AND c.name = 'International Feature Film';
@@ -23,35 +23,35 @@ WHERE NOT EXISTS (SELECT 1 FROM movies WHERE title = 'Two People Exchanging Sali
 
 -- Delete existing Live Action Short Film nominees
 DELETE FROM nominees 
-WHERE category_id = (SELECT id FROM categories WHERE name = 'Live Action Short Film');
+WHERE category_id = (SELECT id FROM categories WHERE ceremony_id = (SELECT id FROM ceremonies WHERE year = 2026) AND name = 'Live Action Short Film');
 
 -- Insert correct Live Action Short Film nominees
 -- 1. Butcher's Stain - Meyer Levinson-Blount and Oron Caspi
 INSERT INTO nominees (id, movie_id, category_id, nominee_name)
 SELECT gen_random_uuid(), m.id, c.id, 'Meyer Levinson-Blount and Oron Caspi'
 FROM movies m, categories c 
-WHERE m.title = 'Butcher''s Stain' AND c.name = 'Live Action Short Film';
+WHERE m.title = 'Butcher''s Stain' AND c.ceremony_id = (SELECT id FROM ceremonies WHERE year = 2026) AND c.name = 'Live Action Short Film';
 
 -- 2. A Friend of Dorothy - Lee Knight and James Dean
 INSERT INTO nominees (id, movie_id, category_id, nominee_name)
 SELECT gen_random_uuid(), m.id, c.id, 'Lee Knight and James Dean'
 FROM movies m, categories c 
-WHERE m.title = 'A Friend of Dorothy' AND c.name = 'Live Action Short Film';
+WHERE m.title = 'A Friend of Dorothy' AND c.ceremony_id = (SELECT id FROM ceremonies WHERE year = 2026) AND c.name = 'Live Action Short Film';
 
 -- 3. Jane Austen's Period Drama - Julia Aks and Steve Pinder
 INSERT INTO nominees (id, movie_id, category_id, nominee_name)
 SELECT gen_random_uuid(), m.id, c.id, 'Julia Aks and Steve Pinder'
 FROM movies m, categories c 
-WHERE m.title = 'Jane Austen''s Period Drama' AND c.name = 'Live Action Short Film';
+WHERE m.title = 'Jane Austen''s Period Drama' AND c.ceremony_id = (SELECT id FROM ceremonies WHERE year = 2026) AND c.name = 'Live Action Short Film';
 
 -- 4. The Singers - Sam A. Davis and Jack Piatt
 INSERT INTO nominees (id, movie_id, category_id, nominee_name)
 SELECT gen_random_uuid(), m.id, c.id, 'Sam A. Davis and Jack Piatt'
 FROM movies m, categories c 
-WHERE m.title = 'The Singers' AND c.name = 'Live Action Short Film';
+WHERE m.title = 'The Singers' AND c.ceremony_id = (SELECT id FROM ceremonies WHERE year = 2026) AND c.name = 'Live Action Short Film';
 
 -- 5. Two People Exchanging Saliva - Alexandre Singh and Natalie Musteata
 INSERT INTO nominees (id, movie_id, category_id, nominee_name)
 SELECT gen_random_uuid(), m.id, c.id, 'Alexandre Singh and Natalie Musteata'
 FROM movies m, categories c 
-WHERE m.title = 'Two People Exchanging Saliva' AND c.name = 'Live Action Short Film';
+WHERE m.title = 'Two People Exchanging Saliva' AND c.ceremony_id = (SELECT id FROM ceremonies WHERE year = 2026) AND c.name = 'Live Action Short Film';
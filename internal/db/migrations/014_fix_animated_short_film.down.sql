@@ -0,0 +1,2 @@
+-- Data migration: the seeded/fixed rows are kept on the way down.
+-- Nothing to undo.
@@ -0,0 +1,88 @@
+// Package events is an in-process publish/subscribe broker used to push live
+// results (winners, category locks, leaderboard changes) to browsers over SSE.
+package events
+
+import "sync"
+
+// Event types published by the handlers.
+const (
+	WinnerAdded        = "winner_added"
+	WinnerDeleted      = "winner_deleted"
+	CategoryLocked     = "category_locked"
+	LeaderboardUpdated = "leaderboard_updated"
+)
+
+// Event is a single message; Data is encoded as JSON by the SSE endpoint.
+type Event struct {
+	Type string
+	Data interface{}
+}
+
+// subscriberBuffer is how many events a slow subscriber may lag behind before
+// further events are dropped for it.
+const subscriberBuffer = 16
+
+// Broker fans published events out to all current subscribers.
+type Broker struct {
+	mu     sync.Mutex
+	subs   map[chan Event]struct{}
+	closed bool
+}
+
+func NewBroker() *Broker {
+	return &Broker{subs: make(map[chan Event]struct{})}
+}
+
+// Subscribe registers a new subscriber. The returned function unsubscribes and
+// closes the channel; it must be called when the subscriber goes away. After
+// Close the channel is returned already closed.
+func (b *Broker) Subscribe() (<-chan Event, func()) {
+	ch := make(chan Event, subscriberBuffer)
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		close(ch)
+		return ch, func() {}
+	}
+	b.subs[ch] = struct{}{}
+	return ch, func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		if _, ok := b.subs[ch]; ok {
+			delete(b.subs, ch)
+			close(ch)
+		}
+	}
+}
+
+// Close closes every subscriber's channel and refuses new subscribers, so
+// open streams end when the server shuts down.
+func (b *Broker) Close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.closed = true
+	for ch := range b.subs {
+		delete(b.subs, ch)
+		close(ch)
+	}
+}
+
+// Publish sends the event to every subscriber without blocking; subscribers
+// whose buffer is full miss the event.
+func (b *Broker) Publish(e Event) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for ch := range b.subs {
+		select {
+		case ch <- e:
+		default:
+		}
+	}
+}
+
+// Subscribers returns the number of active subscribers.
+func (b *Broker) Subscribers() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return len(b.subs)
+}
@@ -0,0 +1,52 @@
+package events
+
+import "testing"
+
+func TestBrokerFanOut(t *testing.T) {
+	b := NewBroker()
+	a, unsubA := b.Subscribe()
+	c, unsubC := b.Subscribe()
+	defer unsubC()
+
+	b.Publish(Event{Type: WinnerAdded, Data: "x"})
+	for _, ch := range []<-chan Event{a, c} {
+		e := <-ch
+		if e.Type != WinnerAdded || e.Data != "x" {
+			t.Fatalf("unexpected event %+v", e)
+		}
+	}
+
+	unsubA()
+	unsubA() // idempotent
+	if _, ok := <-a; ok {
+		t.Fatalf("expected closed channel after unsubscribe")
+	}
+	if n := b.Subscribers(); n != 1 {
+		t.Fatalf("expected 1 subscriber got %d", n)
+	}
+}
+
+func TestBrokerPublishDoesNotBlock(t *testing.T) {
+	b := NewBroker()
+	_, unsub := b.Subscribe()
+	defer unsub()
+	for i := 0; i < subscriberBuffer*2; i++ {
+		b.Publish(Event{Type: LeaderboardUpdated})
+	}
+}
+
+func TestBrokerClose(t *testing.T) {
+	b := NewBroker()
+	ch, unsub := b.Subscribe()
+	b.Close()
+	if _, ok := <-ch; ok {
+		t.Fatal("expected closed channel after Close")
+	}
+	unsub() // must not close the channel twice
+	b.Publish(Event{Type: WinnerAdded})
+
+	late, _ := b.Subscribe()
+	if _, ok := <-late; ok {
+		t.Fatal("expected a closed channel when subscribing after Close")
+	}
+}
@@ -0,0 +1,114 @@
+package handler
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// requestLog collects what the access log reports but only inner handlers
+// know: withRoute fills in the route pattern and RequireAuth the user.
+type requestLog struct {
+	route  string
+	userID string
+}
+
+type requestLogKey struct{}
+
+// setLogUser records the authenticated user for the access log line.
+func setLogUser(ctx context.Context, userID string) {
+	if rl, ok := ctx.Value(requestLogKey{}).(*requestLog); ok {
+		rl.userID = userID
+	}
+}
+
+// withRoute records the pattern a request was routed to.
+func withRoute(pattern string, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if rl, ok := r.Context().Value(requestLogKey{}).(*requestLog); ok {
+			rl.route = pattern
+		}
+		next(w, r)
+	}
+}
+
+// statusRecorder remembers the status and size of a response. It forwards
+// Flush, which the event stream needs, and Unwrap for http.ResponseController.
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+	bytes  int
+}
+
+func (s *statusRecorder) WriteHeader(code int) {
+	if s.status == 0 {
+		s.status = code
+	}
+	s.ResponseWriter.WriteHeader(code)
+}
+
+func (s *statusRecorder) Write(b []byte) (int, error) {
+	if s.status == 0 {
+		s.status = http.StatusOK
+	}
+	n, err := s.ResponseWriter.Write(b)
+	s.bytes += n
+	return n, err
+}
+
+func (s *statusRecorder) Flush() {
+	if f, ok := s.ResponseWriter.(http.Flusher); ok {
+		if s.status == 0 {
+			s.status = http.StatusOK
+		}
+		f.Flush()
+	}
+}
+
+func (s *statusRecorder) Unwrap() http.ResponseWriter {
+	return s.ResponseWriter
+}
+
+// withAccessLog logs one line per request once it has been served: method,
+// path, status, size, latency and the authenticated user. Server errors are
+// logged at error level, client errors at warn. The latency is also recorded
+// in votacao_http_request_duration_seconds.
+func withAccessLog(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		rl := &requestLog{}
+		rec := &statusRecorder{ResponseWriter: w}
+		ctx := context.WithValue(r.Context(), requestLogKey{}, rl)
+		next.ServeHTTP(rec, r.WithContext(ctx))
+
+		elapsed := time.Since(start)
+		status := rec.status
+		if status == 0 {
+			status = http.StatusOK
+		}
+		route := rl.route
+		if route == "" {
+			route = unmatchedRoute
+		}
+		httpDuration.Observe(elapsed.Seconds(), r.Method, route, strconv.Itoa(status))
+		level := slog.LevelInfo
+		switch {
+		case status >= 500:
+			level = slog.LevelError
+		case status >= 400:
+			level = slog.LevelWarn
+		}
+		slog.LogAttrs(ctx, level, "request",
+			slog.String("method", r.Method),
+			slog.String("path", r.URL.Path),
+			slog.String("route", route),
+			slog.Int("status", status),
+			slog.Int("bytes", rec.bytes),
+			slog.Float64("latency_ms", float64(elapsed.Microseconds())/1000),
+			slog.String("user_id", rl.userID),
+			slog.String("remote", clientIP(r)),
+		)
+	})
+}
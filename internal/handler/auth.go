@@ -5,7 +5,6 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
-	"os"
 	"strings"
 	"time"
 
@@ -18,22 +17,55 @@ import (
 
 type ctxKey string
 
-const ctxKeyUserID ctxKey = "user_id"
+const (
+	ctxKeyUserID    ctxKey = "user_id"
+	ctxKeyRole      ctxKey = "role"
+	ctxKeySessionID ctxKey = "session_id"
+)
+
+// Values of users.role. Only RoleAdmin may call the admin mutation endpoints.
+const (
+	RoleUser  = "user"
+	RoleAdmin = "admin"
+)
+
+// Access tokens are short-lived; sessions are extended by refreshing.
+const (
+	accessTokenTTL  = 15 * time.Minute
+	refreshTokenTTL = 30 * 24 * time.Hour
+)
 
-// generateToken creates a signed JWT for the given user.
-func (h *Handler) generateToken(u *models.User) (string, error) {
+// generateToken creates a signed access token for the given user and session.
+func (h *Handler) generateToken(u *models.User, sessionID string) (string, error) {
 	if h.jwtSecret == "" {
 		return "", errors.New("jwt secret not configured")
 	}
 	claims := jwt.MapClaims{
 		"sub":      u.ID,
+		"sid":      sessionID,
 		"nickname": u.Nickname,
-		"exp":      time.Now().Add(24 * time.Hour).Unix(),
+		"role":     u.Role,
+		"exp":      time.Now().Add(accessTokenTTL).Unix(),
 	}
 	tok := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
 	return tok.SignedString([]byte(h.jwtSecret))
 }
 
+// setJWTCookie stores the token in the HttpOnly "jwt" cookie read by RequireAuth.
+// The cookie outlives the token: an expired token in the cookie is renewed
+// from the refresh_token cookie.
+func (h *Handler) setJWTCookie(w http.ResponseWriter, tok string) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     "jwt",
+		Value:    tok,
+		Path:     "/",
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
+		Secure:   h.settings.CookieSecure,
+		Expires:  time.Now().Add(refreshTokenTTL),
+	})
+}
+
 // ensureCSRFCookie ensures a non-HttpOnly csrf_token cookie exists and returns its value.
 func (h *Handler) ensureCSRFCookie(w http.ResponseWriter, r *http.Request) string {
 	if c, err := r.Cookie("csrf_token"); err == nil && c.Value != "" {
@@ -46,7 +78,7 @@ func (h *Handler) ensureCSRFCookie(w http.ResponseWriter, r *http.Request) strin
 		Path:     "/",
 		HttpOnly: false,
 		SameSite: http.SameSiteLaxMode,
-		Secure:   os.Getenv("COOKIE_SECURE") == "true",
+		Secure:   h.settings.CookieSecure,
 		Expires:  time.Now().Add(24 * time.Hour),
 	}
 	http.SetCookie(w, cookie)
@@ -72,66 +104,152 @@ func (h *Handler) validateCSRF(r *http.Request) bool {
 	return val == c.Value
 }
 
-// cookieSecure returns whether cookies should be marked Secure based on env var.
-func cookieSecure() bool {
-	return os.Getenv("COOKIE_SECURE") == "true"
-}
-
 // RequireAuth is middleware that enforces Bearer token presence and validity.
-// On success it stores the user id in the request context under ctxKeyUserID.
+// On success it stores the user id, role and session id in the request context.
+// The token's session must still be active, so revoking a session logs it out
+// immediately. Browser requests whose "jwt" cookie holds an expired (or no)
+// token are renewed transparently from the refresh_token cookie.
 func (h *Handler) RequireAuth(next http.HandlerFunc) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		// Accept token from either Authorization header or HttpOnly cookie named "jwt"
-		tokenStr := ""
-		auth := r.Header.Get("Authorization")
-		if auth != "" {
-			parts := strings.SplitN(auth, " ", 2)
-			if len(parts) == 2 && strings.ToLower(parts[0]) == "bearer" {
-				tokenStr = parts[1]
-			}
-		}
-		if tokenStr == "" {
-			if c, err := r.Cookie("jwt"); err == nil {
-				tokenStr = c.Value
-			}
-		}
-		if tokenStr == "" {
-			http.Error(w, "authorization required", http.StatusUnauthorized)
+		ar, err := h.authenticate(w, r)
+		if err != nil {
+			writeErr(w, err)
 			return
 		}
-		token, err := jwt.Parse(tokenStr, func(t *jwt.Token) (interface{}, error) {
-			if _, ok := t.Method.(*jwt.SigningMethodHMAC); !ok {
-				return nil, fmt.Errorf("unexpected signing method")
-			}
-			return []byte(h.jwtSecret), nil
-		})
-		if err != nil || !token.Valid {
-			http.Error(w, "invalid token: "+err.Error(), http.StatusUnauthorized)
+		next(w, ar)
+	}
+}
+
+// withPageUser authenticates page requests like RequireAuth but lets
+// visitors through; pages then find no user in the context. A store error
+// still fails the request.
+func (h *Handler) withPageUser(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ar, err := h.authenticate(w, r)
+		var se *statusError
+		switch {
+		case err == nil:
+			r = ar
+		case !errors.As(err, &se):
+			writeStoreError(w, err)
 			return
 		}
-		claims, ok := token.Claims.(jwt.MapClaims)
-		if !ok {
-			http.Error(w, "invalid token claims", http.StatusUnauthorized)
-			return
+		next(w, r)
+	}
+}
+
+// unauthorized is the 401 authenticate returns for a missing or rejected
+// token; the message never carries the underlying error.
+func unauthorized(msg string) error {
+	return &statusError{http.StatusUnauthorized, codeUnauthorized, msg}
+}
+
+// authenticate does the work of RequireAuth. It returns the request carrying
+// the user, an unauthorized error, or the store error that kept it from
+// checking the session or the user.
+func (h *Handler) authenticate(w http.ResponseWriter, r *http.Request) (*http.Request, error) {
+	// Accept token from either Authorization header or HttpOnly cookie named "jwt"
+	tokenStr := ""
+	fromHeader := false
+	auth := r.Header.Get("Authorization")
+	if auth != "" {
+		parts := strings.SplitN(auth, " ", 2)
+		if len(parts) == 2 && strings.ToLower(parts[0]) == "bearer" {
+			tokenStr = parts[1]
+			fromHeader = true
 		}
-		sub, ok := claims["sub"].(string)
-		if !ok || sub == "" {
-			http.Error(w, "invalid token subject", http.StatusUnauthorized)
-			return
+	}
+	if tokenStr == "" {
+		if c, err := r.Cookie("jwt"); err == nil {
+			tokenStr = c.Value
 		}
+	}
 
-		// ensure the user still exists in the database (tokens may be stale after DB reset)
-		if h.userStore != nil {
-			if u, err := h.userStore.GetByID(sub); err != nil {
-				http.Error(w, "invalid token: "+err.Error(), http.StatusUnauthorized)
-				return
-			} else if u == nil {
-				http.Error(w, "user not found", http.StatusUnauthorized)
+	var sub, sid, role string
+	claims, err := h.parseToken(tokenStr)
+	switch {
+	case err == nil:
+		sub, _ = claims["sub"].(string)
+		sid, _ = claims["sid"].(string)
+		role, _ = claims["role"].(string)
+		if sub == "" {
+			return nil, unauthorized("invalid token subject")
+		}
+		if sid == "" {
+			return nil, unauthorized("invalid token: no session")
+		}
+		sess, err := h.sessionStore.Get(r.Context(), sid)
+		if err != nil {
+			return nil, fmt.Errorf("get session: %w", err)
+		}
+		if sess == nil || sess.UserID != sub || !sess.IsActive(time.Now()) {
+			return nil, unauthorized("session revoked")
+		}
+	case !fromHeader && (tokenStr == "" || errors.Is(err, jwt.ErrTokenExpired)) && hasRefreshCookie(r):
+		sess, u, err := h.refreshFromCookie(w, r)
+		if errors.Is(err, errInvalidRefreshToken) {
+			return nil, unauthorized("invalid token")
+		} else if err != nil {
+			return nil, fmt.Errorf("refresh session: %w", err)
+		}
+		sub, sid, role = u.ID, sess.ID, u.Role
+	case tokenStr == "":
+		return nil, unauthorized("authorization required")
+	default:
+		return nil, unauthorized("invalid token")
+	}
+
+	// ensure the user still exists in the database (tokens may be stale after DB reset).
+	// The stored role wins over the claim so demotions take effect immediately.
+	if h.userStore != nil {
+		if u, err := h.userStore.GetByID(r.Context(), sub); err != nil {
+			return nil, fmt.Errorf("get user: %w", err)
+		} else if u == nil {
+			return nil, unauthorized("user not found")
+		} else {
+			role = u.Role
+		}
+	}
+	ctx := context.WithValue(r.Context(), ctxKeyUserID, sub)
+	ctx = context.WithValue(ctx, ctxKeyRole, role)
+	ctx = context.WithValue(ctx, ctxKeySessionID, sid)
+	setLogUser(ctx, sub)
+	return r.WithContext(ctx), nil
+}
+
+// parseToken validates a signed access token and returns its claims.
+func (h *Handler) parseToken(tokenStr string) (jwt.MapClaims, error) {
+	if tokenStr == "" {
+		return nil, errors.New("no token")
+	}
+	token, err := jwt.Parse(tokenStr, func(t *jwt.Token) (interface{}, error) {
+		if _, ok := t.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, fmt.Errorf("unexpected signing method")
+		}
+		return []byte(h.jwtSecret), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !token.Valid || !ok {
+		return nil, errors.New("invalid token claims")
+	}
+	return claims, nil
+}
+
+// RequireRole returns middleware that authenticates the request like RequireAuth
+// and then requires the user to have the given role. Unauthenticated requests
+// get 401, authenticated users with another role get 403.
+func (h *Handler) RequireRole(role string) func(http.HandlerFunc) http.HandlerFunc {
+	return func(next http.HandlerFunc) http.HandlerFunc {
+		return h.RequireAuth(func(w http.ResponseWriter, r *http.Request) {
+			if got, _ := GetRoleFromContext(r.Context()); got != role {
+				writeError(w, http.StatusForbidden, codeForbidden, "forbidden: "+role+" role required")
 				return
 			}
-		}
-		ctx := context.WithValue(r.Context(), ctxKeyUserID, sub)
-		next(w, r.WithContext(ctx))
+			next(w, r)
+		})
 	}
 }
 
@@ -144,3 +262,23 @@ func GetUserIDFromContext(ctx context.Context) (string, bool) {
 	id, ok := v.(string)
 	return id, ok
 }
+
+// GetSessionIDFromContext returns the session id stored by RequireAuth.
+func GetSessionIDFromContext(ctx context.Context) (string, bool) {
+	v := ctx.Value(ctxKeySessionID)
+	if v == nil {
+		return "", false
+	}
+	id, ok := v.(string)
+	return id, ok
+}
+
+// GetRoleFromContext returns the user role stored by RequireAuth.
+func GetRoleFromContext(ctx context.Context) (string, bool) {
+	v := ctx.Value(ctxKeyRole)
+	if v == nil {
+		return "", false
+	}
+	role, ok := v.(string)
+	return role, ok
+}
@@ -0,0 +1,237 @@
+package handler
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+
+	"votacao/internal/router"
+	"votacao/internal/store"
+)
+
+// deleteParams reads the id (path or ?id=) and the optional ?force= of the
+// delete endpoints.
+func deleteParams(w http.ResponseWriter, r *http.Request) (string, bool, bool) {
+	id := param(r, "id")
+	if id == "" {
+		writeInvalid(w, "id is required", map[string]string{"id": "is required"})
+		return "", false, false
+	}
+	force := false
+	if v := r.URL.Query().Get("force"); v != "" {
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			writeInvalid(w, "force must be true or false", map[string]string{"force": "must be true or false"})
+			return "", false, false
+		}
+		force = b
+	}
+	return id, force, true
+}
+
+// writeDeleteError maps a store Delete error: 404 for a missing row, 409 when
+// votes or winners reference it and force was not set.
+func writeDeleteError(w http.ResponseWriter, what string, err error) {
+	var in *store.InUseError
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		writeError(w, http.StatusNotFound, codeNotFound, what+" not found")
+	case errors.As(err, &in):
+		writeError(w, http.StatusConflict, codeInUse, fmt.Sprintf("%s is %s; pass force=true to delete them too", what, in.Error()))
+	default:
+		writeStoreError(w, err)
+	}
+}
+
+// UpdateMovie handles POST /update_movie with JSON {id, title}.
+func (h *Handler) UpdateMovie(w http.ResponseWriter, r *http.Request) {
+	if !h.validateCSRF(r) {
+		writeError(w, http.StatusForbidden, codeInvalidCSRF, "invalid csrf token")
+		return
+	}
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, codeBadRequest, "failed to read body")
+		return
+	}
+	defer r.Body.Close()
+	var req updateMovieRequest
+	if err := json.Unmarshal(body, &req); err != nil {
+		writeError(w, http.StatusBadRequest, codeInvalidJSON, "invalid json: "+err.Error())
+		return
+	}
+	if id := router.Param(r, "id"); id != "" {
+		req.ID = id
+	}
+	if req.ID == "" || req.Title == "" {
+		writeInvalid(w, "id and title are required", map[string]string{"id": "is required", "title": "is required"})
+		return
+	}
+	m, err := h.movieStore.Get(r.Context(), req.ID)
+	if err != nil {
+		writeStoreError(w, err)
+		return
+	}
+	if m == nil {
+		writeError(w, http.StatusNotFound, codeNotFound, "movie not found")
+		return
+	}
+	m.Title = req.Title
+	if err := h.movieStore.Update(r.Context(), m); err != nil {
+		writeStoreError(w, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(m)
+}
+
+// DeleteMovie handles DELETE /delete_movie?id=[&force=true]. The movie's
+// nominees are deleted with it; force is required when they have votes or a winner.
+func (h *Handler) DeleteMovie(w http.ResponseWriter, r *http.Request) {
+	id, force, ok := deleteParams(w, r)
+	if !ok {
+		return
+	}
+	if !h.validateCSRF(r) {
+		writeError(w, http.StatusForbidden, codeInvalidCSRF, "invalid csrf token")
+		return
+	}
+	if err := h.movieStore.Delete(r.Context(), id, force); err != nil {
+		writeDeleteError(w, "movie", err)
+		return
+	}
+	if force {
+		// the movie may have had winning or voted nominees in the active ceremony
+		if cer, err := h.ceremonyStore.GetActive(r.Context()); err == nil && cer != nil {
+			h.publishLeaderboard(r.Context(), cer.ID)
+		}
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// DeleteCategory handles DELETE /delete_category?id=[&force=true]. The
+// category's nominees are deleted with it; force is required when it has votes
+// or a winner.
+func (h *Handler) DeleteCategory(w http.ResponseWriter, r *http.Request) {
+	id, force, ok := deleteParams(w, r)
+	if !ok {
+		return
+	}
+	if !h.validateCSRF(r) {
+		writeError(w, http.StatusForbidden, codeInvalidCSRF, "invalid csrf token")
+		return
+	}
+	c, err := h.categoryStore.Get(r.Context(), id)
+	if err != nil {
+		writeStoreError(w, err)
+		return
+	}
+	if c == nil {
+		writeError(w, http.StatusNotFound, codeNotFound, "category not found")
+		return
+	}
+	if err := h.categoryStore.Delete(r.Context(), id, force); err != nil {
+		writeDeleteError(w, "category", err)
+		return
+	}
+	if force {
+		h.publishLeaderboard(r.Context(), c.CeremonyID)
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// UpdateNominated handles POST /update_nominated with JSON
+// {id, movie_id?, name?, url_image?}. The category cannot change because
+// votes are keyed by it; delete and re-add the nominee instead.
+func (h *Handler) UpdateNominated(w http.ResponseWriter, r *http.Request) {
+	if !h.validateCSRF(r) {
+		writeError(w, http.StatusForbidden, codeInvalidCSRF, "invalid csrf token")
+		return
+	}
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, codeBadRequest, "failed to read body")
+		return
+	}
+	defer r.Body.Close()
+	var req updateNominatedRequest
+	if err := json.Unmarshal(body, &req); err != nil {
+		writeError(w, http.StatusBadRequest, codeInvalidJSON, "invalid json: "+err.Error())
+		return
+	}
+	if id := router.Param(r, "id"); id != "" {
+		req.ID = id
+	}
+	if req.ID == "" {
+		writeInvalid(w, "id is required", map[string]string{"id": "is required"})
+		return
+	}
+	n, err := h.nominatedStore.Get(r.Context(), req.ID)
+	if err != nil {
+		writeStoreError(w, err)
+		return
+	}
+	if n == nil {
+		writeError(w, http.StatusNotFound, codeNotFound, "nominated not found")
+		return
+	}
+	if req.MovieID != nil {
+		m, err := h.movieStore.Get(r.Context(), *req.MovieID)
+		if err != nil {
+			writeStoreError(w, err)
+			return
+		}
+		if m == nil {
+			writeError(w, http.StatusBadRequest, codeBadRequest, "movie not found")
+			return
+		}
+		n.MovieID = m.ID
+	}
+	if req.Name != nil {
+		if *req.Name == "" {
+			writeInvalid(w, "name cannot be empty", map[string]string{"name": "cannot be empty"})
+			return
+		}
+		n.Name = *req.Name
+	}
+	if req.UrlImage != nil {
+		if *req.UrlImage == "" {
+			writeInvalid(w, "url_image cannot be empty", map[string]string{"url_image": "cannot be empty"})
+			return
+		}
+		n.UrlImage = *req.UrlImage
+	}
+	if err := h.nominatedStore.Update(r.Context(), n); err != nil {
+		writeStoreError(w, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(n)
+}
+
+// DeleteNominated handles DELETE /delete_nominated?id=[&force=true]; force is
+// required when the nominee has votes or is a winner.
+func (h *Handler) DeleteNominated(w http.ResponseWriter, r *http.Request) {
+	id, force, ok := deleteParams(w, r)
+	if !ok {
+		return
+	}
+	if !h.validateCSRF(r) {
+		writeError(w, http.StatusForbidden, codeInvalidCSRF, "invalid csrf token")
+		return
+	}
+	// resolve the ceremony before the row is gone
+	cid := h.ceremonyOfNominated(r.Context(), id)
+	if err := h.nominatedStore.Delete(r.Context(), id, force); err != nil {
+		writeDeleteError(w, "nominated", err)
+		return
+	}
+	if force && cid != "" {
+		h.publishLeaderboard(r.Context(), cid)
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
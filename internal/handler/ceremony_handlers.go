@@ -0,0 +1,127 @@
+package handler
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+
+	"votacao/models"
+)
+
+// currentCeremony returns the ceremony a request is scoped to: the one given
+// by the ceremony_id query parameter, or the active ceremony otherwise.
+// It returns nil if no such ceremony exists.
+func (h *Handler) currentCeremony(r *http.Request) (*models.Ceremony, error) {
+	if id := r.URL.Query().Get("ceremony_id"); id != "" {
+		return h.ceremonyStore.Get(r.Context(), id)
+	}
+	return h.ceremonyStore.GetActive(r.Context())
+}
+
+// requireCeremony resolves the request's ceremony and writes an error response
+// when it cannot be found. Callers should return when ok is false.
+func (h *Handler) requireCeremony(w http.ResponseWriter, r *http.Request) (*models.Ceremony, bool) {
+	cer, err := h.currentCeremony(r)
+	if err != nil {
+		writeStoreError(w, err)
+		return nil, false
+	}
+	if cer == nil {
+		writeError(w, http.StatusNotFound, codeNotFound, "ceremony not found")
+		return nil, false
+	}
+	return cer, true
+}
+
+// ListCeremonies handles GET /ceremonies
+func (h *Handler) ListCeremonies(w http.ResponseWriter, r *http.Request) {
+	out, err := h.ceremonyStore.List(r.Context())
+	if err != nil {
+		writeStoreError(w, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(out)
+}
+
+// GetActiveCeremony handles GET /ceremonies/active and returns the ceremony
+// the pool is currently voting on.
+func (h *Handler) GetActiveCeremony(w http.ResponseWriter, r *http.Request) {
+	cer, err := h.ceremonyStore.GetActive(r.Context())
+	if err != nil {
+		writeStoreError(w, err)
+		return
+	}
+	if cer == nil {
+		writeError(w, http.StatusNotFound, codeNotFound, "no active ceremony")
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(cer)
+}
+
+// AddCeremony accepts POST /add_ceremony with JSON {year, name, deadline} and
+// creates a new (upcoming) ceremony.
+func (h *Handler) AddCeremony(w http.ResponseWriter, r *http.Request) {
+	if !h.validateCSRF(r) {
+		writeError(w, http.StatusForbidden, codeInvalidCSRF, "invalid csrf token")
+		return
+	}
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, codeBadRequest, "failed to read body")
+		return
+	}
+	defer r.Body.Close()
+
+	var c models.Ceremony
+	if err := json.Unmarshal(body, &c); err != nil {
+		writeError(w, http.StatusBadRequest, codeInvalidJSON, "invalid json: "+err.Error())
+		return
+	}
+	if c.Year == 0 || c.Name == "" || c.Deadline.IsZero() {
+		writeInvalid(w, "year, name and deadline are required", map[string]string{"year": "is required", "name": "is required", "deadline": "is required"})
+		return
+	}
+	// new ceremonies never become active implicitly; use /ceremonies/activate
+	c.Status = models.CeremonyUpcoming
+	id, err := h.ceremonyStore.Insert(r.Context(), &c)
+	if err != nil {
+		writeInsertError(w, "year", err)
+		return
+	}
+	c.ID = id
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	_ = json.NewEncoder(w).Encode(c)
+}
+
+// ActivateCeremony handles POST /ceremonies/activate?id=<id>. The given
+// ceremony becomes the active one and the previously active one is archived.
+func (h *Handler) ActivateCeremony(w http.ResponseWriter, r *http.Request) {
+	if !h.validateCSRF(r) {
+		writeError(w, http.StatusForbidden, codeInvalidCSRF, "invalid csrf token")
+		return
+	}
+	id := param(r, "id")
+	if id == "" {
+		writeInvalid(w, "id is required", map[string]string{"id": "is required"})
+		return
+	}
+	cer, err := h.ceremonyStore.Get(r.Context(), id)
+	if err != nil {
+		writeStoreError(w, err)
+		return
+	}
+	if cer == nil {
+		writeError(w, http.StatusNotFound, codeNotFound, "ceremony not found")
+		return
+	}
+	if err := h.ceremonyStore.SetActive(r.Context(), id); err != nil {
+		writeStoreError(w, err)
+		return
+	}
+	cer.Status = models.CeremonyActive
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(cer)
+}
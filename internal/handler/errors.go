@@ -0,0 +1,147 @@
+package handler
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	"votacao/internal/logging"
+	"votacao/internal/store"
+)
+
+// Error codes of the JSON error envelope. They are part of the API: clients
+// switch on them, so existing codes must not change.
+const (
+	codeBadRequest       = "bad_request"
+	codeInvalidJSON      = "invalid_json"
+	codeValidation       = "validation_failed"
+	codeInvalidCursor    = "invalid_cursor"
+	codeUnauthorized     = "unauthorized"
+	codeForbidden        = "forbidden"
+	codeInvalidCSRF      = "invalid_csrf"
+	codeVotingClosed     = "voting_closed"
+	codeNotFound         = "not_found"
+	codeMethodNotAllowed = "method_not_allowed"
+	codeConflict         = "conflict"
+	codeInUse            = "in_use"
+	codeInvalidReference = "invalid_reference"
+	codeTimeout          = "timeout"
+	codeInternal         = "internal"
+)
+
+// apiError is the body of every error response, wrapped as {"error": {...}}.
+// Fields maps request fields to what is wrong with them.
+type apiError struct {
+	Code      string            `json:"code"`
+	Message   string            `json:"message"`
+	Fields    map[string]string `json:"fields,omitempty"`
+	RequestID string            `json:"request_id,omitempty"`
+}
+
+// requestIDHeader carries the request ID; it is set on the response before
+// the handler runs, so error writers without the request can still report it.
+const requestIDHeader = "X-Request-ID"
+
+// withRequestID reuses a sane incoming X-Request-ID or generates one, echoes
+// it on the response and puts it in the request context for the logs.
+func withRequestID(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get(requestIDHeader)
+		if id == "" || len(id) > 64 {
+			b := make([]byte, 8)
+			_, _ = rand.Read(b)
+			id = hex.EncodeToString(b)
+		}
+		w.Header().Set(requestIDHeader, id)
+		next.ServeHTTP(w, r.WithContext(logging.WithRequestID(r.Context(), id)))
+	})
+}
+
+func writeAPIError(w http.ResponseWriter, status int, e apiError) {
+	e.RequestID = w.Header().Get(requestIDHeader)
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(errorResponse{Error: e})
+}
+
+// writeError writes the JSON error envelope.
+func writeError(w http.ResponseWriter, status int, code, message string) {
+	writeAPIError(w, status, apiError{Code: code, Message: message})
+}
+
+// writeInvalid writes a 400 validation_failed error with per-field details.
+func writeInvalid(w http.ResponseWriter, message string, fields map[string]string) {
+	writeAPIError(w, http.StatusBadRequest, apiError{Code: codeValidation, Message: message, Fields: fields})
+}
+
+// writeInternal logs err and writes a 500 that does not reveal it.
+func writeInternal(w http.ResponseWriter, err error) {
+	slog.Error("internal error", "request_id", w.Header().Get(requestIDHeader), "err", err)
+	writeError(w, http.StatusInternalServerError, codeInternal, "internal error")
+}
+
+// statusError is a failure of logic shared by the API and the pages, with the
+// response it maps to; the pages show its message.
+type statusError struct {
+	status  int
+	code    string
+	message string
+}
+
+func (e *statusError) Error() string { return e.message }
+
+// writeErr writes a *statusError as is and anything else as a store error.
+func writeErr(w http.ResponseWriter, err error) {
+	var se *statusError
+	if errors.As(err, &se) {
+		writeError(w, se.status, se.code, se.message)
+		return
+	}
+	writeStoreError(w, err)
+}
+
+// writeStoreError maps a store error to a response: 404 for a missing row,
+// 409 for unique violations and rows still in use, 400 for a bad cursor or a
+// failed check, 503 when the request's DB deadline passed, and a 500 that
+// hides the driver message otherwise.
+func writeStoreError(w http.ResponseWriter, err error) {
+	var in *store.InUseError
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		writeError(w, http.StatusNotFound, codeNotFound, "not found")
+	case errors.As(err, &in):
+		writeError(w, http.StatusConflict, codeInUse, in.Error())
+	case errors.Is(err, store.ErrInvalidCursor):
+		writeError(w, http.StatusBadRequest, codeInvalidCursor, "invalid cursor")
+	case errors.Is(err, context.DeadlineExceeded):
+		writeError(w, http.StatusServiceUnavailable, codeTimeout, "the database did not answer in time")
+	default:
+		switch store.Violation(err) {
+		case store.ErrUniqueViolation:
+			writeError(w, http.StatusConflict, codeConflict, "already exists")
+		case store.ErrForeignKeyViolation:
+			writeError(w, http.StatusConflict, codeInvalidReference, "refers to a missing record or is still referenced")
+		case store.ErrCheckViolation:
+			writeError(w, http.StatusBadRequest, codeValidation, "value out of range")
+		default:
+			writeInternal(w, fmt.Errorf("store: %w", err))
+		}
+	}
+}
+
+// writeInsertError is writeStoreError for inserts whose unique constraint is
+// reported against one request field, e.g. a registered email.
+func writeInsertError(w http.ResponseWriter, field string, err error) {
+	if store.Violation(err) == store.ErrUniqueViolation {
+		writeAPIError(w, http.StatusConflict, apiError{Code: codeConflict, Message: field + " is already taken", Fields: map[string]string{field: "is already taken"}})
+		return
+	}
+	writeStoreError(w, err)
+}
@@ -0,0 +1,101 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"votacao/internal/events"
+	"votacao/internal/store"
+)
+
+// sseHeartbeat keeps idle /events connections from being closed by proxies.
+const sseHeartbeat = 25 * time.Second
+
+// ServeEvents handles GET /events, a Server-Sent Events stream of live results:
+// winner_added, winner_deleted, category_locked and leaderboard_updated.
+func (h *Handler) ServeEvents(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeError(w, http.StatusInternalServerError, codeInternal, "streaming unsupported")
+		return
+	}
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	// the stream outlives the server's write timeout
+	_ = http.NewResponseController(w).SetWriteDeadline(time.Time{})
+	// tell clients how long to wait before reconnecting
+	fmt.Fprint(w, "retry: 3000\n\n")
+	flusher.Flush()
+
+	ch, unsubscribe := h.events.Subscribe()
+	defer unsubscribe()
+	ticker := time.NewTicker(sseHeartbeat)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-ticker.C:
+			fmt.Fprint(w, ": ping\n\n")
+			flusher.Flush()
+		case e, ok := <-ch:
+			if !ok {
+				// the server is shutting down
+				return
+			}
+			b, err := json.Marshal(e.Data)
+			if err != nil {
+				slog.ErrorContext(r.Context(), "events: marshal", "type", e.Type, "err", err)
+				continue
+			}
+			fmt.Fprintf(w, "event: %s\ndata: %s\n\n", e.Type, b)
+			flusher.Flush()
+		}
+	}
+}
+
+// Close ends the open event streams; call it when the server shuts down, as
+// http.Server.Shutdown does not interrupt them.
+func (h *Handler) Close() {
+	h.events.Close()
+}
+
+// publishLeaderboard recomputes the scores of a ceremony and publishes them as
+// a leaderboard_updated event. Errors are logged: the mutation that triggered
+// the update has already succeeded.
+func (h *Handler) publishLeaderboard(ctx context.Context, ceremonyID string) {
+	scores, err := h.voteStore.GetAllScores(ctx, ceremonyID)
+	if err != nil {
+		slog.ErrorContext(ctx, "events: leaderboard", "ceremony_id", ceremonyID, "err", err)
+		return
+	}
+	h.publishScores(ceremonyID, scores)
+}
+
+// publishScores publishes already computed scores as a leaderboard_updated event.
+func (h *Handler) publishScores(ceremonyID string, scores []store.UserScore) {
+	h.events.Publish(events.Event{Type: events.LeaderboardUpdated, Data: struct {
+		CeremonyID string            `json:"ceremony_id"`
+		Scores     []store.UserScore `json:"scores"`
+	}{CeremonyID: ceremonyID, Scores: scores}})
+}
+
+// ceremonyOfNominated returns the ceremony id a nominee belongs to (via its
+// category), or "" if it cannot be resolved.
+func (h *Handler) ceremonyOfNominated(ctx context.Context, nominatedID string) string {
+	n, err := h.nominatedStore.Get(ctx, nominatedID)
+	if err != nil || n == nil {
+		return ""
+	}
+	c, err := h.categoryStore.Get(ctx, n.CategoryID)
+	if err != nil || c == nil {
+		return ""
+	}
+	return c.CeremonyID
+}
@@ -1,14 +1,19 @@
 package handler
 
 import (
+	"context"
 	"encoding/json"
-	"fmt"
-	"html/template"
 	"io"
+	"log/slog"
 	"net/http"
+	"net/url"
 	"sort"
+	"time"
 
+	"votacao/internal/events"
+	"votacao/internal/router"
 	"votacao/internal/store"
+	"votacao/internal/view"
 	"votacao/models"
 )
 
@@ -19,47 +24,67 @@ type Handler struct {
 	userStore      store.UserStore
 	voteStore      store.VoteStore
 	winnerStore    store.WinnerStore
-	nominatedTpl   *template.Template
+	ceremonyStore  store.CeremonyStore
+	leagueStore    store.LeagueStore
+	sessionStore   store.SessionStore
+	events         *events.Broker
+	pages          *view.Set
 	jwtSecret      string
+	settings       Settings
 }
 
-func New(m store.MovieStore, c store.CategoryStore, n store.NominatedStore, u store.UserStore, v store.VoteStore, w store.WinnerStore, tpl *template.Template, jwtSecret string) *Handler {
-	return &Handler{movieStore: m, categoryStore: c, nominatedStore: n, userStore: u, voteStore: v, winnerStore: w, nominatedTpl: tpl, jwtSecret: jwtSecret}
+// Settings are the handler's runtime options. The zero value suits tests and
+// development: no DB deadline, cookies without the Secure flag, deadlines shown
+// in America/Sao_Paulo and no token required to bootstrap the first admin.
+type Settings struct {
+	// DBTimeout bounds the time each request may spend on the stores; the
+	// event stream is exempt.
+	DBTimeout      time.Duration
+	CookieSecure   bool
+	Location       *time.Location
+	BootstrapToken string
+	// MetricsToken, when set, serves GET /metrics to scrapers sending it as
+	// a Bearer token.
+	MetricsToken string
+}
+
+func New(m store.MovieStore, c store.CategoryStore, n store.NominatedStore, u store.UserStore, v store.VoteStore, w store.WinnerStore, cer store.CeremonyStore, l store.LeagueStore, ss store.SessionStore, pages *view.Set, jwtSecret string) *Handler {
+	return &Handler{movieStore: m, categoryStore: c, nominatedStore: n, userStore: u, voteStore: v, winnerStore: w, ceremonyStore: cer, leagueStore: l, sessionStore: ss, events: events.NewBroker(), pages: pages, jwtSecret: jwtSecret}
+}
+
+// Configure replaces the handler's settings; call it before Routes.
+func (h *Handler) Configure(s Settings) {
+	h.settings = s
 }
 
 // AddMovie accepts POST /add_movie with JSON body and inserts into storage.
 func (h *Handler) AddMovie(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodPost {
-		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
-		return
-	}
-
 	// validate CSRF for mutating request
 	if !h.validateCSRF(r) {
-		http.Error(w, "invalid csrf token", http.StatusForbidden)
+		writeError(w, http.StatusForbidden, codeInvalidCSRF, "invalid csrf token")
 		return
 	}
 
 	body, err := io.ReadAll(r.Body)
 	if err != nil {
-		http.Error(w, "failed to read body", http.StatusBadRequest)
+		writeError(w, http.StatusBadRequest, codeBadRequest, "failed to read body")
 		return
 	}
 	defer r.Body.Close()
 
 	var m models.Movie
 	if err := json.Unmarshal(body, &m); err != nil {
-		http.Error(w, "invalid json: "+err.Error(), http.StatusBadRequest)
+		writeError(w, http.StatusBadRequest, codeInvalidJSON, "invalid json: "+err.Error())
 		return
 	}
 	if m.Title == "" {
-		http.Error(w, "title is required", http.StatusBadRequest)
+		writeInvalid(w, "title is required", map[string]string{"title": "is required"})
 		return
 	}
 
-	id, err := h.movieStore.Insert(&m)
+	id, err := h.movieStore.Insert(r.Context(), &m)
 	if err != nil {
-		http.Error(w, "db error: "+err.Error(), http.StatusInternalServerError)
+		writeInsertError(w, "title", err)
 		return
 	}
 	m.ID = id
@@ -70,42 +95,37 @@ func (h *Handler) AddMovie(w http.ResponseWriter, r *http.Request) {
 
 // AddMovies accepts POST /add_movies with a JSON array body and inserts multiple movies.
 func (h *Handler) AddMovies(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodPost {
-		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
-		return
-	}
-
 	if !h.validateCSRF(r) {
-		http.Error(w, "invalid csrf token", http.StatusForbidden)
+		writeError(w, http.StatusForbidden, codeInvalidCSRF, "invalid csrf token")
 		return
 	}
 	body, err := io.ReadAll(r.Body)
 	if err != nil {
-		http.Error(w, "failed to read body", http.StatusBadRequest)
+		writeError(w, http.StatusBadRequest, codeBadRequest, "failed to read body")
 		return
 	}
 	defer r.Body.Close()
 
 	var ms []models.Movie
 	if err := json.Unmarshal(body, &ms); err != nil {
-		http.Error(w, "invalid json: "+err.Error(), http.StatusBadRequest)
+		writeError(w, http.StatusBadRequest, codeInvalidJSON, "invalid json: "+err.Error())
 		return
 	}
 	if len(ms) == 0 {
-		http.Error(w, "empty movie list", http.StatusBadRequest)
+		writeError(w, http.StatusBadRequest, codeBadRequest, "empty movie list")
 		return
 	}
 	// basic validation
 	for i := range ms {
 		if ms[i].Title == "" {
-			http.Error(w, "title is required for each movie", http.StatusBadRequest)
+			writeInvalid(w, "title is required for each movie", map[string]string{"title": "is required"})
 			return
 		}
 	}
 
-	ids, err := h.movieStore.InsertMany(ms)
+	ids, err := h.movieStore.InsertMany(r.Context(), ms)
 	if err != nil {
-		http.Error(w, "db error: "+err.Error(), http.StatusInternalServerError)
+		writeStoreError(w, err)
 		return
 	}
 	// attach IDs to returned objects
@@ -123,40 +143,36 @@ func (h *Handler) AddMovies(w http.ResponseWriter, r *http.Request) {
 	_ = json.NewEncoder(w).Encode(out)
 }
 
-// ListMovies handles GET /movies
+// ListMovies handles GET /movies[?title=<prefix>&limit=&cursor=] and returns
+// one page as {items, next_cursor}.
 func (h *Handler) ListMovies(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodGet {
-		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	p, ok := pageParams(w, r)
+	if !ok {
 		return
 	}
-	out, err := h.movieStore.List()
+	out, next, err := h.movieStore.ListPage(r.Context(), store.MovieFilter{TitlePrefix: r.URL.Query().Get("title")}, p)
 	if err != nil {
-		http.Error(w, "db error: "+err.Error(), http.StatusInternalServerError)
+		writeStoreError(w, err)
 		return
 	}
-	w.Header().Set("Content-Type", "application/json")
-	_ = json.NewEncoder(w).Encode(out)
+	writePage(w, out, next)
 }
 
 // GetMovie handles GET /movies?id=<id>
 func (h *Handler) GetMovie(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodGet {
-		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
-		return
-	}
-	q := r.URL.Query().Get("id")
+	q := param(r, "id")
 	if q == "" {
-		http.Error(w, "id is required", http.StatusBadRequest)
+		writeInvalid(w, "id is required", map[string]string{"id": "is required"})
 		return
 	}
 	// IDs are UUID strings now — pass directly
-	m, err := h.movieStore.Get(q)
+	m, err := h.movieStore.Get(r.Context(), q)
 	if err != nil {
-		http.Error(w, "db error: "+err.Error(), http.StatusInternalServerError)
+		writeStoreError(w, err)
 		return
 	}
 	if m == nil {
-		http.NotFound(w, r)
+		writeError(w, http.StatusNotFound, codeNotFound, "movie not found")
 		return
 	}
 	w.Header().Set("Content-Type", "application/json")
@@ -165,34 +181,42 @@ func (h *Handler) GetMovie(w http.ResponseWriter, r *http.Request) {
 
 // AddCategory accepts POST /add_category with JSON body and inserts into storage.
 func (h *Handler) AddCategory(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodPost {
-		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
-		return
-	}
-
 	if !h.validateCSRF(r) {
-		http.Error(w, "invalid csrf token", http.StatusForbidden)
+		writeError(w, http.StatusForbidden, codeInvalidCSRF, "invalid csrf token")
 		return
 	}
 	body, err := io.ReadAll(r.Body)
 	if err != nil {
-		http.Error(w, "failed to read body", http.StatusBadRequest)
+		writeError(w, http.StatusBadRequest, codeBadRequest, "failed to read body")
 		return
 	}
 	defer r.Body.Close()
 
-	var c models.Category
-	if err := json.Unmarshal(body, &c); err != nil {
-		http.Error(w, "invalid json: "+err.Error(), http.StatusBadRequest)
+	var req categoryRequest
+	if err := json.Unmarshal(body, &req); err != nil {
+		writeError(w, http.StatusBadRequest, codeInvalidJSON, "invalid json: "+err.Error())
 		return
 	}
-	if c.Name == "" {
-		http.Error(w, "name is required", http.StatusBadRequest)
+	if req.Name == "" {
+		writeInvalid(w, "name is required", map[string]string{"name": "is required"})
 		return
 	}
-	id, err := h.categoryStore.Insert(&c)
+	if req.Points != nil && *req.Points < 0 {
+		writeInvalid(w, "points must be >= 0", map[string]string{"points": "must be >= 0"})
+		return
+	}
+	c := req.category()
+	// categories default to the active (or ?ceremony_id=) ceremony
+	if c.CeremonyID == "" {
+		cer, ok := h.requireCeremony(w, r)
+		if !ok {
+			return
+		}
+		c.CeremonyID = cer.ID
+	}
+	id, err := h.categoryStore.Insert(r.Context(), &c)
 	if err != nil {
-		http.Error(w, "db error: "+err.Error(), http.StatusInternalServerError)
+		writeInsertError(w, "name", err)
 		return
 	}
 	c.ID = id
@@ -201,42 +225,62 @@ func (h *Handler) AddCategory(w http.ResponseWriter, r *http.Request) {
 	_ = json.NewEncoder(w).Encode(c)
 }
 
-// AddCategories accepts POST /add_categories with a JSON array body and inserts multiple categories.
-func (h *Handler) AddCategories(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodPost {
-		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
-		return
+// category is the category req creates, with the default points applied
+// only when points is absent.
+func (req categoryRequest) category() models.Category {
+	c := models.Category{CeremonyID: req.CeremonyID, Name: req.Name, SequenceOrder: req.SequenceOrder, Points: store.DefaultCategoryPoints}
+	if req.Points != nil {
+		c.Points = *req.Points
 	}
+	return c
+}
 
+// AddCategories accepts POST /add_categories with a JSON array body and inserts multiple categories.
+func (h *Handler) AddCategories(w http.ResponseWriter, r *http.Request) {
 	if !h.validateCSRF(r) {
-		http.Error(w, "invalid csrf token", http.StatusForbidden)
+		writeError(w, http.StatusForbidden, codeInvalidCSRF, "invalid csrf token")
 		return
 	}
 	body, err := io.ReadAll(r.Body)
 	if err != nil {
-		http.Error(w, "failed to read body", http.StatusBadRequest)
+		writeError(w, http.StatusBadRequest, codeBadRequest, "failed to read body")
 		return
 	}
 	defer r.Body.Close()
 
-	var cs []models.Category
-	if err := json.Unmarshal(body, &cs); err != nil {
-		http.Error(w, "invalid json: "+err.Error(), http.StatusBadRequest)
+	var reqs []categoryRequest
+	if err := json.Unmarshal(body, &reqs); err != nil {
+		writeError(w, http.StatusBadRequest, codeInvalidJSON, "invalid json: "+err.Error())
 		return
 	}
-	if len(cs) == 0 {
-		http.Error(w, "empty category list", http.StatusBadRequest)
+	if len(reqs) == 0 {
+		writeError(w, http.StatusBadRequest, codeBadRequest, "empty category list")
 		return
 	}
-	for i := range cs {
-		if cs[i].Name == "" {
-			http.Error(w, "name is required for each category", http.StatusBadRequest)
+	cs := make([]models.Category, len(reqs))
+	for i, req := range reqs {
+		if req.Name == "" {
+			writeInvalid(w, "name is required for each category", map[string]string{"name": "is required"})
+			return
+		}
+		if req.Points != nil && *req.Points < 0 {
+			writeInvalid(w, "points must be >= 0 for each category", map[string]string{"points": "must be >= 0"})
 			return
 		}
+		cs[i] = req.category()
+	}
+	cer, ok := h.requireCeremony(w, r)
+	if !ok {
+		return
+	}
+	for i := range cs {
+		if cs[i].CeremonyID == "" {
+			cs[i].CeremonyID = cer.ID
+		}
 	}
-	ids, err := h.categoryStore.InsertMany(cs)
+	ids, err := h.categoryStore.InsertMany(r.Context(), cs)
 	if err != nil {
-		http.Error(w, "db error: "+err.Error(), http.StatusInternalServerError)
+		writeStoreError(w, err)
 		return
 	}
 	out := make([]models.Category, 0, len(cs))
@@ -254,34 +298,29 @@ func (h *Handler) AddCategories(w http.ResponseWriter, r *http.Request) {
 
 // AddNominated accepts POST /add_nominated with JSON body and inserts into storage.
 func (h *Handler) AddNominated(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodPost {
-		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
-		return
-	}
-
 	if !h.validateCSRF(r) {
-		http.Error(w, "invalid csrf token", http.StatusForbidden)
+		writeError(w, http.StatusForbidden, codeInvalidCSRF, "invalid csrf token")
 		return
 	}
 	body, err := io.ReadAll(r.Body)
 	if err != nil {
-		http.Error(w, "failed to read body", http.StatusBadRequest)
+		writeError(w, http.StatusBadRequest, codeBadRequest, "failed to read body")
 		return
 	}
 	defer r.Body.Close()
 
 	var n models.Nominated
 	if err := json.Unmarshal(body, &n); err != nil {
-		http.Error(w, "invalid json: "+err.Error(), http.StatusBadRequest)
+		writeError(w, http.StatusBadRequest, codeInvalidJSON, "invalid json: "+err.Error())
 		return
 	}
 	if n.MovieID == "" || n.CategoryID == "" || n.Name == "" {
-		http.Error(w, "movie_id, category_id and name are required", http.StatusBadRequest)
+		writeInvalid(w, "movie_id, category_id and name are required", map[string]string{"movie_id": "is required", "category_id": "is required", "name": "is required"})
 		return
 	}
-	id, err := h.nominatedStore.Insert(&n)
+	id, err := h.nominatedStore.Insert(r.Context(), &n)
 	if err != nil {
-		http.Error(w, "db error: "+err.Error(), http.StatusInternalServerError)
+		writeInsertError(w, "name", err)
 		return
 	}
 	n.ID = id
@@ -292,40 +331,35 @@ func (h *Handler) AddNominated(w http.ResponseWriter, r *http.Request) {
 
 // AddNominateds accepts POST /add_nominateds with a JSON array body and inserts multiple nominations.
 func (h *Handler) AddNominateds(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodPost {
-		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
-		return
-	}
-
 	if !h.validateCSRF(r) {
-		http.Error(w, "invalid csrf token", http.StatusForbidden)
+		writeError(w, http.StatusForbidden, codeInvalidCSRF, "invalid csrf token")
 		return
 	}
 	body, err := io.ReadAll(r.Body)
 	if err != nil {
-		http.Error(w, "failed to read body", http.StatusBadRequest)
+		writeError(w, http.StatusBadRequest, codeBadRequest, "failed to read body")
 		return
 	}
 	defer r.Body.Close()
 
 	var ns []models.Nominated
 	if err := json.Unmarshal(body, &ns); err != nil {
-		http.Error(w, "invalid json: "+err.Error(), http.StatusBadRequest)
+		writeError(w, http.StatusBadRequest, codeInvalidJSON, "invalid json: "+err.Error())
 		return
 	}
 	if len(ns) == 0 {
-		http.Error(w, "empty nomination list", http.StatusBadRequest)
+		writeError(w, http.StatusBadRequest, codeBadRequest, "empty nomination list")
 		return
 	}
 	for i := range ns {
 		if ns[i].MovieID == "" || ns[i].CategoryID == "" || ns[i].Name == "" {
-			http.Error(w, "movie_id, category_id and name are required for each nomination", http.StatusBadRequest)
+			writeInvalid(w, "movie_id, category_id and name are required for each nomination", map[string]string{"movie_id": "is required", "category_id": "is required", "name": "is required"})
 			return
 		}
 	}
-	ids, err := h.nominatedStore.InsertMany(ns)
+	ids, err := h.nominatedStore.InsertMany(r.Context(), ns)
 	if err != nil {
-		http.Error(w, "db error: "+err.Error(), http.StatusInternalServerError)
+		writeStoreError(w, err)
 		return
 	}
 	out := make([]models.Nominated, 0, len(ns))
@@ -341,59 +375,51 @@ func (h *Handler) AddNominateds(w http.ResponseWriter, r *http.Request) {
 	_ = json.NewEncoder(w).Encode(out)
 }
 
-// ListNominateds handles GET /nominateds
+// ListNominateds handles GET /nominateds[?category_id=&movie_id=&limit=&cursor=]
+// and returns one page of the ceremony's nominations as {items, next_cursor}.
 func (h *Handler) ListNominateds(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodGet {
-		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	cer, ok := h.requireCeremony(w, r)
+	if !ok {
+		return
+	}
+	p, ok := pageParams(w, r)
+	if !ok {
 		return
 	}
-	out, err := h.nominatedStore.List()
+	q := r.URL.Query()
+	out, next, err := h.nominatedStore.ListPage(r.Context(), store.NominatedFilter{CeremonyID: cer.ID, CategoryID: q.Get("category_id"), MovieID: q.Get("movie_id")}, p)
 	if err != nil {
-		http.Error(w, "db error: "+err.Error(), http.StatusInternalServerError)
+		writeStoreError(w, err)
 		return
 	}
 
 	// enrich nominateds with the movie title so frontends can show the movie name
-	type nominatedOut struct {
-		ID         string `json:"id,omitempty"`
-		MovieID    string `json:"movie_id"`
-		CategoryID string `json:"category_id"`
-		Name       string `json:"name"`
-		Image      string `json:"image,omitempty"`
-		MovieName  string `json:"movie_name,omitempty"`
-	}
 	res := make([]nominatedOut, 0, len(out))
 	for _, n := range out {
 		no := nominatedOut{ID: n.ID, MovieID: n.MovieID, CategoryID: n.CategoryID, Name: n.Name, Image: n.UrlImage}
 		// attempt to fetch movie title; if it fails keep MovieName empty
-		if m, err := h.movieStore.Get(n.MovieID); err == nil && m != nil {
+		if m, err := h.movieStore.Get(r.Context(), n.MovieID); err == nil && m != nil {
 			no.MovieName = m.Title
 		}
 		res = append(res, no)
 	}
-
-	w.Header().Set("Content-Type", "application/json")
-	_ = json.NewEncoder(w).Encode(res)
+	writePage(w, res, next)
 }
 
 // GetNominated handles GET /nominateds?id=<id>
 func (h *Handler) GetNominated(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodGet {
-		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
-		return
-	}
-	q := r.URL.Query().Get("id")
+	q := param(r, "id")
 	if q == "" {
-		http.Error(w, "id is required", http.StatusBadRequest)
+		writeInvalid(w, "id is required", map[string]string{"id": "is required"})
 		return
 	}
-	n, err := h.nominatedStore.Get(q)
+	n, err := h.nominatedStore.Get(r.Context(), q)
 	if err != nil {
-		http.Error(w, "db error: "+err.Error(), http.StatusInternalServerError)
+		writeStoreError(w, err)
 		return
 	}
 	if n == nil {
-		http.NotFound(w, r)
+		writeError(w, http.StatusNotFound, codeNotFound, "nominee not found")
 		return
 	}
 	w.Header().Set("Content-Type", "application/json")
@@ -403,34 +429,21 @@ func (h *Handler) GetNominated(w http.ResponseWriter, r *http.Request) {
 // ListNominatedsByCategory handles GET /nominateds/by_category?category_id=<id>
 // It returns nominated entries that belong to the provided category id.
 func (h *Handler) ListNominatedsByCategory(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodGet {
-		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
-		return
-	}
-	cid := r.URL.Query().Get("category_id")
+	cid := param(r, "category_id")
 	if cid == "" {
-		http.Error(w, "category_id is required", http.StatusBadRequest)
+		writeInvalid(w, "category_id is required", map[string]string{"category_id": "is required"})
 		return
 	}
-	out, err := h.nominatedStore.ListByCategory(cid)
+	out, err := h.nominatedStore.ListByCategory(r.Context(), cid)
 	if err != nil {
-		http.Error(w, "db error: "+err.Error(), http.StatusInternalServerError)
+		writeStoreError(w, err)
 		return
 	}
 
-	type nominatedOut struct {
-		ID         string `json:"id,omitempty"`
-		MovieID    string `json:"movie_id"`
-		CategoryID string `json:"category_id"`
-		Name       string `json:"name"`
-		Image      string `json:"image,omitempty"`
-		MovieName  string `json:"movie_name,omitempty"`
-	}
-
 	res := make([]nominatedOut, 0, len(out))
 	for _, n := range out {
 		no := nominatedOut{ID: n.ID, MovieID: n.MovieID, CategoryID: n.CategoryID, Name: n.Name, Image: n.UrlImage}
-		if m, err := h.movieStore.Get(n.MovieID); err == nil && m != nil {
+		if m, err := h.movieStore.Get(r.Context(), n.MovieID); err == nil && m != nil {
 			no.MovieName = m.Title
 		}
 		res = append(res, no)
@@ -443,29 +456,29 @@ func (h *Handler) ListNominatedsByCategory(w http.ResponseWriter, r *http.Reques
 // NomineesByCategory returns all nominees grouped by categories.
 // GET /nominees_by_category -> { categories: [{ id, name, sequence_order, nominees: [...] }, ...] }
 func (h *Handler) NomineesByCategory(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodGet {
-		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	cer, ok := h.requireCeremony(w, r)
+	if !ok {
 		return
 	}
 
 	// Get all categories
-	categories, err := h.categoryStore.List()
+	categories, err := h.categoryStore.List(r.Context(), cer.ID)
 	if err != nil {
-		http.Error(w, "db error: "+err.Error(), http.StatusInternalServerError)
+		writeStoreError(w, err)
 		return
 	}
 
 	// Get all nominees
-	allNominees, err := h.nominatedStore.List()
+	allNominees, err := h.nominatedStore.List(r.Context(), cer.ID)
 	if err != nil {
-		http.Error(w, "db error: "+err.Error(), http.StatusInternalServerError)
+		writeStoreError(w, err)
 		return
 	}
 
 	// Get all movies for movie name lookup
-	allMovies, err := h.movieStore.List()
+	allMovies, err := h.movieStore.List(r.Context())
 	if err != nil {
-		http.Error(w, "db error: "+err.Error(), http.StatusInternalServerError)
+		writeStoreError(w, err)
 		return
 	}
 	movieByID := make(map[string]string)
@@ -480,27 +493,12 @@ func (h *Handler) NomineesByCategory(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Build response
-	type NomineeOut struct {
-		ID        string `json:"id"`
-		Name      string `json:"name"`
-		MovieID   string `json:"movie_id"`
-		MovieName string `json:"movie_name,omitempty"`
-		Image     string `json:"image,omitempty"`
-	}
-
-	type CategoryWithNominees struct {
-		ID            string       `json:"id"`
-		Name          string       `json:"name"`
-		SequenceOrder int          `json:"sequence_order"`
-		Nominees      []NomineeOut `json:"nominees"`
-	}
-
-	result := make([]CategoryWithNominees, 0, len(categories))
+	result := make([]categoryWithNominees, 0, len(categories))
 	for _, cat := range categories {
 		catNominees := nomineesByCat[cat.ID]
-		nominees := make([]NomineeOut, 0, len(catNominees))
+		nominees := make([]nomineeOut, 0, len(catNominees))
 		for _, n := range catNominees {
-			nominees = append(nominees, NomineeOut{
+			nominees = append(nominees, nomineeOut{
 				ID:        n.ID,
 				Name:      n.Name,
 				MovieID:   n.MovieID,
@@ -508,7 +506,7 @@ func (h *Handler) NomineesByCategory(w http.ResponseWriter, r *http.Request) {
 				Image:     n.UrlImage,
 			})
 		}
-		result = append(result, CategoryWithNominees{
+		result = append(result, categoryWithNominees{
 			ID:            cat.ID,
 			Name:          cat.Name,
 			SequenceOrder: cat.SequenceOrder,
@@ -528,60 +526,44 @@ func (h *Handler) NomineesByCategory(w http.ResponseWriter, r *http.Request) {
 // ServeNominatedForm renders an HTML form to create a nomination by selecting
 // a movie and a category and entering a name. The form POSTs to /nominated/create.
 func (h *Handler) ServeNominatedForm(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodGet {
-		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	movies, err := h.movieStore.List(r.Context())
+	if err != nil {
+		writeStoreError(w, err)
 		return
 	}
-	movies, err := h.movieStore.List()
+	cer, ok := h.requireCeremony(w, r)
+	if !ok {
+		return
+	}
+	categories, err := h.categoryStore.List(r.Context(), cer.ID)
 	if err != nil {
-		http.Error(w, "db error: "+err.Error(), http.StatusInternalServerError)
+		writeStoreError(w, err)
 		return
 	}
-	categories, err := h.categoryStore.List()
+	p, err := h.newPage(w, r, "")
 	if err != nil {
-		http.Error(w, "db error: "+err.Error(), http.StatusInternalServerError)
+		writeStoreError(w, err)
 		return
 	}
-	csrf := h.ensureCSRFCookie(w, r)
 	data := struct {
+		page
 		Movies     []models.Movie
 		Categories []models.Category
-		CSRF       string
-	}{Movies: movies, Categories: categories, CSRF: csrf}
-	w.Header().Set("Content-Type", "text/html; charset=utf-8")
-	if h.nominatedTpl != nil {
-		if err := h.nominatedTpl.Execute(w, data); err != nil {
-			http.Error(w, "template render error: "+err.Error(), http.StatusInternalServerError)
-			return
-		}
-		return
-	}
-	// fallback: parse template on each request if not provided at startup
-	tpl, err := template.ParseFiles("templates/nominated_form.html", "templates/footer.html")
-	if err != nil {
-		http.Error(w, "template error: "+err.Error(), http.StatusInternalServerError)
-		return
-	}
-	if err := tpl.Execute(w, data); err != nil {
-		http.Error(w, "template render error: "+err.Error(), http.StatusInternalServerError)
-		return
-	}
+	}{page: p, Movies: movies, Categories: categories}
+	h.render(w, http.StatusOK, "nominated_form.html", data)
 }
 
 // CreateNominatedFromForm accepts form POST from the HTML view and creates a nomination.
-// It expects form fields: movie_id, category_id, name. On success it redirects to /nominateds.
+// It expects form fields: movie_id, category_id, name. On success it redirects to
+// the nominees page of the category.
 func (h *Handler) CreateNominatedFromForm(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodPost {
-		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
-		return
-	}
 	// validate csrf token submitted in form
 	if !h.validateCSRF(r) {
-		http.Error(w, "invalid csrf token", http.StatusForbidden)
+		writeError(w, http.StatusForbidden, codeInvalidCSRF, "invalid csrf token")
 		return
 	}
 	if err := r.ParseForm(); err != nil {
-		http.Error(w, "invalid form: "+err.Error(), http.StatusBadRequest)
+		writeError(w, http.StatusBadRequest, codeBadRequest, "invalid form: "+err.Error())
 		return
 	}
 	movieIDStr := r.FormValue("movie_id")
@@ -589,61 +571,53 @@ func (h *Handler) CreateNominatedFromForm(w http.ResponseWriter, r *http.Request
 	name := r.FormValue("name")
 	urlImage := r.FormValue("url_image")
 	if movieIDStr == "" || categoryIDStr == "" || name == "" {
-		http.Error(w, "movie_id, category_id and name are required", http.StatusBadRequest)
+		writeInvalid(w, "movie_id, category_id and name are required", map[string]string{"movie_id": "is required", "category_id": "is required", "name": "is required"})
 		return
 	}
 	n := models.Nominated{MovieID: movieIDStr, CategoryID: categoryIDStr, Name: name, UrlImage: urlImage}
-	id, err := h.nominatedStore.Insert(&n)
+	id, err := h.nominatedStore.Insert(r.Context(), &n)
 	if err != nil {
-		http.Error(w, "db error: "+err.Error(), http.StatusInternalServerError)
+		writeInsertError(w, "name", err)
 		return
 	}
-	// Redirect to list view after successful creation
-	http.Redirect(w, r, "/nominateds", http.StatusSeeOther)
-	fmt.Printf("created nomination id=%s\n", id)
+	slog.InfoContext(r.Context(), "nomination created", "nominated_id", id)
+	// show the category the nominee was added to
+	http.Redirect(w, r, "/nominateds/view?category_id="+url.QueryEscape(categoryIDStr), http.StatusSeeOther)
 }
 
 // AddNominatedsByNames accepts POST /add_nominateds_names with JSON { category_id: <int>, names: ["name1","name2"] }
 // It will create movies for each provided name (using Movie titles), then create nominated entries linking the created movies to the given category.
 func (h *Handler) AddNominatedsByNames(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodPost {
-		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
-		return
-	}
-
 	if !h.validateCSRF(r) {
-		http.Error(w, "invalid csrf token", http.StatusForbidden)
+		writeError(w, http.StatusForbidden, codeInvalidCSRF, "invalid csrf token")
 		return
 	}
 
 	body, err := io.ReadAll(r.Body)
 	if err != nil {
-		http.Error(w, "failed to read body", http.StatusBadRequest)
+		writeError(w, http.StatusBadRequest, codeBadRequest, "failed to read body")
 		return
 	}
 	defer r.Body.Close()
 
-	var req struct {
-		CategoryID string   `json:"category_id"`
-		Names      []string `json:"names"`
-	}
+	var req nominateByNamesRequest
 	if err := json.Unmarshal(body, &req); err != nil {
-		http.Error(w, "invalid json: "+err.Error(), http.StatusBadRequest)
+		writeError(w, http.StatusBadRequest, codeInvalidJSON, "invalid json: "+err.Error())
 		return
 	}
 	if req.CategoryID == "" || len(req.Names) == 0 {
-		http.Error(w, "category_id and non-empty names are required", http.StatusBadRequest)
+		writeInvalid(w, "category_id and non-empty names are required", map[string]string{"category_id": "is required", "names": "must be non-empty"})
 		return
 	}
 
 	// verify category exists
-	cat, err := h.categoryStore.Get(req.CategoryID)
+	cat, err := h.categoryStore.Get(r.Context(), req.CategoryID)
 	if err != nil {
-		http.Error(w, "db error: "+err.Error(), http.StatusInternalServerError)
+		writeStoreError(w, err)
 		return
 	}
 	if cat == nil {
-		http.Error(w, "category not found", http.StatusBadRequest)
+		writeError(w, http.StatusBadRequest, codeBadRequest, "category not found")
 		return
 	}
 
@@ -651,14 +625,14 @@ func (h *Handler) AddNominatedsByNames(w http.ResponseWriter, r *http.Request) {
 	movies := make([]models.Movie, 0, len(req.Names))
 	for _, n := range req.Names {
 		if n == "" {
-			http.Error(w, "empty name in list", http.StatusBadRequest)
+			writeError(w, http.StatusBadRequest, codeBadRequest, "empty name in list")
 			return
 		}
 		movies = append(movies, models.Movie{Title: n})
 	}
-	ids, err := h.movieStore.InsertMany(movies)
+	ids, err := h.movieStore.InsertMany(r.Context(), movies)
 	if err != nil {
-		http.Error(w, "db error: "+err.Error(), http.StatusInternalServerError)
+		writeStoreError(w, err)
 		return
 	}
 
@@ -667,9 +641,9 @@ func (h *Handler) AddNominatedsByNames(w http.ResponseWriter, r *http.Request) {
 	for i, mid := range ids {
 		nominateds = append(nominateds, models.Nominated{MovieID: mid, CategoryID: req.CategoryID, Name: req.Names[i]})
 	}
-	nids, err := h.nominatedStore.InsertMany(nominateds)
+	nids, err := h.nominatedStore.InsertMany(r.Context(), nominateds)
 	if err != nil {
-		http.Error(w, "db error: "+err.Error(), http.StatusInternalServerError)
+		writeStoreError(w, err)
 		return
 	}
 
@@ -687,214 +661,144 @@ func (h *Handler) AddNominatedsByNames(w http.ResponseWriter, r *http.Request) {
 	_ = json.NewEncoder(w).Encode(out)
 }
 
-// ServeLoginForm renders a simple login/register HTML form.
-func (h *Handler) ServeLoginForm(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodGet {
-		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
-		return
-	}
-	// ensure CSRF cookie so the client can include it in subsequent requests
-	h.ensureCSRFCookie(w, r)
-	// fallback: parse template on each request
-	tpl, err := template.ParseFiles("templates/login_form.html", "templates/auth_modal.html")
-	if err != nil {
-		http.Error(w, "template error: "+err.Error(), http.StatusInternalServerError)
-		return
-	}
-	w.Header().Set("Content-Type", "text/html; charset=utf-8")
-	if err := tpl.Execute(w, nil); err != nil {
-		http.Error(w, "template render error: "+err.Error(), http.StatusInternalServerError)
-		return
-	}
+// Healthz returns 200 OK for health checks.
+func (h *Handler) Healthz(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write([]byte("ok"))
 }
 
-// ServeCategoriesView renders the categories page which fetches the API using the stored JWT.
-func (h *Handler) ServeCategoriesView(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodGet {
-		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
-		return
-	}
-	// try to load compiled template or parse from templates dir
-	tpl, err := template.ParseFiles("templates/categories_view.html", "templates/footer.html")
-	if err != nil {
-		http.Error(w, "template error: "+err.Error(), http.StatusInternalServerError)
-		return
-	}
-	w.Header().Set("Content-Type", "text/html; charset=utf-8")
-	if err := tpl.Execute(w, nil); err != nil {
-		http.Error(w, "template render error: "+err.Error(), http.StatusInternalServerError)
+// ListCategories handles GET /categories[?limit=&cursor=] (active ceremony
+// unless ?ceremony_id= is given) and returns one page as {items, next_cursor}.
+func (h *Handler) ListCategories(w http.ResponseWriter, r *http.Request) {
+	cer, ok := h.requireCeremony(w, r)
+	if !ok {
 		return
 	}
-}
-
-// ServeNominatedsView renders a page showing nominated candidates for a category.
-func (h *Handler) ServeNominatedsView(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodGet {
-		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	p, ok := pageParams(w, r)
+	if !ok {
 		return
 	}
-	tpl, err := template.ParseFiles("templates/nominateds_view.html", "templates/auth_modal.html", "templates/footer.html")
+	out, next, err := h.categoryStore.ListPage(r.Context(), store.CategoryFilter{CeremonyID: cer.ID}, p)
 	if err != nil {
-		http.Error(w, "template error: "+err.Error(), http.StatusInternalServerError)
-		return
-	}
-	w.Header().Set("Content-Type", "text/html; charset=utf-8")
-	if err := tpl.Execute(w, nil); err != nil {
-		http.Error(w, "template render error: "+err.Error(), http.StatusInternalServerError)
+		writeStoreError(w, err)
 		return
 	}
+	writePage(w, out, next)
 }
 
-// ServeProfileView renders the user's profile page.
-func (h *Handler) ServeProfileView(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodGet {
-		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+// GetCategory handles GET /categories?id=<id>
+func (h *Handler) GetCategory(w http.ResponseWriter, r *http.Request) {
+	q := param(r, "id")
+	if q == "" {
+		writeInvalid(w, "id is required", map[string]string{"id": "is required"})
 		return
 	}
-	tpl, err := template.ParseFiles("templates/profile_view.html", "templates/footer.html")
+	// IDs are UUID strings now — pass directly
+	c, err := h.categoryStore.Get(r.Context(), q)
 	if err != nil {
-		http.Error(w, "template error: "+err.Error(), http.StatusInternalServerError)
+		writeStoreError(w, err)
 		return
 	}
-	w.Header().Set("Content-Type", "text/html; charset=utf-8")
-	if err := tpl.Execute(w, nil); err != nil {
-		http.Error(w, "template render error: "+err.Error(), http.StatusInternalServerError)
+	if c == nil {
+		writeError(w, http.StatusNotFound, codeNotFound, "category not found")
 		return
 	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(c)
 }
 
-// ServeParticipantsView renders a page listing participants and their votes.
-func (h *Handler) ServeParticipantsView(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodGet {
-		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+// UpdateCategory accepts POST /update_category with JSON {id, name?, sequence_order?, points?}.
+// Omitted fields keep their current value.
+func (h *Handler) UpdateCategory(w http.ResponseWriter, r *http.Request) {
+	if !h.validateCSRF(r) {
+		writeError(w, http.StatusForbidden, codeInvalidCSRF, "invalid csrf token")
 		return
 	}
-	tpl, err := template.ParseFiles("templates/participants_view.html", "templates/auth_modal.html", "templates/footer.html")
+	body, err := io.ReadAll(r.Body)
 	if err != nil {
-		http.Error(w, "template error: "+err.Error(), http.StatusInternalServerError)
+		writeError(w, http.StatusBadRequest, codeBadRequest, "failed to read body")
 		return
 	}
-	w.Header().Set("Content-Type", "text/html; charset=utf-8")
-	if err := tpl.Execute(w, nil); err != nil {
-		http.Error(w, "template render error: "+err.Error(), http.StatusInternalServerError)
-		return
-	}
-}
-
-// Healthz returns 200 OK for health checks.
-func (h *Handler) Healthz(w http.ResponseWriter, r *http.Request) {
-	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
-	w.WriteHeader(http.StatusOK)
-	_, _ = w.Write([]byte("ok"))
-}
+	defer r.Body.Close()
 
-// ListCategories handles GET /categories
-func (h *Handler) ListCategories(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodGet {
-		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
-		return
-	}
-	out, err := h.categoryStore.List()
-	if err != nil {
-		http.Error(w, "db error: "+err.Error(), http.StatusInternalServerError)
+	var req updateCategoryRequest
+	if err := json.Unmarshal(body, &req); err != nil {
+		writeError(w, http.StatusBadRequest, codeInvalidJSON, "invalid json: "+err.Error())
 		return
 	}
-	w.Header().Set("Content-Type", "application/json")
-	_ = json.NewEncoder(w).Encode(out)
-}
-
-// GetCategory handles GET /categories?id=<id>
-func (h *Handler) GetCategory(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodGet {
-		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
-		return
+	if id := router.Param(r, "id"); id != "" {
+		req.ID = id
 	}
-	q := r.URL.Query().Get("id")
-	if q == "" {
-		http.Error(w, "id is required", http.StatusBadRequest)
+	if req.ID == "" {
+		writeInvalid(w, "id is required", map[string]string{"id": "is required"})
 		return
 	}
-	// IDs are UUID strings now — pass directly
-	c, err := h.categoryStore.Get(q)
+	c, err := h.categoryStore.Get(r.Context(), req.ID)
 	if err != nil {
-		http.Error(w, "db error: "+err.Error(), http.StatusInternalServerError)
+		writeStoreError(w, err)
 		return
 	}
 	if c == nil {
-		http.NotFound(w, r)
+		writeError(w, http.StatusNotFound, codeNotFound, "category not found")
 		return
 	}
-	w.Header().Set("Content-Type", "application/json")
-	_ = json.NewEncoder(w).Encode(c)
-}
-
-// ServeWinnersView renders the admin page for selecting winners by category.
-func (h *Handler) ServeWinnersView(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodGet {
-		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
-		return
+	if req.Name != nil {
+		if *req.Name == "" {
+			writeInvalid(w, "name cannot be empty", map[string]string{"name": "cannot be empty"})
+			return
+		}
+		c.Name = *req.Name
 	}
-	tpl, err := template.ParseFiles("templates/winners_view.html", "templates/footer.html")
-	if err != nil {
-		http.Error(w, "template error: "+err.Error(), http.StatusInternalServerError)
-		return
+	if req.SequenceOrder != nil {
+		c.SequenceOrder = *req.SequenceOrder
+	}
+	if req.Points != nil {
+		if *req.Points < 0 {
+			writeInvalid(w, "points must be >= 0", map[string]string{"points": "must be >= 0"})
+			return
+		}
+		c.Points = *req.Points
 	}
-	w.Header().Set("Content-Type", "text/html; charset=utf-8")
-	if err := tpl.Execute(w, nil); err != nil {
-		http.Error(w, "template render error: "+err.Error(), http.StatusInternalServerError)
+	if err := h.categoryStore.Update(r.Context(), c); err != nil {
+		writeStoreError(w, err)
 		return
 	}
+	if req.Points != nil {
+		h.publishLeaderboard(r.Context(), c.CeremonyID)
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(c)
 }
 
 // AddWinner handles POST /add_winner to set a winner for a nominated.
 func (h *Handler) AddWinner(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodPost {
-		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
-		return
-	}
 	if !h.validateCSRF(r) {
-		http.Error(w, "invalid csrf token", http.StatusForbidden)
+		writeError(w, http.StatusForbidden, codeInvalidCSRF, "invalid csrf token")
 		return
 	}
 	body, err := io.ReadAll(r.Body)
 	if err != nil {
-		http.Error(w, "failed to read body", http.StatusBadRequest)
+		writeError(w, http.StatusBadRequest, codeBadRequest, "failed to read body")
 		return
 	}
 	defer r.Body.Close()
 
-	var req struct {
-		NominatedID string `json:"nominated_id"`
-	}
+	var req nominatedIDRequest
 	if err := json.Unmarshal(body, &req); err != nil {
-		http.Error(w, "invalid json: "+err.Error(), http.StatusBadRequest)
+		writeError(w, http.StatusBadRequest, codeInvalidJSON, "invalid json: "+err.Error())
 		return
 	}
 	if req.NominatedID == "" {
-		http.Error(w, "nominated_id is required", http.StatusBadRequest)
-		return
-	}
-
-	// Check if nominated exists
-	nominated, err := h.nominatedStore.Get(req.NominatedID)
-	if err != nil {
-		http.Error(w, "db error: "+err.Error(), http.StatusInternalServerError)
-		return
-	}
-	if nominated == nil {
-		http.Error(w, "nominated not found", http.StatusNotFound)
+		writeInvalid(w, "nominated_id is required", map[string]string{"nominated_id": "is required"})
 		return
 	}
 
-	// Insert winner
-	winner := &models.Winner{NominatedID: req.NominatedID}
-	id, err := h.winnerStore.Insert(winner)
+	winner, err := h.setWinner(r.Context(), req.NominatedID)
 	if err != nil {
-		http.Error(w, "db error: "+err.Error(), http.StatusInternalServerError)
+		writeErr(w, err)
 		return
 	}
-	winner.ID = id
-
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusCreated)
 	_ = json.NewEncoder(w).Encode(winner)
@@ -902,35 +806,75 @@ func (h *Handler) AddWinner(w http.ResponseWriter, r *http.Request) {
 
 // DeleteWinner handles DELETE /delete_winner?id=<id> to remove a winner.
 func (h *Handler) DeleteWinner(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodDelete {
-		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
-		return
-	}
 	if !h.validateCSRF(r) {
-		http.Error(w, "invalid csrf token", http.StatusForbidden)
+		writeError(w, http.StatusForbidden, codeInvalidCSRF, "invalid csrf token")
 		return
 	}
-	id := r.URL.Query().Get("id")
+	id := param(r, "id")
 	if id == "" {
-		http.Error(w, "id is required", http.StatusBadRequest)
+		writeInvalid(w, "id is required", map[string]string{"id": "is required"})
 		return
 	}
-	if err := h.winnerStore.Delete(id); err != nil {
-		http.Error(w, "db error: "+err.Error(), http.StatusInternalServerError)
+	if err := h.removeWinner(r.Context(), id); err != nil {
+		writeErr(w, err)
 		return
 	}
 	w.WriteHeader(http.StatusNoContent)
 }
 
-// ListWinners handles GET /winners to list all winners.
+// setWinner records a nominee as its category's winner and pushes the new
+// leaderboard to the live subscribers.
+func (h *Handler) setWinner(ctx context.Context, nominatedID string) (*models.Winner, error) {
+	nominated, err := h.nominatedStore.Get(ctx, nominatedID)
+	if err != nil {
+		return nil, err
+	}
+	if nominated == nil {
+		return nil, &statusError{http.StatusNotFound, codeNotFound, "nominated not found"}
+	}
+	winner := &models.Winner{NominatedID: nominatedID}
+	id, err := h.winnerStore.Insert(ctx, winner)
+	if err != nil {
+		return nil, err
+	}
+	winner.ID = id
+	winnersSetTotal.Inc()
+
+	h.events.Publish(events.Event{Type: events.WinnerAdded, Data: winner})
+	if cid := h.ceremonyOfNominated(ctx, winner.NominatedID); cid != "" {
+		h.publishLeaderboard(ctx, cid)
+	}
+	return winner, nil
+}
+
+// removeWinner deletes a winner and pushes the new leaderboard.
+func (h *Handler) removeWinner(ctx context.Context, id string) error {
+	// look the winner up first so the event can carry its nominee
+	existing, err := h.winnerStore.Get(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := h.winnerStore.Delete(ctx, id); err != nil {
+		return err
+	}
+	if existing != nil {
+		h.events.Publish(events.Event{Type: events.WinnerDeleted, Data: existing})
+		if cid := h.ceremonyOfNominated(ctx, existing.NominatedID); cid != "" {
+			h.publishLeaderboard(ctx, cid)
+		}
+	}
+	return nil
+}
+
+// ListWinners handles GET /winners to list the winners of the active (or ?ceremony_id=) ceremony.
 func (h *Handler) ListWinners(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodGet {
-		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	cer, ok := h.requireCeremony(w, r)
+	if !ok {
 		return
 	}
-	winners, err := h.winnerStore.List()
+	winners, err := h.winnerStore.List(r.Context(), cer.ID)
 	if err != nil {
-		http.Error(w, "db error: "+err.Error(), http.StatusInternalServerError)
+		writeStoreError(w, err)
 		return
 	}
 	w.Header().Set("Content-Type", "application/json")
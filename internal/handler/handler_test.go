@@ -2,31 +2,57 @@ package handler
 
 import (
 	"bytes"
+	"context"
+	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"log/slog"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
+	"strings"
 	"testing"
+	"time"
 
+	"votacao/internal/events"
+	"votacao/internal/logging"
+	"votacao/internal/store"
 	"votacao/models"
 )
 
 type mockMovieStore struct{}
 
-func (m *mockMovieStore) Insert(mv *models.Movie) (string, error) {
+func (m *mockMovieStore) Insert(ctx context.Context, mv *models.Movie) (string, error) {
 	return "00000000-0000-0000-0000-000000000042", nil
 }
-func (m *mockMovieStore) Get(id string) (*models.Movie, error) {
+func (m *mockMovieStore) Get(ctx context.Context, id string) (*models.Movie, error) {
 	if id != "00000000-0000-0000-0000-000000000042" {
 		return nil, nil
 	}
 	return &models.Movie{ID: "00000000-0000-0000-0000-000000000042", Title: "Mock"}, nil
 }
-func (m *mockMovieStore) List() ([]models.Movie, error) {
+func (m *mockMovieStore) GetMany(ctx context.Context, ids []string) ([]models.Movie, error) {
+	out := make([]models.Movie, 0)
+	for _, id := range ids {
+		if mv, _ := m.Get(ctx, id); mv != nil {
+			out = append(out, *mv)
+		}
+	}
+	return out, nil
+}
+func (m *mockMovieStore) List(ctx context.Context) ([]models.Movie, error) {
 	return []models.Movie{{ID: "00000000-0000-0000-0000-000000000042", Title: "Mock"}}, nil
 }
+func (m *mockMovieStore) ListPage(ctx context.Context, f store.MovieFilter, p store.Page) ([]models.Movie, string, error) {
+	out, err := m.List(ctx)
+	return out, "", err
+}
+
+func (m *mockMovieStore) Update(ctx context.Context, mv *models.Movie) error      { return nil }
+func (m *mockMovieStore) Delete(ctx context.Context, id string, force bool) error { return nil }
 
-func (m *mockMovieStore) InsertMany(ms []models.Movie) ([]string, error) {
+func (m *mockMovieStore) InsertMany(ctx context.Context, ms []models.Movie) ([]string, error) {
 	ids := make([]string, 0, len(ms))
 	for i := range ms {
 		ids = append(ids, fmt.Sprintf("00000000-0000-0000-0000-0000000001%02d", i))
@@ -34,21 +60,45 @@ func (m *mockMovieStore) InsertMany(ms []models.Movie) ([]string, error) {
 	return ids, nil
 }
 
-type mockCategoryStore struct{}
+// mockCategoryStore records the last Update/SetPoints call; locked and
+// locksAt are its single category's lock state.
+type mockCategoryStore struct {
+	updated *models.Category
+	points  map[string]int
+	locked  bool
+	locksAt *time.Time
+}
 
-func (m *mockCategoryStore) Insert(c *models.Category) (string, error) {
+func (m *mockCategoryStore) Insert(ctx context.Context, c *models.Category) (string, error) {
 	return "00000000-0000-0000-0000-000000000007", nil
 }
-func (m *mockCategoryStore) Get(id string) (*models.Category, error) {
+func (m *mockCategoryStore) Get(ctx context.Context, id string) (*models.Category, error) {
 	if id != "00000000-0000-0000-0000-000000000007" {
 		return nil, nil
 	}
-	return &models.Category{ID: "00000000-0000-0000-0000-000000000007", Name: "MockCat"}, nil
+	return &models.Category{ID: "00000000-0000-0000-0000-000000000007", CeremonyID: mockCeremonyID, Name: "MockCat", Locked: m.locked, LocksAt: m.locksAt}, nil
 }
-func (m *mockCategoryStore) List() ([]models.Category, error) {
-	return []models.Category{{ID: "00000000-0000-0000-0000-000000000007", Name: "MockCat"}}, nil
+func (m *mockCategoryStore) List(ctx context.Context, ceremonyID string) ([]models.Category, error) {
+	if ceremonyID != mockCeremonyID {
+		return []models.Category{}, nil
+	}
+	return []models.Category{{ID: "00000000-0000-0000-0000-000000000007", CeremonyID: mockCeremonyID, Name: "MockCat", Locked: m.locked, LocksAt: m.locksAt}}, nil
 }
-func (m *mockCategoryStore) InsertMany(cs []models.Category) ([]string, error) {
+
+// ListPage serves the single category as one page; any cursor is rejected
+// since none is ever issued.
+func (m *mockCategoryStore) ListPage(ctx context.Context, f store.CategoryFilter, p store.Page) ([]models.Category, string, error) {
+	if p.Cursor != "" {
+		var key int
+		if err := store.DecodeCursor(p.Cursor, &key); err != nil {
+			return nil, "", err
+		}
+		return []models.Category{}, "", nil
+	}
+	out, err := m.List(ctx, f.CeremonyID)
+	return out, "", err
+}
+func (m *mockCategoryStore) InsertMany(ctx context.Context, cs []models.Category) ([]string, error) {
 	ids := make([]string, 0, len(cs))
 	for i := range cs {
 		ids = append(ids, fmt.Sprintf("00000000-0000-0000-0000-0000000002%02d", i))
@@ -56,45 +106,337 @@ func (m *mockCategoryStore) InsertMany(cs []models.Category) ([]string, error) {
 	return ids, nil
 }
 
-type mockNominatedStore struct{}
+func (m *mockCategoryStore) Update(ctx context.Context, c *models.Category) error {
+	m.updated = c
+	return nil
+}
+func (m *mockCategoryStore) SetPoints(ctx context.Context, ceremonyID string, points map[string]int) error {
+	for id := range points {
+		if ceremonyID != mockCeremonyID || id != "00000000-0000-0000-0000-000000000007" {
+			return sql.ErrNoRows
+		}
+	}
+	m.points = points
+	return nil
+}
 
-func (m *mockNominatedStore) Insert(n *models.Nominated) (string, error) {
+func (m *mockCategoryStore) SetLock(ctx context.Context, id string, locked bool, locksAt *time.Time) error {
+	m.locked, m.locksAt = locked, locksAt
+	return nil
+}
+func (m *mockCategoryStore) Delete(ctx context.Context, id string, force bool) error { return nil }
+
+// mockNominatedStore reports its nominee as voted (refusing Delete without
+// force) when votes is set, and records forced deletes.
+type mockNominatedStore struct {
+	votes   int
+	deleted bool
+}
+
+func (m *mockNominatedStore) Insert(ctx context.Context, n *models.Nominated) (string, error) {
 	return "00000000-0000-0000-0000-000000000011", nil
 }
-func (m *mockNominatedStore) InsertMany(ns []models.Nominated) ([]string, error) {
+func (m *mockNominatedStore) InsertMany(ctx context.Context, ns []models.Nominated) ([]string, error) {
 	ids := make([]string, 0, len(ns))
 	for i := range ns {
 		ids = append(ids, fmt.Sprintf("00000000-0000-0000-0000-0000000003%02d", i))
 	}
 	return ids, nil
 }
-func (m *mockNominatedStore) Get(id string) (*models.Nominated, error) {
+func (m *mockNominatedStore) Get(ctx context.Context, id string) (*models.Nominated, error) {
 	if id != "00000000-0000-0000-0000-000000000011" {
 		return nil, nil
 	}
-	return &models.Nominated{ID: "00000000-0000-0000-0000-000000000011", MovieID: "1", CategoryID: "1", Name: "Nominee"}, nil
+	return &models.Nominated{ID: "00000000-0000-0000-0000-000000000011", MovieID: "1", CategoryID: "00000000-0000-0000-0000-000000000007", Name: "Nominee"}, nil
 }
-func (m *mockNominatedStore) List() ([]models.Nominated, error) {
+func (m *mockNominatedStore) List(ctx context.Context, ceremonyID string) ([]models.Nominated, error) {
 	return []models.Nominated{{ID: "00000000-0000-0000-0000-000000000011", MovieID: "1", CategoryID: "1", Name: "Nominee"}}, nil
 }
+func (m *mockNominatedStore) ListByCategory(ctx context.Context, categoryID string) ([]models.Nominated, error) {
+	return []models.Nominated{}, nil
+}
+func (m *mockNominatedStore) ListPage(ctx context.Context, f store.NominatedFilter, p store.Page) ([]models.Nominated, string, error) {
+	out, err := m.List(ctx, f.CeremonyID)
+	return out, "", err
+}
+func (m *mockNominatedStore) Update(ctx context.Context, n *models.Nominated) error { return nil }
+func (m *mockNominatedStore) Delete(ctx context.Context, id string, force bool) error {
+	if id != "00000000-0000-0000-0000-000000000011" {
+		return sql.ErrNoRows
+	}
+	if m.votes > 0 && !force {
+		return &store.InUseError{Votes: m.votes}
+	}
+	m.deleted = true
+	return nil
+}
 
-type mockUserStore struct{}
+// mockUserStore returns users from an optional id->user map.
+type mockUserStore struct {
+	users map[string]*models.User
+}
 
-func (m *mockUserStore) Insert(u *models.User) (string, error) {
+func (m *mockUserStore) Insert(ctx context.Context, u *models.User) (string, error) {
 	return "00000000-0000-0000-0000-000000000000", nil
 }
-func (m *mockUserStore) GetByID(id string) (*models.User, error)       { return nil, nil }
-func (m *mockUserStore) GetByEmail(email string) (*models.User, error) { return nil, nil }
-func (m *mockUserStore) List() ([]models.User, error)                  { return []models.User{}, nil }
+func (m *mockUserStore) GetByID(ctx context.Context, id string) (*models.User, error) {
+	return m.users[id], nil
+}
+func (m *mockUserStore) GetByEmail(ctx context.Context, email string) (*models.User, error) {
+	return nil, nil
+}
+func (m *mockUserStore) List(ctx context.Context) ([]models.User, error) { return []models.User{}, nil }
+func (m *mockUserStore) ListPage(ctx context.Context, f store.UserFilter, p store.Page) ([]models.User, string, error) {
+	return []models.User{}, "", nil
+}
+func (m *mockUserStore) SetRole(ctx context.Context, id, role string) error {
+	if u := m.users[id]; u != nil {
+		u.Role = role
+	}
+	return nil
+}
+func (m *mockUserStore) PromoteFirstAdmin(ctx context.Context, id string) (bool, error) {
+	for _, u := range m.users {
+		if u.Role == RoleAdmin {
+			return false, nil
+		}
+	}
+	return true, m.SetRole(ctx, id, RoleAdmin)
+}
 
 type mockVoteStore struct{}
 
-func (m *mockVoteStore) Insert(v *models.Vote) (int64, bool, error)      { return 123, true, nil }
-func (m *mockVoteStore) Get(id int64) (*models.Vote, error)              { return nil, nil }
-func (m *mockVoteStore) ListByUser(userID string) ([]models.Vote, error) { return []models.Vote{}, nil }
+func (m *mockVoteStore) Insert(ctx context.Context, v *models.Vote) (int64, string, error) {
+	return 123, models.VoteCreated, nil
+}
+func (m *mockVoteStore) InsertBallot(ctx context.Context, votes []models.Vote) ([]string, error) {
+	actions := make([]string, len(votes))
+	for i := range votes {
+		votes[i].ID, actions[i] = int64(123+i), models.VoteCreated
+	}
+	return actions, nil
+}
+func (m *mockVoteStore) Get(ctx context.Context, id int64) (*models.Vote, error) { return nil, nil }
+func (m *mockVoteStore) ListByUser(ctx context.Context, userID, ceremonyID string) ([]models.Vote, error) {
+	return []models.Vote{}, nil
+}
+func (m *mockVoteStore) GetUserScore(ctx context.Context, userID, ceremonyID string) (int, int, error) {
+	return 0, 0, nil
+}
+func (m *mockVoteStore) ScoredVotes(ctx context.Context, userID, ceremonyID string) ([]store.ScoredVote, error) {
+	return []store.ScoredVote{}, nil
+}
+func (m *mockVoteStore) GetAllScores(ctx context.Context, ceremonyID string) ([]store.UserScore, error) {
+	return []store.UserScore{}, nil
+}
+func (m *mockVoteStore) ListPage(ctx context.Context, f store.VoteFilter, p store.Page) ([]models.Vote, string, error) {
+	return []models.Vote{}, "", nil
+}
+func (m *mockVoteStore) ScoresPage(ctx context.Context, f store.ScoreFilter, p store.Page) ([]store.UserScore, string, error) {
+	return []store.UserScore{}, "", nil
+}
+func (m *mockVoteStore) ListEvents(ctx context.Context, userID, ceremonyID string) ([]models.VoteEvent, error) {
+	return []models.VoteEvent{
+		{ID: 1, UserID: userID, CategoryID: "c1", NominatedID: "n1", Action: models.VoteCreated},
+		{ID: 2, UserID: userID, CategoryID: "c1", NominatedID: "n2", PreviousNominatedID: "n1", Action: models.VoteChanged},
+		{ID: 3, UserID: userID, CategoryID: "c1", NominatedID: "n1", PreviousNominatedID: "n2", Action: models.VoteChanged},
+		{ID: 4, UserID: userID, CategoryID: "c2", NominatedID: "n5", Action: models.VoteCreated},
+	}, nil
+}
+func (m *mockVoteStore) GetLeagueScores(ctx context.Context, leagueID, ceremonyID string) ([]store.UserScore, error) {
+	return []store.UserScore{{UserID: "u-1", Nickname: "fan"}}, nil
+}
+
+type mockWinnerStore struct{}
+
+func (m *mockWinnerStore) Insert(ctx context.Context, w *models.Winner) (string, error) {
+	return "00000000-0000-0000-0000-000000000021", nil
+}
+func (m *mockWinnerStore) Get(ctx context.Context, id string) (*models.Winner, error) {
+	return nil, nil
+}
+func (m *mockWinnerStore) GetByNominated(ctx context.Context, nominatedID string) (*models.Winner, error) {
+	return nil, nil
+}
+func (m *mockWinnerStore) List(ctx context.Context, ceremonyID string) ([]models.Winner, error) {
+	return []models.Winner{}, nil
+}
+func (m *mockWinnerStore) Delete(ctx context.Context, id string) error { return nil }
+
+const mockCeremonyID = "00000000-0000-0000-0000-000000002026"
+
+// mockCeremonyStore holds a single 2026 ceremony; activated records SetActive
+// calls and activeLookups counts GetActive calls.
+type mockCeremonyStore struct {
+	deadline      time.Time
+	activated     string
+	activeLookups int
+}
+
+func (m *mockCeremonyStore) ceremony() *models.Ceremony {
+	return &models.Ceremony{ID: mockCeremonyID, Year: 2026, Name: "Oscar 2026", Deadline: m.deadline, Status: models.CeremonyActive}
+}
+func (m *mockCeremonyStore) Insert(ctx context.Context, c *models.Ceremony) (string, error) {
+	return "00000000-0000-0000-0000-000000002027", nil
+}
+func (m *mockCeremonyStore) Get(ctx context.Context, id string) (*models.Ceremony, error) {
+	if id != mockCeremonyID {
+		return nil, nil
+	}
+	return m.ceremony(), nil
+}
+func (m *mockCeremonyStore) GetActive(ctx context.Context) (*models.Ceremony, error) {
+	m.activeLookups++
+	return m.ceremony(), nil
+}
+func (m *mockCeremonyStore) List(ctx context.Context) ([]models.Ceremony, error) {
+	return []models.Ceremony{*m.ceremony()}, nil
+}
+func (m *mockCeremonyStore) SetActive(ctx context.Context, id string) error {
+	m.activated = id
+	return nil
+}
+
+// mockLeagueStore holds leagues by id and members as league id -> user ids.
+type mockLeagueStore struct {
+	leagues map[string]*models.League
+	members map[string]map[string]bool
+}
+
+func (m *mockLeagueStore) Insert(ctx context.Context, l *models.League) (string, error) {
+	if m.leagues == nil {
+		m.leagues = map[string]*models.League{}
+		m.members = map[string]map[string]bool{}
+	}
+	l.ID = fmt.Sprintf("league-%d", len(m.leagues)+1)
+	l.InviteCode = fmt.Sprintf("CODE%d", len(m.leagues)+1)
+	m.leagues[l.ID] = l
+	m.members[l.ID] = map[string]bool{l.OwnerID: true}
+	return l.ID, nil
+}
+func (m *mockLeagueStore) Get(ctx context.Context, id string) (*models.League, error) {
+	return m.leagues[id], nil
+}
+func (m *mockLeagueStore) GetByInviteCode(ctx context.Context, code string) (*models.League, error) {
+	for _, l := range m.leagues {
+		if l.InviteCode == code {
+			return l, nil
+		}
+	}
+	return nil, nil
+}
+func (m *mockLeagueStore) ListByUser(ctx context.Context, userID string) ([]models.League, error) {
+	out := []models.League{}
+	for id, ms := range m.members {
+		if ms[userID] {
+			out = append(out, *m.leagues[id])
+		}
+	}
+	return out, nil
+}
+func (m *mockLeagueStore) AddMember(ctx context.Context, leagueID, userID string) error {
+	m.members[leagueID][userID] = true
+	return nil
+}
+func (m *mockLeagueStore) RemoveMember(ctx context.Context, leagueID, userID string) error {
+	delete(m.members[leagueID], userID)
+	return nil
+}
+func (m *mockLeagueStore) IsMember(ctx context.Context, leagueID, userID string) (bool, error) {
+	return m.members[leagueID][userID], nil
+}
+
+// mockSessionStore keeps sessions in a map keyed by id. Get fails with err
+// when it is set.
+type mockSessionStore struct {
+	sessions map[string]*models.Session
+	err      error
+}
+
+func (m *mockSessionStore) Insert(ctx context.Context, s *models.Session) (string, error) {
+	if m.sessions == nil {
+		m.sessions = make(map[string]*models.Session)
+	}
+	cp := *s
+	cp.ID = fmt.Sprintf("s-%d", len(m.sessions)+1)
+	m.sessions[cp.ID] = &cp
+	return cp.ID, nil
+}
+func (m *mockSessionStore) Get(ctx context.Context, id string) (*models.Session, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	if s := m.sessions[id]; s != nil {
+		cp := *s
+		return &cp, nil
+	}
+	return nil, nil
+}
+func (m *mockSessionStore) GetByTokenHash(ctx context.Context, hash string) (*models.Session, error) {
+	for _, s := range m.sessions {
+		if s.TokenHash == hash || s.PreviousTokenHash == hash {
+			cp := *s
+			return &cp, nil
+		}
+	}
+	return nil, nil
+}
+func (m *mockSessionStore) Rotate(ctx context.Context, id, oldHash, newHash string, expiresAt time.Time) error {
+	s := m.sessions[id]
+	if s == nil || s.TokenHash != oldHash || s.RevokedAt != nil {
+		return sql.ErrNoRows
+	}
+	s.PreviousTokenHash, s.TokenHash, s.RotatedAt, s.ExpiresAt = oldHash, newHash, time.Now(), expiresAt
+	return nil
+}
+func (m *mockSessionStore) Touch(ctx context.Context, id string, at time.Time) error { return nil }
+func (m *mockSessionStore) ListByUser(ctx context.Context, userID string) ([]models.Session, error) {
+	out := []models.Session{}
+	for _, s := range m.sessions {
+		if s.UserID == userID && s.IsActive(time.Now()) {
+			out = append(out, *s)
+		}
+	}
+	return out, nil
+}
+func (m *mockSessionStore) Revoke(ctx context.Context, id string) error {
+	s := m.sessions[id]
+	if s == nil {
+		return sql.ErrNoRows
+	}
+	now := time.Now()
+	s.RevokedAt = &now
+	return nil
+}
+func (m *mockSessionStore) RevokeAllByUser(ctx context.Context, userID string) (int, error) {
+	n := 0
+	for _, s := range m.sessions {
+		if s.UserID == userID && s.RevokedAt == nil {
+			now := time.Now()
+			s.RevokedAt = &now
+			n++
+		}
+	}
+	return n, nil
+}
+
+// testAccessToken starts a session for u and returns its access token.
+func testAccessToken(t *testing.T, h *Handler, u *models.User) string {
+	t.Helper()
+	tokens, err := h.startSession(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/login", nil), u)
+	if err != nil {
+		t.Fatalf("start session: %v", err)
+	}
+	return tokens.AccessToken
+}
+
+// newTestHandler wires a Handler with the mock stores above.
+func newTestHandler() *Handler {
+	return New(&mockMovieStore{}, &mockCategoryStore{}, &mockNominatedStore{}, &mockUserStore{}, &mockVoteStore{}, &mockWinnerStore{}, &mockCeremonyStore{deadline: time.Now().Add(time.Hour)}, &mockLeagueStore{}, &mockSessionStore{}, nil, "devsecret")
+}
 
 func TestAddMovie(t *testing.T) {
-	h := New(&mockMovieStore{}, &mockCategoryStore{}, &mockNominatedStore{}, &mockUserStore{}, &mockVoteStore{}, nil, "devsecret")
+	h := newTestHandler()
 	payload := models.Movie{Title: "T"}
 	b, _ := json.Marshal(payload)
 	req := httptest.NewRequest(http.MethodPost, "/add_movie", bytes.NewReader(b))
@@ -116,7 +458,7 @@ func TestAddMovie(t *testing.T) {
 }
 
 func TestListAndGet(t *testing.T) {
-	h := New(&mockMovieStore{}, &mockCategoryStore{}, &mockNominatedStore{}, &mockUserStore{}, &mockVoteStore{}, nil, "devsecret")
+	h := newTestHandler()
 	rr := httptest.NewRecorder()
 	req := httptest.NewRequest(http.MethodGet, "/movies", nil)
 	h.ListMovies(rr, req)
@@ -124,7 +466,7 @@ func TestListAndGet(t *testing.T) {
 		t.Fatalf("list: expected 200 got %d", rr.Code)
 	}
 	rr = httptest.NewRecorder()
-	req = httptest.NewRequest(http.MethodGet, "/movies?id=42", nil)
+	req = httptest.NewRequest(http.MethodGet, "/movies?id=00000000-0000-0000-0000-000000000042", nil)
 	h.GetMovie(rr, req)
 	if rr.Code != http.StatusOK {
 		t.Fatalf("get: expected 200 got %d", rr.Code)
@@ -132,7 +474,7 @@ func TestListAndGet(t *testing.T) {
 }
 
 func TestAddCategory(t *testing.T) {
-	h := New(&mockMovieStore{}, &mockCategoryStore{}, &mockNominatedStore{}, &mockUserStore{}, &mockVoteStore{}, nil, "devsecret")
+	h := newTestHandler()
 	payload := models.Category{Name: "C"}
 	b, _ := json.Marshal(payload)
 	req := httptest.NewRequest(http.MethodPost, "/add_category", bytes.NewReader(b))
@@ -153,8 +495,45 @@ func TestAddCategory(t *testing.T) {
 	}
 }
 
+func TestAddCategoryPoints(t *testing.T) {
+	h := newTestHandler()
+	for _, tc := range []struct {
+		body   string
+		code   int
+		points int
+	}{
+		{`{"name": "C"}`, http.StatusCreated, store.DefaultCategoryPoints},
+		{`{"name": "C", "points": 0}`, http.StatusCreated, 0},
+		{`{"name": "C", "points": 3}`, http.StatusCreated, 3},
+		{`{"name": "C", "points": -1}`, http.StatusBadRequest, 0},
+	} {
+		req := httptest.NewRequest(http.MethodPost, "/add_category", strings.NewReader(tc.body))
+		req.AddCookie(&http.Cookie{Name: "csrf_token", Value: "testcsrf"})
+		req.Header.Set("X-CSRF-Token", "testcsrf")
+		rr := httptest.NewRecorder()
+		h.AddCategory(rr, req)
+		if rr.Code != tc.code {
+			t.Fatalf("%s: expected %d got %d: %s", tc.body, tc.code, rr.Code, rr.Body.String())
+		}
+		var got models.Category
+		if tc.code == http.StatusCreated && (json.Unmarshal(rr.Body.Bytes(), &got) != nil || got.Points != tc.points) {
+			t.Fatalf("%s: points = %d, want %d", tc.body, got.Points, tc.points)
+		}
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/add_categories", strings.NewReader(`[{"name": "A", "points": 0}, {"name": "B"}]`))
+	req.AddCookie(&http.Cookie{Name: "csrf_token", Value: "testcsrf"})
+	req.Header.Set("X-CSRF-Token", "testcsrf")
+	rr := httptest.NewRecorder()
+	h.AddCategories(rr, req)
+	var got []models.Category
+	if err := json.Unmarshal(rr.Body.Bytes(), &got); err != nil || rr.Code != http.StatusCreated || len(got) != 2 || got[0].Points != 0 || got[1].Points != store.DefaultCategoryPoints {
+		t.Fatalf("batch: got %d %s", rr.Code, rr.Body.String())
+	}
+}
+
 func TestListAndGetCategories(t *testing.T) {
-	h := New(&mockMovieStore{}, &mockCategoryStore{}, &mockNominatedStore{}, &mockUserStore{}, &mockVoteStore{}, nil, "devsecret")
+	h := newTestHandler()
 	rr := httptest.NewRecorder()
 	req := httptest.NewRequest(http.MethodGet, "/categories", nil)
 	h.ListCategories(rr, req)
@@ -162,7 +541,7 @@ func TestListAndGetCategories(t *testing.T) {
 		t.Fatalf("list categories: expected 200 got %d", rr.Code)
 	}
 	rr = httptest.NewRecorder()
-	req = httptest.NewRequest(http.MethodGet, "/categories?id=7", nil)
+	req = httptest.NewRequest(http.MethodGet, "/categories?id=00000000-0000-0000-0000-000000000007", nil)
 	h.GetCategory(rr, req)
 	if rr.Code != http.StatusOK {
 		t.Fatalf("get category: expected 200 got %d", rr.Code)
@@ -170,7 +549,7 @@ func TestListAndGetCategories(t *testing.T) {
 }
 
 func TestAddCategories(t *testing.T) {
-	h := New(&mockMovieStore{}, &mockCategoryStore{}, &mockNominatedStore{}, &mockUserStore{}, &mockVoteStore{}, nil, "devsecret")
+	h := newTestHandler()
 	payload := []models.Category{{Name: "A"}, {Name: "B"}}
 	b, _ := json.Marshal(payload)
 	req := httptest.NewRequest(http.MethodPost, "/add_categories", bytes.NewReader(b))
@@ -195,7 +574,7 @@ func TestAddCategories(t *testing.T) {
 }
 
 func TestAddMovies(t *testing.T) {
-	h := New(&mockMovieStore{}, &mockCategoryStore{}, &mockNominatedStore{}, &mockUserStore{}, &mockVoteStore{}, nil, "devsecret")
+	h := newTestHandler()
 	payload := []models.Movie{{Title: "A"}, {Title: "B"}}
 	b, _ := json.Marshal(payload)
 	req := httptest.NewRequest(http.MethodPost, "/add_movies", bytes.NewReader(b))
@@ -218,3 +597,1191 @@ func TestAddMovies(t *testing.T) {
 		t.Fatalf("expected assigned ids, got %+v", got)
 	}
 }
+
+func TestListCategoriesScopedByCeremony(t *testing.T) {
+	h := newTestHandler()
+	rr := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/categories?ceremony_id="+mockCeremonyID, nil)
+	h.ListCategories(rr, req)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200 got %d", rr.Code)
+	}
+	var got struct {
+		Items      []models.Category `json:"items"`
+		NextCursor string            `json:"next_cursor"`
+	}
+	if err := json.Unmarshal(rr.Body.Bytes(), &got); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if len(got.Items) != 1 || got.Items[0].CeremonyID != mockCeremonyID || got.NextCursor != "" {
+		t.Fatalf("expected the ceremony's category, got %+v", got)
+	}
+
+	rr = httptest.NewRecorder()
+	req = httptest.NewRequest(http.MethodGet, "/categories?ceremony_id=unknown", nil)
+	h.ListCategories(rr, req)
+	if rr.Code != http.StatusNotFound {
+		t.Fatalf("unknown ceremony: expected 404 got %d", rr.Code)
+	}
+}
+
+func TestListPageParams(t *testing.T) {
+	h := newTestHandler()
+	for _, tc := range []struct {
+		query string
+		code  int
+	}{
+		{"limit=10", http.StatusOK},
+		{"limit=0", http.StatusBadRequest},
+		{"limit=abc", http.StatusBadRequest},
+		{"cursor=not-a-cursor!", http.StatusBadRequest},
+		{"cursor=" + store.EncodeCursor(1), http.StatusOK},
+	} {
+		rr := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/categories?ceremony_id="+mockCeremonyID+"&"+tc.query, nil)
+		h.ListCategories(rr, req)
+		if rr.Code != tc.code {
+			t.Errorf("%s: expected %d got %d, body=%s", tc.query, tc.code, rr.Code, rr.Body.String())
+		}
+	}
+}
+
+func TestActivateCeremony(t *testing.T) {
+	cers := &mockCeremonyStore{deadline: time.Now().Add(time.Hour)}
+	h := New(&mockMovieStore{}, &mockCategoryStore{}, &mockNominatedStore{}, &mockUserStore{}, &mockVoteStore{}, &mockWinnerStore{}, cers, &mockLeagueStore{}, &mockSessionStore{}, nil, "devsecret")
+	req := httptest.NewRequest(http.MethodPost, "/ceremonies/activate?id="+mockCeremonyID, nil)
+	req.AddCookie(&http.Cookie{Name: "csrf_token", Value: "testcsrf"})
+	req.Header.Set("X-CSRF-Token", "testcsrf")
+	rr := httptest.NewRecorder()
+	h.ActivateCeremony(rr, req)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200 got %d, body=%s", rr.Code, rr.Body.String())
+	}
+	if cers.activated != mockCeremonyID {
+		t.Fatalf("expected SetActive(%s), got %q", mockCeremonyID, cers.activated)
+	}
+}
+
+func TestAddVoteAfterCeremonyDeadline(t *testing.T) {
+	cers := &mockCeremonyStore{deadline: time.Now().Add(-time.Hour)}
+	h := New(&mockMovieStore{}, &mockCategoryStore{}, &mockNominatedStore{}, &mockUserStore{}, &mockVoteStore{}, &mockWinnerStore{}, cers, &mockLeagueStore{}, &mockSessionStore{}, nil, "devsecret")
+	req := httptest.NewRequest(http.MethodPost, "/add_vote", bytes.NewReader([]byte(`{"nominated_id":"00000000-0000-0000-0000-000000000011"}`)))
+	rr := httptest.NewRecorder()
+	h.AddVote(rr, req)
+	if rr.Code != http.StatusForbidden {
+		t.Fatalf("expected 403 got %d", rr.Code)
+	}
+}
+
+func TestRequireRoleAdmin(t *testing.T) {
+	users := map[string]*models.User{
+		"u-admin": {ID: "u-admin", Nickname: "boss", Role: RoleAdmin},
+		"u-user":  {ID: "u-user", Nickname: "fan", Role: RoleUser},
+	}
+	h := New(&mockMovieStore{}, &mockCategoryStore{}, &mockNominatedStore{}, &mockUserStore{users: users}, &mockVoteStore{}, &mockWinnerStore{}, &mockCeremonyStore{}, &mockLeagueStore{}, &mockSessionStore{}, nil, "devsecret")
+	ok := func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusOK) }
+	protected := h.RequireRole(RoleAdmin)(ok)
+
+	cases := []struct {
+		name   string
+		userID string
+		want   int
+	}{
+		{"anonymous", "", http.StatusUnauthorized},
+		{"user", "u-user", http.StatusForbidden},
+		{"admin", "u-admin", http.StatusOK},
+	}
+	for _, tc := range cases {
+		req := httptest.NewRequest(http.MethodPost, "/add_movie", nil)
+		if tc.userID != "" {
+			tok := testAccessToken(t, h, users[tc.userID])
+			req.Header.Set("Authorization", "Bearer "+tok)
+		}
+		rr := httptest.NewRecorder()
+		protected(rr, req)
+		if rr.Code != tc.want {
+			t.Fatalf("%s: expected %d got %d", tc.name, tc.want, rr.Code)
+		}
+	}
+}
+
+func TestBootstrapAdminOnlyOnce(t *testing.T) {
+	users := map[string]*models.User{
+		"u-1": {ID: "u-1", Nickname: "first", Role: RoleUser},
+		"u-2": {ID: "u-2", Nickname: "second", Role: RoleUser},
+	}
+	h := New(&mockMovieStore{}, &mockCategoryStore{}, &mockNominatedStore{}, &mockUserStore{users: users}, &mockVoteStore{}, &mockWinnerStore{}, &mockCeremonyStore{}, &mockLeagueStore{}, &mockSessionStore{}, nil, "devsecret")
+	bootstrap := h.RequireAuth(h.BootstrapAdmin)
+	call := func(uid string) int {
+		tok := testAccessToken(t, h, users[uid])
+		req := httptest.NewRequest(http.MethodPost, "/admin/bootstrap", nil)
+		req.Header.Set("Authorization", "Bearer "+tok)
+		req.AddCookie(&http.Cookie{Name: "csrf_token", Value: "testcsrf"})
+		req.Header.Set("X-CSRF-Token", "testcsrf")
+		rr := httptest.NewRecorder()
+		bootstrap(rr, req)
+		return rr.Code
+	}
+	if code := call("u-1"); code != http.StatusOK {
+		t.Fatalf("first bootstrap: expected 200 got %d", code)
+	}
+	if users["u-1"].Role != RoleAdmin {
+		t.Fatalf("expected u-1 to be admin, got %q", users["u-1"].Role)
+	}
+	if code := call("u-2"); code != http.StatusConflict {
+		t.Fatalf("second bootstrap: expected 409 got %d", code)
+	}
+}
+
+func TestUpdateCategoryPoints(t *testing.T) {
+	cs := &mockCategoryStore{}
+	h := New(&mockMovieStore{}, cs, &mockNominatedStore{}, &mockUserStore{}, &mockVoteStore{}, &mockWinnerStore{}, &mockCeremonyStore{}, &mockLeagueStore{}, &mockSessionStore{}, nil, "devsecret")
+	b := []byte(`{"id":"00000000-0000-0000-0000-000000000007","points":5}`)
+	req := httptest.NewRequest(http.MethodPost, "/update_category", bytes.NewReader(b))
+	req.AddCookie(&http.Cookie{Name: "csrf_token", Value: "testcsrf"})
+	req.Header.Set("X-CSRF-Token", "testcsrf")
+	rr := httptest.NewRecorder()
+	h.UpdateCategory(rr, req)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200 got %d, body=%s", rr.Code, rr.Body.String())
+	}
+	if cs.updated == nil || cs.updated.Points != 5 || cs.updated.Name != "MockCat" {
+		t.Fatalf("expected points updated and name kept, got %+v", cs.updated)
+	}
+
+	b = []byte(`{"id":"00000000-0000-0000-0000-000000000007","points":-1}`)
+	req = httptest.NewRequest(http.MethodPost, "/update_category", bytes.NewReader(b))
+	req.AddCookie(&http.Cookie{Name: "csrf_token", Value: "testcsrf"})
+	req.Header.Set("X-CSRF-Token", "testcsrf")
+	rr = httptest.NewRecorder()
+	h.UpdateCategory(rr, req)
+	if rr.Code != http.StatusBadRequest {
+		t.Fatalf("negative points: expected 400 got %d", rr.Code)
+	}
+}
+
+func TestRecomputeScoresAppliesWeights(t *testing.T) {
+	cs := &mockCategoryStore{}
+	h := New(&mockMovieStore{}, cs, &mockNominatedStore{}, &mockUserStore{}, &mockVoteStore{}, &mockWinnerStore{}, &mockCeremonyStore{}, &mockLeagueStore{}, &mockSessionStore{}, nil, "devsecret")
+	b := []byte(`{"points":{"00000000-0000-0000-0000-000000000007":4}}`)
+	req := httptest.NewRequest(http.MethodPost, "/admin/scores/recompute", bytes.NewReader(b))
+	req.AddCookie(&http.Cookie{Name: "csrf_token", Value: "testcsrf"})
+	req.Header.Set("X-CSRF-Token", "testcsrf")
+	rr := httptest.NewRecorder()
+	h.RecomputeScores(rr, req)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200 got %d, body=%s", rr.Code, rr.Body.String())
+	}
+	if cs.points["00000000-0000-0000-0000-000000000007"] != 4 {
+		t.Fatalf("expected weights applied, got %+v", cs.points)
+	}
+
+	for body, code := range map[string]int{
+		// a category outside the resolved ceremony
+		`{"points":{"00000000-0000-0000-0000-000000000099":2}}`:  http.StatusNotFound,
+		`{"points":{"00000000-0000-0000-0000-000000000007":-1}}`: http.StatusBadRequest,
+	} {
+		req := httptest.NewRequest(http.MethodPost, "/admin/scores/recompute", strings.NewReader(body))
+		req.AddCookie(&http.Cookie{Name: "csrf_token", Value: "testcsrf"})
+		req.Header.Set("X-CSRF-Token", "testcsrf")
+		rr := httptest.NewRecorder()
+		h.RecomputeScores(rr, req)
+		if rr.Code != code {
+			t.Fatalf("%s: expected %d got %d, body=%s", body, code, rr.Code, rr.Body.String())
+		}
+		if code == http.StatusBadRequest && !strings.Contains(rr.Body.String(), `"points.00000000-0000-0000-0000-000000000007"`) {
+			t.Fatalf("%s: expected the field in the error, body=%s", body, rr.Body.String())
+		}
+		if cs.points["00000000-0000-0000-0000-000000000007"] != 4 {
+			t.Fatalf("%s: weights changed to %+v", body, cs.points)
+		}
+	}
+}
+
+func TestAddVoteInLockedCategory(t *testing.T) {
+	users := map[string]*models.User{"u-1": {ID: "u-1", Nickname: "fan", Role: RoleUser}}
+	cs := &mockCategoryStore{}
+	cers := &mockCeremonyStore{deadline: time.Now().Add(time.Hour)}
+	h := New(&mockMovieStore{}, cs, &mockNominatedStore{}, &mockUserStore{users: users}, &mockVoteStore{}, &mockWinnerStore{}, cers, &mockLeagueStore{}, &mockSessionStore{}, nil, "devsecret")
+	tok := testAccessToken(t, h, users["u-1"])
+	vote := func() int {
+		req := httptest.NewRequest(http.MethodPost, "/add_vote", bytes.NewReader([]byte(`{"nominated_id":"00000000-0000-0000-0000-000000000011"}`)))
+		req.Header.Set("Authorization", "Bearer "+tok)
+		req.AddCookie(&http.Cookie{Name: "csrf_token", Value: "testcsrf"})
+		req.Header.Set("X-CSRF-Token", "testcsrf")
+		rr := httptest.NewRecorder()
+		h.RequireAuth(h.AddVote)(rr, req)
+		return rr.Code
+	}
+	if code := vote(); code != http.StatusCreated {
+		t.Fatalf("open category: expected 201 got %d", code)
+	}
+	// the ceremony is resolved once per vote
+	if cers.activeLookups != 1 {
+		t.Fatalf("expected 1 active ceremony lookup, got %d", cers.activeLookups)
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/lock_category", bytes.NewReader([]byte(`{"id":"00000000-0000-0000-0000-000000000007"}`)))
+	req.AddCookie(&http.Cookie{Name: "csrf_token", Value: "testcsrf"})
+	req.Header.Set("X-CSRF-Token", "testcsrf")
+	rr := httptest.NewRecorder()
+	h.LockCategory(rr, req)
+	if rr.Code != http.StatusOK || !cs.locked {
+		t.Fatalf("lock: expected 200 and locked, got %d locked=%v", rr.Code, cs.locked)
+	}
+	if code := vote(); code != http.StatusForbidden {
+		t.Fatalf("locked category: expected 403 got %d", code)
+	}
+
+	rr = httptest.NewRecorder()
+	h.GetDeadline(rr, httptest.NewRequest(http.MethodGet, "/deadline", nil))
+	var got struct {
+		Closed     bool `json:"closed"`
+		Categories []struct {
+			ID     string `json:"id"`
+			Locked bool   `json:"locked"`
+		} `json:"categories"`
+	}
+	if err := json.Unmarshal(rr.Body.Bytes(), &got); err != nil {
+		t.Fatalf("unmarshal deadline: %v", err)
+	}
+	if got.Closed || len(got.Categories) != 1 || !got.Categories[0].Locked {
+		t.Fatalf("expected open ceremony with one locked category, got %+v", got)
+	}
+}
+
+func TestLockCategoryKeepsOmittedFields(t *testing.T) {
+	cs := &mockCategoryStore{}
+	h := New(&mockMovieStore{}, cs, &mockNominatedStore{}, &mockUserStore{}, &mockVoteStore{}, &mockWinnerStore{}, &mockCeremonyStore{deadline: time.Now().Add(time.Hour)}, &mockLeagueStore{}, &mockSessionStore{}, nil, "devsecret")
+	lock := func(body string) {
+		t.Helper()
+		req := httptest.NewRequest(http.MethodPost, "/lock_category", strings.NewReader(body))
+		req.AddCookie(&http.Cookie{Name: "csrf_token", Value: "testcsrf"})
+		req.Header.Set("X-CSRF-Token", "testcsrf")
+		rr := httptest.NewRecorder()
+		h.LockCategory(rr, req)
+		if rr.Code != http.StatusOK {
+			t.Fatalf("%s: expected 200 got %d: %s", body, rr.Code, rr.Body.String())
+		}
+	}
+	at := time.Now().Add(time.Hour).UTC().Truncate(time.Second)
+
+	// schedule only: the category stays open until locks_at
+	lock(`{"id":"00000000-0000-0000-0000-000000000007","locks_at":"` + at.Format(time.RFC3339) + `"}`)
+	if cs.locked || cs.locksAt == nil || !cs.locksAt.Equal(at) {
+		t.Fatalf("schedule: got locked=%v locks_at=%v", cs.locked, cs.locksAt)
+	}
+
+	// lock now, then unlock: the schedule is kept
+	lock(`{"id":"00000000-0000-0000-0000-000000000007"}`)
+	if !cs.locked || cs.locksAt == nil {
+		t.Fatalf("lock: got locked=%v locks_at=%v", cs.locked, cs.locksAt)
+	}
+	lock(`{"id":"00000000-0000-0000-0000-000000000007","locked":false}`)
+	if cs.locked || cs.locksAt == nil || !cs.locksAt.Equal(at) {
+		t.Fatalf("unlock: got locked=%v locks_at=%v", cs.locked, cs.locksAt)
+	}
+
+	// once locks_at passes the category is locked
+	c, _ := cs.Get(context.Background(), "00000000-0000-0000-0000-000000000007")
+	if c.IsLocked(time.Now()) || !c.IsLocked(at) {
+		t.Fatalf("expected locked from %v only, got %+v", at, c)
+	}
+	past := time.Now().Add(-time.Minute).UTC().Format(time.RFC3339)
+	lock(`{"id":"00000000-0000-0000-0000-000000000007","locks_at":"` + past + `"}`)
+	rr := httptest.NewRecorder()
+	h.GetDeadline(rr, httptest.NewRequest(http.MethodGet, "/deadline", nil))
+	var got struct {
+		Categories []struct {
+			Locked bool `json:"locked"`
+		} `json:"categories"`
+	}
+	if err := json.Unmarshal(rr.Body.Bytes(), &got); err != nil || len(got.Categories) != 1 || !got.Categories[0].Locked {
+		t.Fatalf("passed schedule: expected locked, got %s", rr.Body.String())
+	}
+
+	// null clears the schedule
+	lock(`{"id":"00000000-0000-0000-0000-000000000007","locks_at":null}`)
+	if cs.locked || cs.locksAt != nil {
+		t.Fatalf("clear: got locked=%v locks_at=%v", cs.locked, cs.locksAt)
+	}
+}
+
+func TestAddWinnerPublishesEvents(t *testing.T) {
+	h := newTestHandler()
+	ch, unsubscribe := h.events.Subscribe()
+	defer unsubscribe()
+
+	req := httptest.NewRequest(http.MethodPost, "/add_winner", bytes.NewReader([]byte(`{"nominated_id":"00000000-0000-0000-0000-000000000011"}`)))
+	req.AddCookie(&http.Cookie{Name: "csrf_token", Value: "testcsrf"})
+	req.Header.Set("X-CSRF-Token", "testcsrf")
+	rr := httptest.NewRecorder()
+	h.AddWinner(rr, req)
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("expected 201 got %d, body=%s", rr.Code, rr.Body.String())
+	}
+	for _, want := range []string{events.WinnerAdded, events.LeaderboardUpdated} {
+		select {
+		case e := <-ch:
+			if e.Type != want {
+				t.Fatalf("expected %s event got %s", want, e.Type)
+			}
+		default:
+			t.Fatalf("expected %s event, none published", want)
+		}
+	}
+}
+
+func TestLeaderboardPublishedOnlyByMutations(t *testing.T) {
+	h := newTestHandler()
+	ch, unsubscribe := h.events.Subscribe()
+	defer unsubscribe()
+
+	rr := httptest.NewRecorder()
+	h.GetLeaderboard(rr, httptest.NewRequest(http.MethodGet, "/leaderboard", nil))
+	if rr.Code != http.StatusOK {
+		t.Fatalf("leaderboard: expected 200 got %d, body=%s", rr.Code, rr.Body.String())
+	}
+	select {
+	case e := <-ch:
+		t.Fatalf("reading the leaderboard published %s", e.Type)
+	default:
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/update_category", strings.NewReader(`{"id":"00000000-0000-0000-0000-000000000007","points":2}`))
+	req.AddCookie(&http.Cookie{Name: "csrf_token", Value: "testcsrf"})
+	req.Header.Set("X-CSRF-Token", "testcsrf")
+	rr = httptest.NewRecorder()
+	h.UpdateCategory(rr, req)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("update: expected 200 got %d, body=%s", rr.Code, rr.Body.String())
+	}
+	select {
+	case e := <-ch:
+		if e.Type != events.LeaderboardUpdated {
+			t.Fatalf("expected %s event got %s", events.LeaderboardUpdated, e.Type)
+		}
+	default:
+		t.Fatalf("expected %s event, none published", events.LeaderboardUpdated)
+	}
+}
+
+func TestServeEventsStreamsPublishedEvents(t *testing.T) {
+	h := newTestHandler()
+	ctx, cancel := context.WithCancel(context.Background())
+	req := httptest.NewRequest(http.MethodGet, "/events", nil).WithContext(ctx)
+	rr := httptest.NewRecorder()
+	done := make(chan struct{})
+	go func() {
+		h.ServeEvents(rr, req)
+		close(done)
+	}()
+	for i := 0; h.events.Subscribers() == 0; i++ {
+		if i > 100 {
+			t.Fatalf("stream never subscribed")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	h.events.Publish(events.Event{Type: events.CategoryLocked, Data: map[string]string{"id": "c1"}})
+	// give the stream a moment to write the event before closing it
+	time.Sleep(50 * time.Millisecond)
+	cancel()
+	<-done
+	body := rr.Body.String()
+	if !strings.Contains(body, "event: category_locked\ndata: {\"id\":\"c1\"}\n\n") {
+		t.Fatalf("unexpected stream body %q", body)
+	}
+	if ct := rr.Header().Get("Content-Type"); ct != "text/event-stream" {
+		t.Fatalf("expected text/event-stream got %q", ct)
+	}
+}
+
+func TestCloseEndsEventStreams(t *testing.T) {
+	h := newTestHandler()
+	done := make(chan struct{})
+	go func() {
+		h.ServeEvents(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/events", nil))
+		close(done)
+	}()
+	for i := 0; h.events.Subscribers() == 0; i++ {
+		if i > 100 {
+			t.Fatalf("stream never subscribed")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	h.Close()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("stream still open after Close")
+	}
+}
+
+func TestLeagueJoinAndLeaderboard(t *testing.T) {
+	users := map[string]*models.User{
+		"u-owner": {ID: "u-owner", Nickname: "owner", Role: RoleUser},
+		"u-1":     {ID: "u-1", Nickname: "fan", Role: RoleUser},
+	}
+	h := New(&mockMovieStore{}, &mockCategoryStore{}, &mockNominatedStore{}, &mockUserStore{users: users}, &mockVoteStore{}, &mockWinnerStore{}, &mockCeremonyStore{}, &mockLeagueStore{}, &mockSessionStore{}, nil, "devsecret")
+	routes := h.Routes()
+	do := func(uid, method, target, body string) *httptest.ResponseRecorder {
+		tok := testAccessToken(t, h, users[uid])
+		req := httptest.NewRequest(method, target, strings.NewReader(body))
+		req.Header.Set("Authorization", "Bearer "+tok)
+		req.AddCookie(&http.Cookie{Name: "csrf_token", Value: "testcsrf"})
+		req.Header.Set("X-CSRF-Token", "testcsrf")
+		rr := httptest.NewRecorder()
+		routes.ServeHTTP(rr, req)
+		return rr
+	}
+
+	rr := do("u-owner", http.MethodPost, "/add_league", `{"name":"Office SP"}`)
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("create: expected 201 got %d, body=%s", rr.Code, rr.Body.String())
+	}
+	var l models.League
+	if err := json.Unmarshal(rr.Body.Bytes(), &l); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+
+	if rr := do("u-1", http.MethodGet, "/leagues/"+l.ID+"/leaderboard", ""); rr.Code != http.StatusNotFound {
+		t.Fatalf("non-member leaderboard: expected 404 got %d", rr.Code)
+	}
+	if rr := do("u-1", http.MethodPost, "/leagues/join", `{"invite_code":"`+strings.ToLower(l.InviteCode)+`"}`); rr.Code != http.StatusOK {
+		t.Fatalf("join: expected 200 got %d, body=%s", rr.Code, rr.Body.String())
+	}
+	if rr := do("u-1", http.MethodGet, "/leagues/"+l.ID+"/leaderboard", ""); rr.Code != http.StatusOK {
+		t.Fatalf("member leaderboard: expected 200 got %d", rr.Code)
+	}
+	if rr := do("u-owner", http.MethodPost, "/leagues/leave?id="+l.ID, ""); rr.Code != http.StatusConflict {
+		t.Fatalf("owner leave: expected 409 got %d", rr.Code)
+	}
+	if rr := do("u-1", http.MethodPost, "/leagues/leave?id="+l.ID, ""); rr.Code != http.StatusNoContent {
+		t.Fatalf("leave: expected 204 got %d", rr.Code)
+	}
+}
+
+func TestVoteHistoryCountsChanges(t *testing.T) {
+	users := map[string]*models.User{"u-1": {ID: "u-1", Nickname: "fan", Role: RoleUser}}
+	h := New(&mockMovieStore{}, &mockCategoryStore{}, &mockNominatedStore{}, &mockUserStore{users: users}, &mockVoteStore{}, &mockWinnerStore{}, &mockCeremonyStore{}, &mockLeagueStore{}, &mockSessionStore{}, nil, "devsecret")
+
+	rr := httptest.NewRecorder()
+	h.GetUserVoteHistory(rr, httptest.NewRequest(http.MethodGet, "/admin/votes/history?user_id=u-1", nil))
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200 got %d, body=%s", rr.Code, rr.Body.String())
+	}
+	var got struct {
+		Events            []models.VoteEvent `json:"events"`
+		Changes           int                `json:"changes"`
+		ChangesByCategory map[string]int     `json:"changes_by_category"`
+	}
+	if err := json.Unmarshal(rr.Body.Bytes(), &got); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if len(got.Events) != 4 || got.Changes != 2 || got.ChangesByCategory["c1"] != 2 {
+		t.Fatalf("unexpected history %+v", got)
+	}
+
+	rr = httptest.NewRecorder()
+	h.GetUserVoteHistory(rr, httptest.NewRequest(http.MethodGet, "/admin/votes/history?user_id=nobody", nil))
+	if rr.Code != http.StatusNotFound {
+		t.Fatalf("unknown user: expected 404 got %d", rr.Code)
+	}
+}
+
+func TestRefreshTokenRotationAndReuse(t *testing.T) {
+	users := map[string]*models.User{"u-1": {ID: "u-1", Nickname: "fan", Role: RoleUser}}
+	ss := &mockSessionStore{}
+	h := New(&mockMovieStore{}, &mockCategoryStore{}, &mockNominatedStore{}, &mockUserStore{users: users}, &mockVoteStore{}, &mockWinnerStore{}, &mockCeremonyStore{}, &mockLeagueStore{}, ss, nil, "devsecret")
+	tokens, err := h.startSession(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/login", nil), users["u-1"])
+	if err != nil {
+		t.Fatalf("start session: %v", err)
+	}
+	refresh := func(tok string) (*httptest.ResponseRecorder, tokenResponse) {
+		rr := httptest.NewRecorder()
+		h.RefreshToken(rr, httptest.NewRequest(http.MethodPost, "/token/refresh", strings.NewReader(`{"refresh_token":"`+tok+`"}`)))
+		var out tokenResponse
+		_ = json.Unmarshal(rr.Body.Bytes(), &out)
+		return rr, out
+	}
+
+	rr, next := refresh(tokens.RefreshToken)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("refresh: expected 200 got %d, body=%s", rr.Code, rr.Body.String())
+	}
+	if next.AccessToken == "" || next.RefreshToken == "" || next.RefreshToken == tokens.RefreshToken {
+		t.Fatalf("expected a new access and refresh token, got %+v", next)
+	}
+
+	// the old token is still accepted right after the rotation, without rotating again
+	if rr, out := refresh(tokens.RefreshToken); rr.Code != http.StatusOK || out.RefreshToken != "" {
+		t.Fatalf("refresh within grace: expected 200 without refresh token, got %d %+v", rr.Code, out)
+	}
+
+	// after the grace period, reusing the old token revokes the whole session
+	for _, s := range ss.sessions {
+		s.RotatedAt = time.Now().Add(-time.Hour)
+	}
+	if rr, _ := refresh(tokens.RefreshToken); rr.Code != http.StatusUnauthorized {
+		t.Fatalf("reuse: expected 401 got %d", rr.Code)
+	}
+	if rr, _ := refresh(next.RefreshToken); rr.Code != http.StatusUnauthorized {
+		t.Fatalf("refresh after reuse: expected 401 got %d", rr.Code)
+	}
+}
+
+func TestLogoutRequiresPostAndCSRF(t *testing.T) {
+	users := map[string]*models.User{"u-1": {ID: "u-1", Nickname: "fan", Role: RoleUser}}
+	ss := &mockSessionStore{}
+	h := New(&mockMovieStore{}, &mockCategoryStore{}, &mockNominatedStore{}, &mockUserStore{users: users}, &mockVoteStore{}, &mockWinnerStore{}, &mockCeremonyStore{}, &mockLeagueStore{}, ss, nil, "devsecret")
+	tokens, err := h.startSession(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/login", nil), users["u-1"])
+	if err != nil {
+		t.Fatalf("start session: %v", err)
+	}
+	routes := h.Routes()
+	logout := func(method, target string, csrf bool) int {
+		req := httptest.NewRequest(method, target, nil)
+		req.AddCookie(&http.Cookie{Name: "refresh_token", Value: tokens.RefreshToken})
+		req.AddCookie(&http.Cookie{Name: "csrf_token", Value: "testcsrf"})
+		if csrf {
+			req.Header.Set("X-CSRF-Token", "testcsrf")
+		}
+		rr := httptest.NewRecorder()
+		routes.ServeHTTP(rr, req)
+		return rr.Code
+	}
+	revoked := func() bool {
+		for _, s := range ss.sessions {
+			return s.RevokedAt != nil
+		}
+		return false
+	}
+
+	// a cross-site <img src=/logout> or form post does not end the session
+	if code := logout(http.MethodGet, "/logout", false); code != http.StatusMethodNotAllowed {
+		t.Fatalf("GET /logout: expected 405 got %d", code)
+	}
+	if code := logout(http.MethodPost, "/logout", false); code != http.StatusForbidden {
+		t.Fatalf("POST /logout without csrf: expected 403 got %d", code)
+	}
+	if revoked() {
+		t.Fatal("session revoked without a csrf token")
+	}
+	if code := logout(http.MethodPost, "/logout", true); code != http.StatusSeeOther || !revoked() {
+		t.Fatalf("POST /logout: expected 303 and a revoked session, got %d revoked=%v", code, revoked())
+	}
+}
+
+func TestRevokedSessionIsRejected(t *testing.T) {
+	users := map[string]*models.User{"u-1": {ID: "u-1", Nickname: "fan", Role: RoleUser}}
+	h := New(&mockMovieStore{}, &mockCategoryStore{}, &mockNominatedStore{}, &mockUserStore{users: users}, &mockVoteStore{}, &mockWinnerStore{}, &mockCeremonyStore{}, &mockLeagueStore{}, &mockSessionStore{}, nil, "devsecret")
+	phone := testAccessToken(t, h, users["u-1"])
+	laptop := testAccessToken(t, h, users["u-1"])
+	do := func(tok string, fn http.HandlerFunc, method, target string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, target, nil)
+		req.Header.Set("Authorization", "Bearer "+tok)
+		req.AddCookie(&http.Cookie{Name: "csrf_token", Value: "testcsrf"})
+		req.Header.Set("X-CSRF-Token", "testcsrf")
+		rr := httptest.NewRecorder()
+		h.RequireAuth(fn)(rr, req)
+		return rr
+	}
+
+	rr := do(laptop, h.ListSessions, http.MethodGet, "/sessions")
+	var sessions []struct {
+		ID      string `json:"id"`
+		Current bool   `json:"current"`
+	}
+	if err := json.Unmarshal(rr.Body.Bytes(), &sessions); err != nil || len(sessions) != 2 {
+		t.Fatalf("list: expected 2 sessions, got %s (%v)", rr.Body.String(), err)
+	}
+	var phoneID string
+	for _, s := range sessions {
+		if !s.Current {
+			phoneID = s.ID
+		}
+	}
+	if rr := do(laptop, h.RevokeSession, http.MethodPost, "/sessions/revoke?id="+phoneID); rr.Code != http.StatusNoContent {
+		t.Fatalf("revoke: expected 204 got %d", rr.Code)
+	}
+	if rr := do(phone, h.Me, http.MethodGet, "/me"); rr.Code != http.StatusUnauthorized {
+		t.Fatalf("revoked session: expected 401 got %d", rr.Code)
+	}
+	if rr := do(laptop, h.Me, http.MethodGet, "/me"); rr.Code != http.StatusOK {
+		t.Fatalf("other session: expected 200 got %d", rr.Code)
+	}
+	if rr := do(laptop, h.RevokeAllSessions, http.MethodPost, "/sessions/revoke_all"); rr.Code != http.StatusOK {
+		t.Fatalf("revoke all: expected 200 got %d", rr.Code)
+	}
+	if rr := do(laptop, h.Me, http.MethodGet, "/me"); rr.Code != http.StatusUnauthorized {
+		t.Fatalf("after revoke all: expected 401 got %d", rr.Code)
+	}
+}
+
+func TestRequireAuthStoreError(t *testing.T) {
+	users := map[string]*models.User{"u-1": {ID: "u-1", Nickname: "fan", Role: RoleUser}}
+	sessions := &mockSessionStore{}
+	h := New(&mockMovieStore{}, &mockCategoryStore{}, &mockNominatedStore{}, &mockUserStore{users: users}, &mockVoteStore{}, &mockWinnerStore{}, &mockCeremonyStore{}, &mockLeagueStore{}, sessions, nil, "devsecret")
+	tok := testAccessToken(t, h, users["u-1"])
+	me := func(tok string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/me", nil)
+		req.Header.Set("Authorization", "Bearer "+tok)
+		rr := httptest.NewRecorder()
+		h.RequireAuth(h.Me)(rr, req)
+		return rr
+	}
+
+	sessions.err = errors.New(`pq: connection to server at "db.internal" failed`)
+	rr := me(tok)
+	if rr.Code != http.StatusInternalServerError || strings.Contains(rr.Body.String(), "db.internal") {
+		t.Fatalf("store error: expected a 500 without the driver message, got %d %s", rr.Code, rr.Body.String())
+	}
+	sessions.err = nil
+	rr = me(tok + "x")
+	if rr.Code != http.StatusUnauthorized || !strings.Contains(rr.Body.String(), `"message":"invalid token"`) {
+		t.Fatalf("bad token: expected a generic 401, got %d %s", rr.Code, rr.Body.String())
+	}
+}
+
+func TestRequireAuthRenewsExpiredCookie(t *testing.T) {
+	users := map[string]*models.User{"u-1": {ID: "u-1", Nickname: "fan", Role: RoleUser}}
+	h := New(&mockMovieStore{}, &mockCategoryStore{}, &mockNominatedStore{}, &mockUserStore{users: users}, &mockVoteStore{}, &mockWinnerStore{}, &mockCeremonyStore{}, &mockLeagueStore{}, &mockSessionStore{}, nil, "devsecret")
+	tokens, err := h.startSession(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/login", nil), users["u-1"])
+	if err != nil {
+		t.Fatalf("start session: %v", err)
+	}
+	req := httptest.NewRequest(http.MethodGet, "/me", nil)
+	req.AddCookie(&http.Cookie{Name: "refresh_token", Value: tokens.RefreshToken})
+	rr := httptest.NewRecorder()
+	h.RequireAuth(h.Me)(rr, req)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200 got %d, body=%s", rr.Code, rr.Body.String())
+	}
+	renewed := map[string]bool{}
+	for _, c := range rr.Result().Cookies() {
+		renewed[c.Name] = c.Value != ""
+	}
+	if !renewed["jwt"] || !renewed["refresh_token"] {
+		t.Fatalf("expected renewed jwt and refresh_token cookies, got %v", rr.Result().Cookies())
+	}
+
+	// a Bearer token is never renewed from cookies
+	req = httptest.NewRequest(http.MethodGet, "/me", nil)
+	req.Header.Set("Authorization", "Bearer expired")
+	req.AddCookie(&http.Cookie{Name: "refresh_token", Value: tokens.RefreshToken})
+	rr = httptest.NewRecorder()
+	h.RequireAuth(h.Me)(rr, req)
+	if rr.Code != http.StatusUnauthorized {
+		t.Fatalf("bearer: expected 401 got %d", rr.Code)
+	}
+}
+
+func TestDeleteNominatedRequiresForceWhenVoted(t *testing.T) {
+	ns := &mockNominatedStore{votes: 2}
+	h := New(&mockMovieStore{}, &mockCategoryStore{}, ns, &mockUserStore{}, &mockVoteStore{}, &mockWinnerStore{}, &mockCeremonyStore{}, &mockLeagueStore{}, &mockSessionStore{}, nil, "devsecret")
+	del := func(query string) int {
+		req := httptest.NewRequest(http.MethodDelete, "/delete_nominated?"+query, nil)
+		req.AddCookie(&http.Cookie{Name: "csrf_token", Value: "testcsrf"})
+		req.Header.Set("X-CSRF-Token", "testcsrf")
+		rr := httptest.NewRecorder()
+		h.DeleteNominated(rr, req)
+		return rr.Code
+	}
+	if code := del("id=00000000-0000-0000-0000-000000000011"); code != http.StatusConflict || ns.deleted {
+		t.Fatalf("voted nominee: expected 409 and no delete, got %d deleted=%v", code, ns.deleted)
+	}
+	if code := del("id=00000000-0000-0000-0000-000000000099"); code != http.StatusNotFound {
+		t.Fatalf("missing nominee: expected 404 got %d", code)
+	}
+	if code := del("id=00000000-0000-0000-0000-000000000011&force=maybe"); code != http.StatusBadRequest {
+		t.Fatalf("bad force: expected 400 got %d", code)
+	}
+	if code := del("id=00000000-0000-0000-0000-000000000011&force=true"); code != http.StatusNoContent || !ns.deleted {
+		t.Fatalf("forced delete: expected 204 got %d deleted=%v", code, ns.deleted)
+	}
+}
+
+func TestUpdateNominatedRejectsUnknownMovie(t *testing.T) {
+	h := newTestHandler()
+	update := func(body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/update_nominated", strings.NewReader(body))
+		req.AddCookie(&http.Cookie{Name: "csrf_token", Value: "testcsrf"})
+		req.Header.Set("X-CSRF-Token", "testcsrf")
+		rr := httptest.NewRecorder()
+		h.UpdateNominated(rr, req)
+		return rr
+	}
+	if rr := update(`{"id":"00000000-0000-0000-0000-000000000011","movie_id":"nope"}`); rr.Code != http.StatusBadRequest {
+		t.Fatalf("unknown movie: expected 400 got %d", rr.Code)
+	}
+	rr := update(`{"id":"00000000-0000-0000-0000-000000000011","name":"Fixed","url_image":"http://img"}`)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200 got %d, body=%s", rr.Code, rr.Body.String())
+	}
+	var n models.Nominated
+	if err := json.Unmarshal(rr.Body.Bytes(), &n); err != nil || n.Name != "Fixed" || n.UrlImage != "http://img" {
+		t.Fatalf("unexpected body %s (%v)", rr.Body.String(), err)
+	}
+}
+
+func TestAPIv1Routes(t *testing.T) {
+	users := map[string]*models.User{"u-admin": {ID: "u-admin", Nickname: "admin", Role: RoleAdmin}}
+	ns := &mockNominatedStore{votes: 1}
+	h := New(&mockMovieStore{}, &mockCategoryStore{}, ns, &mockUserStore{users: users}, &mockVoteStore{}, &mockWinnerStore{}, &mockCeremonyStore{}, &mockLeagueStore{}, &mockSessionStore{}, nil, "devsecret")
+	routes := h.Routes()
+	tok := testAccessToken(t, h, users["u-admin"])
+	do := func(method, target string, authed bool) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, target, nil)
+		if authed {
+			req.Header.Set("Authorization", "Bearer "+tok)
+		}
+		req.AddCookie(&http.Cookie{Name: "csrf_token", Value: "testcsrf"})
+		req.Header.Set("X-CSRF-Token", "testcsrf")
+		rr := httptest.NewRecorder()
+		routes.ServeHTTP(rr, req)
+		return rr
+	}
+
+	rr := do(http.MethodGet, "/api/v1/categories/00000000-0000-0000-0000-000000000007", false)
+	if rr.Code != http.StatusOK || !strings.Contains(rr.Body.String(), "MockCat") {
+		t.Fatalf("get category: expected 200 got %d, body=%s", rr.Code, rr.Body.String())
+	}
+	if rr.Header().Get("Deprecation") != "" {
+		t.Fatalf("api/v1 route must not be marked deprecated")
+	}
+	rr = do(http.MethodGet, "/categories?id=00000000-0000-0000-0000-000000000007", false)
+	if rr.Code != http.StatusOK || rr.Header().Get("Deprecation") != "true" {
+		t.Fatalf("legacy alias: expected 200 with Deprecation, got %d %v", rr.Code, rr.Header())
+	}
+	if rr := do(http.MethodPut, "/api/v1/categories/00000000-0000-0000-0000-000000000007", true); rr.Code != http.StatusMethodNotAllowed || rr.Header().Get("Allow") == "" {
+		t.Fatalf("wrong method: expected 405 with Allow, got %d", rr.Code)
+	}
+	if rr := do(http.MethodDelete, "/api/v1/nominees/00000000-0000-0000-0000-000000000011?force=true", false); rr.Code != http.StatusUnauthorized {
+		t.Fatalf("anonymous delete: expected 401 got %d", rr.Code)
+	}
+	if rr := do(http.MethodDelete, "/api/v1/nominees/00000000-0000-0000-0000-000000000011?force=true", true); rr.Code != http.StatusNoContent || !ns.deleted {
+		t.Fatalf("admin delete: expected 204 got %d, body=%s", rr.Code, rr.Body.String())
+	}
+	if rr := do(http.MethodGet, "/api/v1/nope", false); rr.Code != http.StatusNotFound {
+		t.Fatalf("unknown api path: expected 404 got %d", rr.Code)
+	}
+}
+
+func TestErrorEnvelope(t *testing.T) {
+	for _, tc := range []struct {
+		err    error
+		status int
+		code   string
+	}{
+		{fmt.Errorf("get movie: %w", sql.ErrNoRows), http.StatusNotFound, codeNotFound},
+		{fmt.Errorf("insert movie: %w", store.ErrUniqueViolation), http.StatusConflict, codeConflict},
+		{fmt.Errorf("insert vote: %w", store.ErrForeignKeyViolation), http.StatusConflict, codeInvalidReference},
+		{&store.InUseError{Votes: 2}, http.StatusConflict, codeInUse},
+		{fmt.Errorf("list movies: %w", context.DeadlineExceeded), http.StatusServiceUnavailable, codeTimeout},
+		{fmt.Errorf("pq: relation \"movies\" does not exist"), http.StatusInternalServerError, codeInternal},
+	} {
+		rr := httptest.NewRecorder()
+		rr.Header().Set(requestIDHeader, "req-1")
+		writeStoreError(rr, tc.err)
+		var body struct {
+			Error apiError `json:"error"`
+		}
+		if err := json.Unmarshal(rr.Body.Bytes(), &body); err != nil {
+			t.Fatalf("%v: unmarshal: %v (%s)", tc.err, err, rr.Body.String())
+		}
+		if rr.Code != tc.status || body.Error.Code != tc.code || body.Error.RequestID != "req-1" {
+			t.Errorf("%v: expected %d %s, got %d %+v", tc.err, tc.status, tc.code, rr.Code, body.Error)
+		}
+		if strings.Contains(rr.Body.String(), "pq:") {
+			t.Errorf("%v: driver message leaked: %s", tc.err, rr.Body.String())
+		}
+	}
+}
+
+func TestValidationErrorHasFields(t *testing.T) {
+	h := newTestHandler()
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/register", strings.NewReader(`{"email":"a@b.c"}`))
+	req.Header.Set(requestIDHeader, "abc123")
+	rr := httptest.NewRecorder()
+	h.Routes().ServeHTTP(rr, req)
+	if rr.Code != http.StatusBadRequest || rr.Header().Get("Content-Type") != "application/json" {
+		t.Fatalf("expected 400 json got %d %s", rr.Code, rr.Header().Get("Content-Type"))
+	}
+	var body struct {
+		Error apiError `json:"error"`
+	}
+	if err := json.Unmarshal(rr.Body.Bytes(), &body); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if body.Error.Code != codeValidation || body.Error.Fields["password"] == "" || body.Error.RequestID != "abc123" {
+		t.Fatalf("unexpected error body %+v", body.Error)
+	}
+}
+
+// blockingMovieStore's ListPage waits for its context, like a query that
+// outlives the request's DB deadline.
+type blockingMovieStore struct{ mockMovieStore }
+
+func (m *blockingMovieStore) ListPage(ctx context.Context, f store.MovieFilter, p store.Page) ([]models.Movie, string, error) {
+	<-ctx.Done()
+	return nil, "", fmt.Errorf("list movies: %w", ctx.Err())
+}
+
+func TestDBTimeout(t *testing.T) {
+	h := newTestHandler()
+	h.movieStore = &blockingMovieStore{}
+	h.Configure(Settings{DBTimeout: 20 * time.Millisecond})
+	rr := httptest.NewRecorder()
+	h.Routes().ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/api/v1/movies", nil))
+	if rr.Code != http.StatusServiceUnavailable || !strings.Contains(rr.Body.String(), codeTimeout) {
+		t.Fatalf("expected 503 timeout, got %d %s", rr.Code, rr.Body.String())
+	}
+}
+
+func TestAccessLog(t *testing.T) {
+	var buf bytes.Buffer
+	logger, err := logging.New(&buf, "json", "info")
+	if err != nil {
+		t.Fatal(err)
+	}
+	prev := slog.Default()
+	slog.SetDefault(logger)
+	defer slog.SetDefault(prev)
+
+	users := map[string]*models.User{"u-1": {ID: "u-1", Nickname: "ana", Role: RoleUser}}
+	h := New(&mockMovieStore{}, &mockCategoryStore{}, &mockNominatedStore{}, &mockUserStore{users: users}, &mockVoteStore{}, &mockWinnerStore{}, &mockCeremonyStore{}, &mockLeagueStore{}, &mockSessionStore{}, nil, "devsecret")
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/me", nil)
+	req.Header.Set("Authorization", "Bearer "+testAccessToken(t, h, users["u-1"]))
+	req.Header.Set(requestIDHeader, "req-42")
+	h.Routes().ServeHTTP(httptest.NewRecorder(), req)
+
+	var line map[string]interface{}
+	for _, l := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		if err := json.Unmarshal([]byte(l), &line); err != nil {
+			t.Fatalf("log line is not JSON: %q", l)
+		}
+	}
+	// the access log line is written last
+	if line["msg"] != "request" || line["method"] != "GET" || line["path"] != "/api/v1/me" || line["status"] != 200.0 ||
+		line["user_id"] != "u-1" || line["request_id"] != "req-42" || line["latency_ms"] == nil {
+		t.Fatalf("unexpected access log line %v", line)
+	}
+}
+
+func TestMetrics(t *testing.T) {
+	h := newTestHandler()
+	if rr := serve(h, httptest.NewRequest(http.MethodGet, "/metrics", nil)); rr.Code == http.StatusOK {
+		t.Fatal("metrics served without a token configured")
+	}
+
+	h.Configure(Settings{MetricsToken: "scrape-me"})
+	failures := loginsTotal.Value("failure")
+	serve(h, httptest.NewRequest(http.MethodGet, "/api/v1/movies/00000000-0000-0000-0000-000000000042", nil))
+	serve(h, httptest.NewRequest(http.MethodPost, "/api/v1/login", strings.NewReader(`{"email":"a@b.c","password":"x"}`)))
+	if got := loginsTotal.Value("failure"); got != failures+1 {
+		t.Fatalf("failed login not counted: %v -> %v", failures, got)
+	}
+
+	if rr := serve(h, httptest.NewRequest(http.MethodGet, "/metrics", nil)); rr.Code != http.StatusUnauthorized {
+		t.Fatalf("expected 401 without the token, got %d", rr.Code)
+	}
+	req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+	req.Header.Set("Authorization", "Bearer scrape-me")
+	rr := serve(h, req)
+	if rr.Code != http.StatusOK || !strings.HasPrefix(rr.Header().Get("Content-Type"), "text/plain; version=0.0.4") {
+		t.Fatalf("expected the text exposition, got %d %s", rr.Code, rr.Header().Get("Content-Type"))
+	}
+	for _, want := range []string{
+		`votacao_http_request_duration_seconds_count{method="GET",route="/api/v1/movies/{id}",status="200"}`,
+		`votacao_logins_total{result="failure"}`,
+	} {
+		if !strings.Contains(rr.Body.String(), want) {
+			t.Errorf("metrics lack %s", want)
+		}
+	}
+}
+
+func TestSubmitBallot(t *testing.T) {
+	f := newPageFixture(t, time.Now().Add(time.Hour))
+	cookies := f.register("fan@example.com")
+	ctx := context.Background()
+	locked, err := f.h.categoryStore.Insert(ctx, &models.Category{CeremonyID: f.ceremonyID, Name: "Best Director", SequenceOrder: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	movie, err := f.h.movieStore.Insert(ctx, &models.Movie{Title: "Flow"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	director, err := f.h.nominatedStore.Insert(ctx, &models.Nominated{MovieID: movie, CategoryID: locked, Name: "Sean Baker"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := f.h.categoryStore.SetLock(ctx, locked, true, nil); err != nil {
+		t.Fatal(err)
+	}
+	submit := func(votes map[string]string) (int, ballotResult) {
+		b, _ := json.Marshal(ballotRequest{Votes: votes})
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/ballot", bytes.NewReader(b))
+		for _, c := range cookies {
+			req.AddCookie(c)
+		}
+		req.Header.Set("X-CSRF-Token", "testcsrf")
+		rr := httptest.NewRecorder()
+		f.srv.ServeHTTP(rr, req)
+		var out ballotResult
+		_ = json.Unmarshal(rr.Body.Bytes(), &out)
+		return rr.Code, out
+	}
+	saved := func() int {
+		u, _ := f.h.userStore.GetByEmail(ctx, "fan@example.com")
+		votes, _ := f.h.voteStore.ListByUser(ctx, u.ID, f.ceremonyID)
+		return len(votes)
+	}
+
+	if code, _ := submit(map[string]string{f.categoryID: f.nominees[0], "00000000-0000-0000-0000-000000000099": f.nominees[1]}); code != http.StatusBadRequest {
+		t.Fatalf("unknown category: expected 400 got %d", code)
+	}
+	if code, _ := submit(map[string]string{f.categoryID: director}); code != http.StatusBadRequest {
+		t.Fatalf("nominee of another category: expected 400 got %d", code)
+	}
+	if code, _ := submit(map[string]string{f.categoryID: f.nominees[0], locked: director}); code != http.StatusForbidden {
+		t.Fatalf("locked category: expected 403 got %d", code)
+	}
+	if n := saved(); n != 0 {
+		t.Fatalf("a refused ballot saved %d votes", n)
+	}
+
+	if err := f.h.categoryStore.SetLock(ctx, locked, false, nil); err != nil {
+		t.Fatal(err)
+	}
+	code, out := submit(map[string]string{f.categoryID: f.nominees[0], locked: director})
+	if code != http.StatusOK || len(out.Votes) != 2 || out.Votes[0].CategoryID != f.categoryID || out.Votes[0].Status != "created" || out.Votes[1].Status != "created" {
+		t.Fatalf("ballot: got %d %+v", code, out)
+	}
+	_, out = submit(map[string]string{f.categoryID: f.nominees[1], locked: director})
+	if out.Votes[0].Status != "changed" || out.Votes[1].Status != "unchanged" || out.Votes[1].VoteID == 0 {
+		t.Fatalf("resubmitted ballot: %+v", out)
+	}
+	if n := saved(); n != 2 {
+		t.Fatalf("expected 2 votes, got %d", n)
+	}
+
+	// submit uses f and cookies, now of a ceremony past its deadline
+	f = newPageFixture(t, time.Now().Add(-time.Minute))
+	cookies = f.register("late@example.com")
+	if code, _ := submit(map[string]string{f.categoryID: f.nominees[0]}); code != http.StatusForbidden {
+		t.Fatalf("after the deadline: expected 403 got %d", code)
+	}
+}
+
+func TestSubmitBallotLargeCeremony(t *testing.T) {
+	f := newPageFixture(t, time.Now().Add(time.Hour))
+	cookies := f.register("fan@example.com")
+	// 120 newer nominees push the fixture's past the List cap
+	crafts, nominees := f.addCategory("Best Sound", 1, 120)
+	rr := f.postJSON("/api/v1/ballot", ballotRequest{Votes: map[string]string{f.categoryID: f.nominees[0], crafts: nominees[0]}}, cookies)
+	var out ballotResult
+	if err := json.Unmarshal(rr.Body.Bytes(), &out); err != nil || rr.Code != http.StatusOK || len(out.Votes) != 2 {
+		t.Fatalf("ballot: got %d %s", rr.Code, rr.Body.String())
+	}
+}
+
+func TestRepeatedVoteNotCounted(t *testing.T) {
+	f := newPageFixture(t, time.Now().Add(time.Hour))
+	cookies := f.register("fan@example.com")
+	vote := url.Values{"category_id": {f.categoryID}, "nominated_id": {f.nominees[0]}, "csrf_token": {"testcsrf"}}
+	created, changed := votesTotal.Value("created"), votesTotal.Value("changed")
+	for i := 0; i < 2; i++ {
+		if rr := f.do(http.MethodPost, "/nominateds/view", vote, cookies); rr.Code != http.StatusSeeOther {
+			t.Fatalf("vote: got %d", rr.Code)
+		}
+	}
+	f.postJSON("/api/v1/ballot", ballotRequest{Votes: map[string]string{f.categoryID: f.nominees[0]}}, cookies)
+	if got := votesTotal.Value("created") - created; got != 1 {
+		t.Fatalf("created votes counted %v times, want 1", got)
+	}
+	if got := votesTotal.Value("changed") - changed; got != 0 {
+		t.Fatalf("the same pick again counted %v changes, want 0", got)
+	}
+}
+
+func TestBallotStatus(t *testing.T) {
+	f := newPageFixture(t, time.Now().Add(2*time.Hour))
+	cookies := f.register("fan@example.com")
+	second, err := f.h.categoryStore.Insert(context.Background(), &models.Category{CeremonyID: f.ceremonyID, Name: "Best Director", SequenceOrder: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rr := f.do(http.MethodPost, "/nominateds/view", url.Values{"category_id": {f.categoryID}, "nominated_id": {f.nominees[0]}, "csrf_token": {"testcsrf"}}, cookies); rr.Code != http.StatusSeeOther {
+		t.Fatalf("vote: got %d", rr.Code)
+	}
+	if rr := f.do(http.MethodGet, "/api/v1/ballot/status", nil, nil); rr.Code != http.StatusUnauthorized {
+		t.Fatalf("anonymous: expected 401 got %d", rr.Code)
+	}
+	rr := f.do(http.MethodGet, "/api/v1/ballot/status", nil, cookies)
+	var st ballotStatus
+	if err := json.Unmarshal(rr.Body.Bytes(), &st); err != nil || rr.Code != http.StatusOK {
+		t.Fatalf("got %d %s", rr.Code, rr.Body.String())
+	}
+	if st.Total != 2 || st.Picked != 1 || st.Percent != 50 || len(st.Missing) != 1 || st.Missing[0] != second {
+		t.Fatalf("progress: %+v", st)
+	}
+	if p := st.Categories[0].Pick; p == nil || p.NominatedID != f.nominees[0] || p.MovieName != "Anora" || st.Categories[1].Pick != nil {
+		t.Fatalf("picks: %+v", st.Categories)
+	}
+	if st.Closed || st.TimeLeftSeconds <= 3600 || st.TimeLeftSeconds > 7200 {
+		t.Fatalf("time left: %+v", st)
+	}
+}
+
+func TestScorecard(t *testing.T) {
+	ctx := context.Background()
+	getScorecard := func(f *pageFixture, target string, cookies []*http.Cookie) scorecard {
+		t.Helper()
+		rr := f.do(http.MethodGet, target, nil, cookies)
+		var sc scorecard
+		if err := json.Unmarshal(rr.Body.Bytes(), &sc); err != nil || rr.Code != http.StatusOK {
+			t.Fatalf("%s: got %d %s", target, rr.Code, rr.Body.String())
+		}
+		return sc
+	}
+
+	f := newPageFixture(t, time.Now().Add(time.Hour))
+	cookies := f.register("fan@example.com")
+	fan, err := f.h.userStore.GetByEmail(ctx, "fan@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := f.h.categoryStore.Insert(ctx, &models.Category{CeremonyID: f.ceremonyID, Name: "Best Director", Points: 2, SequenceOrder: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rr := f.do(http.MethodPost, "/nominateds/view", url.Values{"category_id": {f.categoryID}, "nominated_id": {f.nominees[0]}, "csrf_token": {"testcsrf"}}, cookies); rr.Code != http.StatusSeeOther {
+		t.Fatalf("vote: got %d", rr.Code)
+	}
+	if _, err := f.h.winnerStore.Insert(ctx, &models.Winner{NominatedID: f.nominees[0]}); err != nil {
+		t.Fatal(err)
+	}
+
+	sc := getScorecard(f, "/api/v1/scorecard", cookies)
+	if sc.UserID != fan.ID || sc.Points != 1 || sc.MaxPoints != 1 || len(sc.Categories) != 2 {
+		t.Fatalf("totals: %+v", sc)
+	}
+	if c := sc.Categories[0]; c.Pick == nil || c.Pick.NominatedID != f.nominees[0] || !c.Correct || c.Points != 1 || c.Pending || len(c.Winners) != 1 || c.Winners[0].MovieName != "Anora" {
+		t.Fatalf("decided category: %+v", c)
+	}
+	if c := sc.Categories[1]; c.ID != second || c.Pick != nil || c.Correct || !c.Pending || c.Weight != 2 || c.Winners == nil {
+		t.Fatalf("pending category: %+v", c)
+	}
+
+	other := f.register("rival@example.com")
+	if rr := f.do(http.MethodGet, "/api/v1/users/"+fan.ID+"/scorecard", nil, other); rr.Code != http.StatusForbidden {
+		t.Fatalf("another user's scorecard before the deadline: expected 403 got %d", rr.Code)
+	}
+	if rr := f.do(http.MethodGet, "/api/v1/users/missing/scorecard", nil, other); rr.Code != http.StatusForbidden {
+		t.Fatalf("unknown user before the deadline: expected 403 got %d", rr.Code)
+	}
+	if sc := getScorecard(f, "/api/v1/users/"+fan.ID+"/scorecard", cookies); sc.Points != 1 {
+		t.Fatalf("own scorecard by id: %+v", sc)
+	}
+
+	closed := newPageFixture(t, time.Now().Add(-time.Hour))
+	cookies = closed.register("fan@example.com")
+	fan, err = closed.h.userStore.GetByEmail(ctx, "fan@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := closed.h.voteStore.Insert(ctx, &models.Vote{UserID: fan.ID, CategoryID: closed.categoryID, NominatedID: closed.nominees[1]}); err != nil {
+		t.Fatal(err)
+	}
+	if rr := closed.do(http.MethodGet, "/api/v1/users/missing/scorecard", nil, cookies); rr.Code != http.StatusNotFound {
+		t.Fatalf("unknown user: expected 404 got %d", rr.Code)
+	}
+	sc = getScorecard(closed, "/api/v1/users/"+fan.ID+"/scorecard", closed.register("rival@example.com"))
+	if c := sc.Categories[0]; c.Pick == nil || c.Pick.Name != "Conclave cast" || !c.Pending || sc.MaxPoints != 1 {
+		t.Fatalf("after the deadline: %+v", sc)
+	}
+}
+
+func TestBallotStatusLargeCeremony(t *testing.T) {
+	ctx := context.Background()
+	f := newPageFixture(t, time.Now().Add(time.Hour))
+	cookies := f.register("fan@example.com")
+	fan, err := f.h.userStore.GetByEmail(ctx, "fan@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// more categories, nominees and votes than the List methods return
+	_, sound := f.addCategory("Best Sound", 1, 120)
+	picks := []string{f.nominees[0], sound[0]}
+	for i := 0; i < 100; i++ {
+		_, noms := f.addCategory(fmt.Sprintf("Award %03d", i), 1, 1)
+		picks = append(picks, noms[0])
+	}
+	for _, id := range picks {
+		n, err := f.h.nominatedStore.Get(ctx, id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, _, err := f.h.voteStore.Insert(ctx, &models.Vote{UserID: fan.ID, CategoryID: n.CategoryID, NominatedID: n.ID}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	rr := f.do(http.MethodGet, "/api/v1/ballot/status", nil, cookies)
+	var st ballotStatus
+	if err := json.Unmarshal(rr.Body.Bytes(), &st); err != nil || rr.Code != http.StatusOK {
+		t.Fatalf("got %d %s", rr.Code, rr.Body.String())
+	}
+	if st.Total != 102 || st.Picked != 102 || len(st.Missing) != 0 {
+		t.Fatalf("progress: total=%d picked=%d missing=%d", st.Total, st.Picked, len(st.Missing))
+	}
+}
+
+func TestScorecardMatchesLeaderboard(t *testing.T) {
+	ctx := context.Background()
+	f := newPageFixture(t, time.Now().Add(time.Hour))
+	cookies := f.register("fan@example.com")
+	fan, err := f.h.userStore.GetByEmail(ctx, "fan@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the fixture's nominees fall past the List cap behind these 120
+	sound, nominees := f.addCategory("Best Sound", 3, 120)
+	for _, v := range []models.Vote{
+		{UserID: fan.ID, CategoryID: f.categoryID, NominatedID: f.nominees[0]},
+		{UserID: fan.ID, CategoryID: sound, NominatedID: nominees[0]},
+	} {
+		if _, _, err := f.h.voteStore.Insert(ctx, &v); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := f.h.winnerStore.Insert(ctx, &models.Winner{NominatedID: v.NominatedID}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	rr := f.do(http.MethodGet, "/api/v1/scorecard", nil, cookies)
+	var sc scorecard
+	if err := json.Unmarshal(rr.Body.Bytes(), &sc); err != nil || rr.Code != http.StatusOK {
+		t.Fatalf("got %d %s", rr.Code, rr.Body.String())
+	}
+	scores, err := f.h.voteStore.GetAllScores(ctx, f.ceremonyID)
+	if err != nil || len(scores) != 1 {
+		t.Fatalf("scores = %+v (%v)", scores, err)
+	}
+	sum := 0
+	for _, c := range sc.Categories {
+		if c.Pick == nil || !c.Correct || len(c.Winners) != 1 {
+			t.Fatalf("category %s: %+v", c.Name, c)
+		}
+		sum += c.Points
+	}
+	if sum != scores[0].Points || sc.Points != scores[0].Points || sc.MaxPoints != scores[0].MaxPoints || sum != 4 {
+		t.Fatalf("scorecard %d (sum %d) / %d, leaderboard %d / %d", sc.Points, sum, sc.MaxPoints, scores[0].Points, scores[0].MaxPoints)
+	}
+}
+
+func serve(h *Handler, req *http.Request) *httptest.ResponseRecorder {
+	rr := httptest.NewRecorder()
+	h.Routes().ServeHTTP(rr, req)
+	return rr
+}
@@ -0,0 +1,186 @@
+package handler
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+
+	"votacao/internal/router"
+	"votacao/models"
+)
+
+// CreateLeague accepts POST /add_league with JSON {name}. The authenticated
+// user becomes the owner and first member; the response carries the invite code.
+func (h *Handler) CreateLeague(w http.ResponseWriter, r *http.Request) {
+	if !h.validateCSRF(r) {
+		writeError(w, http.StatusForbidden, codeInvalidCSRF, "invalid csrf token")
+		return
+	}
+	uid, ok := GetUserIDFromContext(r.Context())
+	if !ok || uid == "" {
+		writeError(w, http.StatusUnauthorized, codeUnauthorized, "unauthorized")
+		return
+	}
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, codeBadRequest, "failed to read body")
+		return
+	}
+	defer r.Body.Close()
+
+	var req leagueRequest
+	if err := json.Unmarshal(body, &req); err != nil {
+		writeError(w, http.StatusBadRequest, codeInvalidJSON, "invalid json: "+err.Error())
+		return
+	}
+	if strings.TrimSpace(req.Name) == "" {
+		writeInvalid(w, "name is required", map[string]string{"name": "is required"})
+		return
+	}
+	l := &models.League{Name: strings.TrimSpace(req.Name), OwnerID: uid}
+	id, err := h.leagueStore.Insert(r.Context(), l)
+	if err != nil {
+		writeStoreError(w, err)
+		return
+	}
+	l.ID = id
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	_ = json.NewEncoder(w).Encode(l)
+}
+
+// JoinLeague accepts POST /leagues/join with JSON {invite_code}.
+func (h *Handler) JoinLeague(w http.ResponseWriter, r *http.Request) {
+	if !h.validateCSRF(r) {
+		writeError(w, http.StatusForbidden, codeInvalidCSRF, "invalid csrf token")
+		return
+	}
+	uid, ok := GetUserIDFromContext(r.Context())
+	if !ok || uid == "" {
+		writeError(w, http.StatusUnauthorized, codeUnauthorized, "unauthorized")
+		return
+	}
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, codeBadRequest, "failed to read body")
+		return
+	}
+	defer r.Body.Close()
+
+	var req joinLeagueRequest
+	if err := json.Unmarshal(body, &req); err != nil {
+		writeError(w, http.StatusBadRequest, codeInvalidJSON, "invalid json: "+err.Error())
+		return
+	}
+	code := strings.ToUpper(strings.TrimSpace(req.InviteCode))
+	if code == "" {
+		writeInvalid(w, "invite_code is required", map[string]string{"invite_code": "is required"})
+		return
+	}
+	l, err := h.leagueStore.GetByInviteCode(r.Context(), code)
+	if err != nil {
+		writeStoreError(w, err)
+		return
+	}
+	if l == nil {
+		writeError(w, http.StatusNotFound, codeNotFound, "league not found")
+		return
+	}
+	if err := h.leagueStore.AddMember(r.Context(), l.ID, uid); err != nil {
+		writeStoreError(w, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(l)
+}
+
+// LeaveLeague handles POST /leagues/leave?id=<id>. The owner cannot leave
+// their own league.
+func (h *Handler) LeaveLeague(w http.ResponseWriter, r *http.Request) {
+	if !h.validateCSRF(r) {
+		writeError(w, http.StatusForbidden, codeInvalidCSRF, "invalid csrf token")
+		return
+	}
+	uid, ok := GetUserIDFromContext(r.Context())
+	if !ok || uid == "" {
+		writeError(w, http.StatusUnauthorized, codeUnauthorized, "unauthorized")
+		return
+	}
+	id := param(r, "id")
+	if id == "" {
+		writeInvalid(w, "id is required", map[string]string{"id": "is required"})
+		return
+	}
+	l, err := h.leagueStore.Get(r.Context(), id)
+	if err != nil {
+		writeStoreError(w, err)
+		return
+	}
+	if l == nil {
+		writeError(w, http.StatusNotFound, codeNotFound, "league not found")
+		return
+	}
+	if l.OwnerID == uid {
+		writeError(w, http.StatusConflict, codeConflict, "the owner cannot leave the league")
+		return
+	}
+	if err := h.leagueStore.RemoveMember(r.Context(), id, uid); err != nil {
+		writeStoreError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ListMyLeagues handles GET /leagues and returns the authenticated user's leagues.
+func (h *Handler) ListMyLeagues(w http.ResponseWriter, r *http.Request) {
+	uid, ok := GetUserIDFromContext(r.Context())
+	if !ok || uid == "" {
+		writeError(w, http.StatusUnauthorized, codeUnauthorized, "unauthorized")
+		return
+	}
+	out, err := h.leagueStore.ListByUser(r.Context(), uid)
+	if err != nil {
+		writeStoreError(w, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(out)
+}
+
+// GetLeagueLeaderboard handles GET /leagues/{id}/leaderboard: the leaderboard of
+// the active (or ?ceremony_id=) ceremony restricted to league members. Only
+// members may see it.
+func (h *Handler) GetLeagueLeaderboard(w http.ResponseWriter, r *http.Request) {
+	id := router.Param(r, "id")
+	if id == "" {
+		writeError(w, http.StatusNotFound, codeNotFound, "league not found")
+		return
+	}
+	uid, ok := GetUserIDFromContext(r.Context())
+	if !ok || uid == "" {
+		writeError(w, http.StatusUnauthorized, codeUnauthorized, "unauthorized")
+		return
+	}
+	member, err := h.leagueStore.IsMember(r.Context(), id, uid)
+	if err != nil {
+		writeStoreError(w, err)
+		return
+	}
+	if !member {
+		// do not reveal whether the league exists
+		writeError(w, http.StatusNotFound, codeNotFound, "league not found")
+		return
+	}
+	cer, ok := h.requireCeremony(w, r)
+	if !ok {
+		return
+	}
+	scores, err := h.voteStore.GetLeagueScores(r.Context(), id, cer.ID)
+	if err != nil {
+		writeStoreError(w, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(scores)
+}
@@ -0,0 +1,51 @@
+package handler
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+
+	"votacao/internal/metrics"
+)
+
+// HTTP and business metrics, served by ServeMetrics with the store and pool
+// metrics registered elsewhere on metrics.Default.
+var (
+	httpDuration = metrics.Default.NewHistogramVec("votacao_http_request_duration_seconds",
+		"HTTP request latency by method, route pattern and status.", metrics.DefBuckets, "method", "route", "status")
+	votesTotal = metrics.Default.NewCounterVec("votacao_votes_total",
+		"Votes saved; result is created for a first pick in a category and changed when it replaced one.", "result")
+	registrationsTotal = metrics.Default.NewCounterVec("votacao_registrations_total",
+		"Users registered.")
+	loginsTotal = metrics.Default.NewCounterVec("votacao_logins_total",
+		"Login attempts by result (success or failure).", "result")
+	winnersSetTotal = metrics.Default.NewCounterVec("votacao_winners_set_total",
+		"Winners set by admins.")
+)
+
+// unmatchedRoute labels requests that matched no route (404s and 405s), so
+// arbitrary paths cannot blow up the number of series.
+const unmatchedRoute = "unmatched"
+
+// ServeMetrics handles GET /metrics in the Prometheus text format. When a
+// metrics token is configured the scraper must send it as a Bearer token.
+func (h *Handler) ServeMetrics(w http.ResponseWriter, r *http.Request) {
+	if tok := h.settings.MetricsToken; tok != "" {
+		got := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if subtle.ConstantTimeCompare([]byte(got), []byte(tok)) != 1 {
+			writeError(w, http.StatusUnauthorized, codeUnauthorized, "invalid metrics token")
+			return
+		}
+	}
+	metrics.Default.Handler().ServeHTTP(w, r)
+}
+
+func init() {
+	// export the known series at zero, so rates work from the first scrape
+	votesTotal.Add(0, "created")
+	votesTotal.Add(0, "changed")
+	registrationsTotal.Add(0)
+	loginsTotal.Add(0, "success")
+	loginsTotal.Add(0, "failure")
+	winnersSetTotal.Add(0)
+}
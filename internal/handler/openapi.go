@@ -0,0 +1,329 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"votacao/internal/openapi"
+	"votacao/internal/store"
+	"votacao/models"
+)
+
+// Who may call an operation.
+const (
+	accessPublic = iota
+	accessUser
+	accessAdmin
+)
+
+// apiOp describes one /api/v1 route for the OpenAPI spec. Request and
+// response schemas are derived from the Go values given here, which are the
+// types the handlers decode and encode.
+type apiOp struct {
+	summary string
+	access  int
+	query   []openapi.Parameter
+	// body is the request body; nil when the route takes none
+	body interface{}
+	// status is the success status; resp its body, nil for none
+	status int
+	resp   interface{}
+	// page wraps resp, the item type, in the {items, next_cursor} envelope
+	page bool
+}
+
+func queryParam(name, typ, description string) openapi.Parameter {
+	return openapi.Parameter{Name: name, In: "query", Description: description, Schema: &openapi.Schema{Type: typ}}
+}
+
+var (
+	ceremonyQuery = queryParam("ceremony_id", "string", "ceremony to use instead of the active one")
+	limitQuery    = queryParam("limit", "integer", fmt.Sprintf("page size, default %d, at most %d", store.DefaultPageLimit, store.MaxPageLimit))
+	cursorQuery   = queryParam("cursor", "string", "next_cursor of the previous page")
+	forceQuery    = queryParam("force", "boolean", "also delete the votes and winner referring to it")
+	// csrfHeader is required on every mutating route that reads the cookies
+	csrfHeader = openapi.Parameter{Name: "X-CSRF-Token", In: "header", Required: true, Description: "value of the csrf_token cookie", Schema: &openapi.Schema{Type: "string"}}
+)
+
+// apiOps documents every route of apiRoutes, keyed "METHOD /path".
+var apiOps = map[string]apiOp{
+	"GET /api/v1/movies":                            {summary: "List movies", query: []openapi.Parameter{queryParam("title", "string", "title prefix"), limitQuery, cursorQuery}, status: 200, resp: models.Movie{}, page: true},
+	"POST /api/v1/movies":                           {summary: "Add a movie", access: accessAdmin, body: models.Movie{}, status: 201, resp: models.Movie{}},
+	"POST /api/v1/movies/batch":                     {summary: "Add several movies", access: accessAdmin, body: []models.Movie{}, status: 201, resp: []models.Movie{}},
+	"GET /api/v1/movies/{id}":                       {summary: "Get a movie", status: 200, resp: models.Movie{}},
+	"PATCH /api/v1/movies/{id}":                     {summary: "Rename a movie", access: accessAdmin, body: updateMovieRequest{}, status: 200, resp: models.Movie{}},
+	"DELETE /api/v1/movies/{id}":                    {summary: "Delete a movie and its nominees", access: accessAdmin, query: []openapi.Parameter{forceQuery}, status: 204},
+	"GET /api/v1/categories":                        {summary: "List categories of a ceremony", query: []openapi.Parameter{ceremonyQuery, limitQuery, cursorQuery}, status: 200, resp: models.Category{}, page: true},
+	"POST /api/v1/categories":                       {summary: "Add a category to the active ceremony", access: accessAdmin, body: categoryRequest{}, status: 201, resp: models.Category{}},
+	"POST /api/v1/categories/batch":                 {summary: "Add several categories", access: accessAdmin, body: []categoryRequest{}, status: 201, resp: []models.Category{}},
+	"GET /api/v1/categories/{id}":                   {summary: "Get a category", status: 200, resp: models.Category{}},
+	"PATCH /api/v1/categories/{id}":                 {summary: "Update a category", access: accessAdmin, body: updateCategoryRequest{}, status: 200, resp: models.Category{}},
+	"DELETE /api/v1/categories/{id}":                {summary: "Delete a category and its nominees", access: accessAdmin, query: []openapi.Parameter{forceQuery}, status: 204},
+	"PUT /api/v1/categories/{id}/lock":              {summary: "Lock, reopen or schedule the lock of a category", access: accessAdmin, body: lockCategoryRequest{}, status: 200, resp: models.Category{}},
+	"GET /api/v1/categories/{category_id}/nominees": {summary: "List the nominees of a category", status: 200, resp: []nominatedOut{}},
+	"GET /api/v1/nominees":                          {summary: "List nominees of a ceremony", query: []openapi.Parameter{ceremonyQuery, queryParam("category_id", "string", "only this category"), queryParam("movie_id", "string", "only this movie"), limitQuery, cursorQuery}, status: 200, resp: nominatedOut{}, page: true},
+	"POST /api/v1/nominees":                         {summary: "Add a nominee", access: accessAdmin, body: models.Nominated{}, status: 201, resp: models.Nominated{}},
+	"POST /api/v1/nominees/batch":                   {summary: "Add several nominees", access: accessAdmin, body: []models.Nominated{}, status: 201, resp: []models.Nominated{}},
+	"POST /api/v1/nominees/batch_by_name":           {summary: "Create a movie and a nominee per name in a category", access: accessAdmin, body: nominateByNamesRequest{}, status: 201, resp: []models.Nominated{}},
+	"GET /api/v1/nominees/by_category":              {summary: "Nominees grouped by category", query: []openapi.Parameter{ceremonyQuery}, status: 200, resp: []categoryWithNominees{}},
+	"GET /api/v1/nominees/{id}":                     {summary: "Get a nominee", status: 200, resp: models.Nominated{}},
+	"PATCH /api/v1/nominees/{id}":                   {summary: "Update a nominee", access: accessAdmin, body: updateNominatedRequest{}, status: 200, resp: models.Nominated{}},
+	"DELETE /api/v1/nominees/{id}":                  {summary: "Delete a nominee", access: accessAdmin, query: []openapi.Parameter{forceQuery}, status: 204},
+
+	"GET /api/v1/winners":         {summary: "List the winners of a ceremony", query: []openapi.Parameter{ceremonyQuery}, status: 200, resp: []models.Winner{}},
+	"POST /api/v1/winners":        {summary: "Announce a winner", access: accessAdmin, body: nominatedIDRequest{}, status: 201, resp: models.Winner{}},
+	"DELETE /api/v1/winners/{id}": {summary: "Delete a winner", access: accessAdmin, status: 204},
+
+	"GET /api/v1/ceremonies":                {summary: "List ceremonies", status: 200, resp: []models.Ceremony{}},
+	"POST /api/v1/ceremonies":               {summary: "Add an upcoming ceremony", access: accessAdmin, body: models.Ceremony{}, status: 201, resp: models.Ceremony{}},
+	"GET /api/v1/ceremonies/active":         {summary: "Get the active ceremony", status: 200, resp: models.Ceremony{}},
+	"POST /api/v1/ceremonies/{id}/activate": {summary: "Make a ceremony the active one", access: accessAdmin, status: 200, resp: models.Ceremony{}},
+	"GET /api/v1/deadline":                  {summary: "Voting deadline and category lock states", query: []openapi.Parameter{ceremonyQuery}, status: 200, resp: deadlineResponse{}},
+
+	"POST /api/v1/register":        {summary: "Create an account and log in", body: registerRequest{}, status: 201, resp: userOut{}},
+	"POST /api/v1/login":           {summary: "Log in", body: loginRequest{}, status: 200, resp: loginResponse{}},
+	"POST /api/v1/logout":          {summary: "Revoke the browser session and clear the auth cookies", query: []openapi.Parameter{csrfHeader}, status: 303},
+	"GET /api/v1/me":               {summary: "The authenticated user", access: accessUser, status: 200, resp: meOut{}},
+	"POST /api/v1/token/refresh":   {summary: "Rotate a refresh token for a new access token", body: refreshRequest{}, status: 200, resp: tokenResponse{}},
+	"GET /api/v1/sessions":         {summary: "List the user's active sessions", access: accessUser, status: 200, resp: []sessionOut{}},
+	"DELETE /api/v1/sessions":      {summary: "Revoke all of the user's sessions", access: accessUser, status: 200, resp: revokedResponse{}},
+	"DELETE /api/v1/sessions/{id}": {summary: "Revoke one of the user's sessions", access: accessUser, status: 204},
+
+	"GET /api/v1/users":                         {summary: "List users", query: []openapi.Parameter{queryParam("nickname", "string", "nickname prefix"), limitQuery, cursorQuery}, status: 200, resp: userSummary{}, page: true},
+	"PUT /api/v1/users/{id}/role":               {summary: "Set a user's role", access: accessAdmin, body: setRoleRequest{}, status: 200, resp: roleResponse{}},
+	"GET /api/v1/users/{user_id}/votes/history": {summary: "A user's voting history", access: accessAdmin, query: []openapi.Parameter{ceremonyQuery}, status: 200, resp: voteHistory{}},
+	"GET /api/v1/users/{id}/scorecard":          {summary: "A user's scorecard, once voting has closed", access: accessUser, query: []openapi.Parameter{ceremonyQuery}, status: 200, resp: scorecard{}},
+	"POST /api/v1/admin/bootstrap":              {summary: "Promote the caller to the first admin", access: accessUser, status: 200, resp: roleResponse{}},
+	"POST /api/v1/admin/scores/recompute":       {summary: "Reweight categories and recompute the leaderboard", access: accessAdmin, query: []openapi.Parameter{ceremonyQuery}, body: recomputeRequest{}, status: 200, resp: []store.UserScore{}},
+
+	"GET /api/v1/votes":         {summary: "List the user's votes", access: accessUser, query: []openapi.Parameter{ceremonyQuery, queryParam("category_id", "string", "only this category"), limitQuery, cursorQuery}, status: 200, resp: models.Vote{}, page: true},
+	"POST /api/v1/votes":        {summary: "Vote, replacing an earlier pick in the category (200)", access: accessUser, body: nominatedIDRequest{}, status: 201, resp: voteResult{}},
+	"GET /api/v1/votes/history": {summary: "The user's voting history", access: accessUser, query: []openapi.Parameter{ceremonyQuery}, status: 200, resp: voteHistory{}},
+	"POST /api/v1/ballot":       {summary: "Vote in several categories at once, all or nothing", access: accessUser, body: ballotRequest{}, status: 200, resp: ballotResult{}},
+	"GET /api/v1/ballot/status": {summary: "The user's pick in every category and what is missing", access: accessUser, query: []openapi.Parameter{ceremonyQuery}, status: 200, resp: ballotStatus{}},
+	"GET /api/v1/score":         {summary: "The user's score", access: accessUser, query: []openapi.Parameter{ceremonyQuery}, status: 200, resp: scoreResponse{}},
+	"GET /api/v1/scorecard":     {summary: "The user's score category by category", access: accessUser, query: []openapi.Parameter{ceremonyQuery}, status: 200, resp: scorecard{}},
+	"GET /api/v1/leaderboard":   {summary: "Leaderboard of a ceremony", query: []openapi.Parameter{ceremonyQuery, queryParam("nickname", "string", "nickname prefix"), limitQuery, cursorQuery}, status: 200, resp: store.UserScore{}, page: true},
+
+	"GET /api/v1/leagues":                  {summary: "List the user's leagues", access: accessUser, status: 200, resp: []models.League{}},
+	"POST /api/v1/leagues":                 {summary: "Create a league", access: accessUser, body: leagueRequest{}, status: 201, resp: models.League{}},
+	"POST /api/v1/leagues/join":            {summary: "Join a league by invite code", access: accessUser, body: joinLeagueRequest{}, status: 200, resp: models.League{}},
+	"POST /api/v1/leagues/{id}/leave":      {summary: "Leave a league", access: accessUser, status: 204},
+	"GET /api/v1/leagues/{id}/leaderboard": {summary: "Leaderboard of a league's members", access: accessUser, query: []openapi.Parameter{ceremonyQuery}, status: 200, resp: []store.UserScore{}},
+
+	"GET /api/v1/events": {summary: "Server-Sent Events stream of live results", status: 200},
+}
+
+const specDescription = `Authenticated routes take the access token as a Bearer token or in the jwt
+cookie. Authenticated POST, PUT, PATCH and DELETE requests must also send the
+csrf_token cookie back in the X-CSRF-Token header. Errors use the envelope
+{"error": {"code", "message", "fields", "request_id"}}. The RPC-style routes
+outside /api/v1 are deprecated aliases kept for the bundled pages.`
+
+// newSpec builds the OpenAPI document of the API: every route of apiRoutes
+// from apiOps and every legacy route as a deprecated copy of its successor.
+func newSpec(routes []route) (*openapi.Document, error) {
+	doc := openapi.New("Votacao API", "1.0.0")
+	doc.Info.Description = specDescription
+	doc.Components.SecuritySchemes = map[string]*openapi.SecurityScheme{
+		"bearer": {Type: "http", Scheme: "bearer", BearerFormat: "JWT"},
+		"cookie": {Type: "apiKey", In: "cookie", Name: "jwt"},
+	}
+	// named first so that other schemas refer to them
+	doc.Define("Error", errorResponse{})
+	doc.Define("Movie", models.Movie{})
+	doc.Define("Category", models.Category{})
+	doc.Define("Nominee", models.Nominated{})
+	doc.Define("NomineeWithMovie", nominatedOut{})
+	doc.Define("CategoryWithNominees", categoryWithNominees{})
+	doc.Define("Winner", models.Winner{})
+	doc.Define("Ceremony", models.Ceremony{})
+	doc.Define("Vote", models.Vote{})
+	doc.Define("VoteEvent", models.VoteEvent{})
+	doc.Define("League", models.League{})
+	doc.Define("Session", sessionOut{})
+	doc.Define("UserScore", store.UserScore{})
+	doc.Define("Tokens", tokenResponse{})
+
+	for _, rte := range routes {
+		key := rte.method + " " + rte.path
+		o, ok := apiOps[key]
+		if !ok {
+			return nil, fmt.Errorf("openapi: route %s is not documented", key)
+		}
+		doc.Add(rte.method, rte.path, o.operation(doc, key))
+	}
+	for _, l := range legacyRoutes {
+		op, err := legacyOperation(doc, l)
+		if err != nil {
+			return nil, err
+		}
+		doc.Add(l.method, l.path, op)
+	}
+	return doc, nil
+}
+
+func (o apiOp) operation(doc *openapi.Document, key string) *openapi.Operation {
+	method, path, _ := strings.Cut(key, " ")
+	op := &openapi.Operation{
+		OperationID: operationID(method, path),
+		Summary:     o.summary,
+		Tags:        []string{strings.Split(strings.TrimPrefix(path, "/api/v1/"), "/")[0]},
+		Responses:   map[string]*openapi.Response{"default": errorResponseSpec()},
+	}
+	for _, seg := range strings.Split(path, "/") {
+		if strings.HasPrefix(seg, "{") {
+			op.Parameters = append(op.Parameters, openapi.Parameter{Name: strings.Trim(seg, "{}"), In: "path", Required: true, Schema: &openapi.Schema{Type: "string"}})
+		}
+	}
+	op.Parameters = append(op.Parameters, o.query...)
+	if o.access != accessPublic {
+		op.Security = []map[string][]string{{"bearer": {}}, {"cookie": {}}}
+		if method != http.MethodGet {
+			op.Parameters = append(op.Parameters, csrfHeader)
+		}
+		if o.access == accessAdmin {
+			op.Description = "Admin only."
+		}
+	}
+	if o.body != nil {
+		op.RequestBody = &openapi.RequestBody{Required: true, Content: jsonContent(doc.SchemaOf(o.body))}
+	}
+	res := &openapi.Response{Description: http.StatusText(o.status)}
+	switch {
+	case path == "/api/v1/events":
+		res.Content = map[string]openapi.MediaType{"text/event-stream": {Schema: &openapi.Schema{Type: "string"}}}
+	case o.page:
+		res.Content = jsonContent(pageSchema(doc.SchemaOf(o.resp)))
+	case o.resp != nil:
+		res.Content = jsonContent(doc.SchemaOf(o.resp))
+	}
+	op.Responses[strconv.Itoa(o.status)] = res
+	if key == "POST /api/v1/votes" {
+		// replacing an earlier pick is not a creation
+		op.Responses["200"] = &openapi.Response{Description: "OK", Content: res.Content}
+	}
+	return op
+}
+
+// legacyOperation copies the successor's operation, marked deprecated. Path
+// parameters of the successor become query parameters unless the route takes
+// a body, which then carries them.
+func legacyOperation(doc *openapi.Document, l legacyRoute) (*openapi.Operation, error) {
+	method, path, _ := strings.Cut(l.successor, " ")
+	succ := doc.Operation(method, path)
+	if succ == nil {
+		return nil, fmt.Errorf("openapi: successor %s of %s %s is not documented", l.successor, l.method, l.path)
+	}
+	op := *succ
+	op.OperationID = operationID(l.method, l.path)
+	op.Deprecated = true
+	op.Description = strings.TrimSpace("Deprecated alias of " + l.successor + ". " + succ.Description)
+	op.Parameters = nil
+	for _, p := range succ.Parameters {
+		if p.In == "path" && !strings.Contains(l.path, "{"+p.Name+"}") {
+			if succ.RequestBody != nil {
+				continue
+			}
+			p.In = "query"
+		}
+		op.Parameters = append(op.Parameters, p)
+	}
+	if l.byID != "" {
+		m, p, _ := strings.Cut(l.byID, " ")
+		get := doc.Operation(m, p)
+		if get == nil {
+			return nil, fmt.Errorf("openapi: %s of %s %s is not documented", l.byID, l.method, l.path)
+		}
+		op.Description += " With ?id= it returns one item, as " + l.byID + "."
+		op.Parameters = append(op.Parameters, queryParam("id", "string", "return this item instead of a page"))
+		list, one := succ.Responses["200"].Content["application/json"].Schema, get.Responses["200"].Content["application/json"].Schema
+		op.Responses = map[string]*openapi.Response{
+			"200":     {Description: "OK", Content: jsonContent(&openapi.Schema{OneOf: []*openapi.Schema{list, one}})},
+			"default": succ.Responses["default"],
+		}
+	}
+	return &op, nil
+}
+
+// operationID turns "GET /api/v1/movies/{id}" into "getApiV1MoviesId".
+func operationID(method, path string) string {
+	var b strings.Builder
+	b.WriteString(strings.ToLower(method))
+	for _, part := range strings.FieldsFunc(path, func(r rune) bool { return r == '/' || r == '_' || r == '{' || r == '}' }) {
+		b.WriteString(strings.ToUpper(part[:1]) + part[1:])
+	}
+	return b.String()
+}
+
+func jsonContent(s *openapi.Schema) map[string]openapi.MediaType {
+	return map[string]openapi.MediaType{"application/json": {Schema: s}}
+}
+
+func errorResponseSpec() *openapi.Response {
+	return &openapi.Response{Description: "Error", Content: jsonContent(openapi.Ref("Error"))}
+}
+
+// pageSchema is the {items, next_cursor} envelope written by writePage.
+func pageSchema(item *openapi.Schema) *openapi.Schema {
+	return &openapi.Schema{
+		Type: "object",
+		Properties: map[string]*openapi.Schema{
+			"items":       openapi.ArrayOf(item),
+			"next_cursor": {Type: "string", Description: "cursor of the next page; absent on the last page"},
+		},
+		Required: []string{"items"},
+	}
+}
+
+var (
+	specOnce sync.Once
+	specJSON []byte
+	specErr  error
+)
+
+// ServeOpenAPI handles GET /openapi.json.
+func (h *Handler) ServeOpenAPI(w http.ResponseWriter, r *http.Request) {
+	specOnce.Do(func() {
+		doc, err := newSpec(h.apiRoutes())
+		if err != nil {
+			specErr = err
+			return
+		}
+		specJSON, specErr = json.MarshalIndent(doc, "", "  ")
+	})
+	if specErr != nil {
+		writeInternal(w, specErr)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(specJSON)
+}
+
+// apiDocsPage renders /openapi.json with Swagger UI.
+const apiDocsPage = `<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>Votacao API</title>
+<link rel="stylesheet" href="https://unpkg.com/swagger-ui-dist@5/swagger-ui.css">
+</head>
+<body>
+<div id="swagger-ui"></div>
+<script src="https://unpkg.com/swagger-ui-dist@5/swagger-ui-bundle.js"></script>
+<script>
+window.ui = SwaggerUIBundle({ url: '/openapi.json', dom_id: '#swagger-ui' });
+</script>
+</body>
+</html>
+`
+
+// ServeAPIDocs handles GET /docs, a browsable view of the OpenAPI spec.
+func (h *Handler) ServeAPIDocs(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	_, _ = w.Write([]byte(apiDocsPage))
+}
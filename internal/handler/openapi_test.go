@@ -0,0 +1,254 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"votacao/internal/openapi"
+	"votacao/internal/store/memstore"
+)
+
+func TestSpecDocumentsEveryRoute(t *testing.T) {
+	h := New(nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, "test-secret")
+	doc, err := newSpec(h.apiRoutes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	registered := make(map[string]bool)
+	for _, rte := range h.apiRoutes() {
+		registered[rte.method+" "+rte.path] = true
+	}
+	for key := range apiOps {
+		if !registered[key] {
+			t.Errorf("apiOps documents %s, which is not a route", key)
+		}
+	}
+	for _, l := range legacyRoutes {
+		op := doc.Operation(l.method, l.path)
+		if op == nil || !op.Deprecated {
+			t.Errorf("legacy route %s %s is not documented as deprecated", l.method, l.path)
+		}
+	}
+}
+
+func TestServeOpenAPIAndDocs(t *testing.T) {
+	srv := New(nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, "test-secret").Routes()
+
+	rr := httptest.NewRecorder()
+	srv.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/openapi.json", nil))
+	if rr.Code != http.StatusOK {
+		t.Fatalf("GET /openapi.json: %d %s", rr.Code, rr.Body.String())
+	}
+	var doc openapi.Document
+	if err := json.Unmarshal(rr.Body.Bytes(), &doc); err != nil {
+		t.Fatal(err)
+	}
+	if doc.OpenAPI != "3.0.3" || doc.Paths["/api/v1/movies/{id}"]["patch"] == nil || doc.Components.Schemas["Movie"] == nil {
+		t.Fatalf("unexpected spec: openapi=%q, %d paths", doc.OpenAPI, len(doc.Paths))
+	}
+
+	rr = httptest.NewRecorder()
+	srv.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/docs", nil))
+	if rr.Code != http.StatusOK || !strings.Contains(rr.Body.String(), "/openapi.json") {
+		t.Fatalf("GET /docs: %d", rr.Code)
+	}
+}
+
+// contract runs requests through the real routes, backed by the in-memory
+// stores, and validates every response against the spec.
+type contract struct {
+	t     *testing.T
+	srv   http.Handler
+	doc   *openapi.Document
+	token string
+	seen  map[string]bool
+}
+
+func newContract(t *testing.T) *contract {
+	mem := memstore.New()
+	h := New(memstore.NewMovie(mem), memstore.NewCategory(mem), memstore.NewNominated(mem), memstore.NewUser(mem),
+		memstore.NewVote(mem), memstore.NewWinner(mem), memstore.NewCeremony(mem), memstore.NewLeague(mem),
+		memstore.NewSession(mem), nil, "test-secret")
+	doc, err := newSpec(h.apiRoutes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &contract{t: t, srv: h.Routes(), doc: doc, seen: make(map[string]bool)}
+}
+
+// call requests method and pattern, with {name} segments replaced from
+// params, checks the status and validates the body against the operation of
+// pattern. It returns the decoded body.
+func (c *contract) call(method, pattern string, params map[string]string, query string, body interface{}, want int) interface{} {
+	c.t.Helper()
+	path := pattern
+	for k, v := range params {
+		path = strings.Replace(path, "{"+k+"}", v, 1)
+	}
+	if query != "" {
+		path += "?" + query
+	}
+	var rdr *bytes.Reader
+	if body != nil {
+		b, _ := json.Marshal(body)
+		rdr = bytes.NewReader(b)
+	} else {
+		rdr = bytes.NewReader(nil)
+	}
+	req := httptest.NewRequest(method, path, rdr)
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+	req.AddCookie(&http.Cookie{Name: "csrf_token", Value: "csrf"})
+	req.Header.Set("X-CSRF-Token", "csrf")
+	rr := httptest.NewRecorder()
+	c.srv.ServeHTTP(rr, req)
+	if rr.Code != want {
+		c.t.Fatalf("%s %s: status %d, want %d: %s", method, path, rr.Code, want, rr.Body.String())
+	}
+
+	op := c.doc.Operation(method, pattern)
+	if op == nil {
+		c.t.Fatalf("%s %s is not in the spec", method, pattern)
+	}
+	c.seen[method+" "+pattern] = true
+	res := op.Responses[strconv.Itoa(rr.Code)]
+	if res == nil {
+		if rr.Code < 400 {
+			c.t.Fatalf("%s %s: status %d is not documented", method, pattern, rr.Code)
+		}
+		res = op.Responses["default"]
+	}
+	media, ok := res.Content["application/json"]
+	if !ok {
+		if ct := rr.Header().Get("Content-Type"); strings.HasPrefix(ct, "application/json") {
+			c.t.Fatalf("%s %s: undocumented JSON body: %s", method, pattern, rr.Body.String())
+		}
+		return nil
+	}
+	var v interface{}
+	if err := json.Unmarshal(rr.Body.Bytes(), &v); err != nil {
+		c.t.Fatalf("%s %s: invalid JSON %q: %v", method, pattern, rr.Body.String(), err)
+	}
+	if err := c.doc.Validate(media.Schema, v); err != nil {
+		c.t.Fatalf("%s %s: response does not match the spec: %v\n%s", method, pattern, err, rr.Body.String())
+	}
+	return v
+}
+
+func field(v interface{}, name string) string {
+	s, _ := v.(map[string]interface{})[name].(string)
+	return s
+}
+
+func firstItem(v interface{}) interface{} {
+	return v.(map[string]interface{})["items"].([]interface{})[0]
+}
+
+func TestContract(t *testing.T) {
+	c := newContract(t)
+	id := func(v interface{}) map[string]string { return map[string]string{"id": field(v, "id")} }
+
+	// accounts: the first user becomes admin
+	admin := c.call("POST", "/api/v1/register", nil, "", map[string]string{"nickname": "ana", "email": "ana@example.com", "password": "secret"}, 201)
+	c.token = field(c.call("POST", "/api/v1/login", nil, "", map[string]string{"email": "ana@example.com", "password": "secret"}, 200), "access_token")
+	c.call("POST", "/api/v1/admin/bootstrap", nil, "", nil, 200)
+	tokens := c.call("POST", "/api/v1/login", nil, "", map[string]string{"email": "ana@example.com", "password": "secret"}, 200)
+	c.token = field(tokens, "access_token")
+	c.call("GET", "/api/v1/me", nil, "", nil, 200)
+	c.call("POST", "/api/v1/token/refresh", nil, "", map[string]string{"refresh_token": field(tokens, "refresh_token")}, 200)
+	c.call("GET", "/api/v1/sessions", nil, "", nil, 200)
+
+	// catalog
+	cer := c.call("POST", "/api/v1/ceremonies", nil, "", map[string]interface{}{"year": 2026, "name": "Oscars 2026", "deadline": "2099-03-15T19:00:00Z"}, 201)
+	c.call("POST", "/api/v1/ceremonies/{id}/activate", id(cer), "", nil, 200)
+	c.call("GET", "/api/v1/ceremonies", nil, "", nil, 200)
+	c.call("GET", "/api/v1/ceremonies/active", nil, "", nil, 200)
+
+	movie := c.call("POST", "/api/v1/movies", nil, "", map[string]string{"title": "Anora"}, 201)
+	extra := c.call("POST", "/api/v1/movies/batch", nil, "", []map[string]string{{"title": "Flow"}, {"title": "Wicked"}}, 201).([]interface{})
+	c.call("GET", "/api/v1/movies", nil, "limit=1", nil, 200)
+	c.call("GET", "/api/v1/movies/{id}", id(movie), "", nil, 200)
+	c.call("PATCH", "/api/v1/movies/{id}", id(movie), "", map[string]string{"title": "Anora (2024)"}, 200)
+
+	cat := c.call("POST", "/api/v1/categories", nil, "", map[string]interface{}{"name": "Best Picture", "points": 5}, 201)
+	cats := c.call("POST", "/api/v1/categories/batch", nil, "", []map[string]string{{"name": "Best Director"}}, 201).([]interface{})
+	c.call("GET", "/api/v1/categories", nil, "", nil, 200)
+	c.call("GET", "/api/v1/categories/{id}", id(cat), "", nil, 200)
+	c.call("PATCH", "/api/v1/categories/{id}", id(cat), "", map[string]int{"sequence_order": 1}, 200)
+
+	nom := c.call("POST", "/api/v1/nominees", nil, "", map[string]string{"movie_id": field(movie, "id"), "category_id": field(cat, "id"), "name": "Anora"}, 201)
+	noms := c.call("POST", "/api/v1/nominees/batch", nil, "", []map[string]string{{"movie_id": field(extra[0], "id"), "category_id": field(cat, "id"), "name": "Flow"}}, 201).([]interface{})
+	c.call("POST", "/api/v1/nominees/batch_by_name", nil, "", map[string]interface{}{"category_id": field(cats[0], "id"), "names": []string{"Sean Baker"}}, 201)
+	c.call("GET", "/api/v1/nominees", nil, "category_id="+field(cat, "id"), nil, 200)
+	c.call("GET", "/api/v1/nominees/{id}", id(nom), "", nil, 200)
+	c.call("PATCH", "/api/v1/nominees/{id}", id(nom), "", map[string]string{"url_image": "https://example.com/anora.jpg"}, 200)
+	c.call("GET", "/api/v1/categories/{category_id}/nominees", map[string]string{"category_id": field(cat, "id")}, "", nil, 200)
+	c.call("GET", "/api/v1/nominees/by_category", nil, "", nil, 200)
+	c.call("GET", "/api/v1/deadline", nil, "", nil, 200)
+
+	// votes, winners and scores
+	c.call("POST", "/api/v1/votes", nil, "", map[string]string{"nominated_id": field(nom, "id")}, 201)
+	c.call("POST", "/api/v1/votes", nil, "", map[string]string{"nominated_id": field(noms[0], "id")}, 200)
+	c.call("POST", "/api/v1/ballot", nil, "", map[string]interface{}{"votes": map[string]string{field(cat, "id"): field(nom, "id")}}, 200)
+	c.call("GET", "/api/v1/ballot/status", nil, "", nil, 200)
+	c.call("GET", "/api/v1/votes", nil, "", nil, 200)
+	c.call("GET", "/api/v1/votes/history", nil, "", nil, 200)
+	c.call("GET", "/api/v1/users/{user_id}/votes/history", map[string]string{"user_id": field(admin, "id")}, "", nil, 200)
+	winner := c.call("POST", "/api/v1/winners", nil, "", map[string]string{"nominated_id": field(noms[0], "id")}, 201)
+	c.call("GET", "/api/v1/winners", nil, "", nil, 200)
+	c.call("GET", "/api/v1/score", nil, "", nil, 200)
+	c.call("GET", "/api/v1/scorecard", nil, "", nil, 200)
+	c.call("GET", "/api/v1/users/{id}/scorecard", id(admin), "", nil, 200)
+	c.call("GET", "/api/v1/leaderboard", nil, "", nil, 200)
+	c.call("POST", "/api/v1/admin/scores/recompute", nil, "", map[string]interface{}{"points": map[string]int{field(cat, "id"): 3}}, 200)
+	c.call("PUT", "/api/v1/categories/{id}/lock", id(cat), "", nil, 200)
+	c.call("DELETE", "/api/v1/winners/{id}", id(winner), "", nil, 204)
+
+	// a second user in a league
+	league := c.call("POST", "/api/v1/leagues", nil, "", map[string]string{"name": "Office"}, 201)
+	c.call("GET", "/api/v1/leagues", nil, "", nil, 200)
+	adminToken := c.token
+	bob := c.call("POST", "/api/v1/register", nil, "", map[string]string{"nickname": "bob", "email": "bob@example.com", "password": "secret"}, 201)
+	c.token = field(c.call("POST", "/api/v1/login", nil, "", map[string]string{"email": "bob@example.com", "password": "secret"}, 200), "access_token")
+	c.call("POST", "/api/v1/leagues/join", nil, "", map[string]string{"invite_code": field(league, "invite_code")}, 200)
+	c.call("GET", "/api/v1/leagues/{id}/leaderboard", id(league), "", nil, 200)
+	c.call("POST", "/api/v1/leagues/{id}/leave", id(league), "", nil, 204)
+	sessions := c.call("GET", "/api/v1/sessions", nil, "", nil, 200).([]interface{})
+	c.call("DELETE", "/api/v1/sessions/{id}", id(sessions[0]), "", nil, 204)
+	c.token = adminToken
+	c.call("GET", "/api/v1/users", nil, "nickname=b", nil, 200)
+	c.call("PUT", "/api/v1/users/{id}/role", id(bob), "", map[string]string{"role": "admin"}, 200)
+
+	// deletes
+	c.call("DELETE", "/api/v1/nominees/{id}", id(nom), "force=true", nil, 204)
+	c.call("DELETE", "/api/v1/categories/{id}", id(cats[0]), "force=true", nil, 204)
+	c.call("DELETE", "/api/v1/movies/{id}", id(extra[1]), "", nil, 204)
+
+	// errors use the envelope
+	c.call("GET", "/api/v1/movies/{id}", map[string]string{"id": "missing"}, "", nil, 404)
+	c.call("GET", "/api/v1/movies", nil, "limit=0", nil, 400)
+	c.call("POST", "/api/v1/movies", nil, "", "not an object", 400)
+
+	// legacy aliases answer with the successor's shapes
+	c.call("GET", "/movies", nil, "", nil, 200)
+	c.call("GET", "/movies", nil, "id="+field(movie, "id"), nil, 200)
+	c.call("GET", "/nominateds/by_category", nil, "category_id="+field(cat, "id"), nil, 200)
+	c.call("GET", "/leaderboard", nil, "", nil, 200)
+
+	c.call("DELETE", "/api/v1/sessions", nil, "", nil, 200)
+	c.token = ""
+	c.call("POST", "/api/v1/logout", nil, "", nil, 303)
+
+	for key := range apiOps {
+		// the event stream does not end; its framing is covered by the events tests
+		if !c.seen[key] && key != "GET /api/v1/events" {
+			t.Errorf("contract test does not exercise %s", key)
+		}
+	}
+}
@@ -0,0 +1,704 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"votacao/internal/store"
+	"votacao/models"
+)
+
+// The HTML pages are rendered on the server from the stores and work without
+// JavaScript: their forms post back to the page's own path, which redirects
+// after a change. Scripts only add live updates on top.
+
+// fallbackImage is shown for nominees without an image.
+const fallbackImage = "https://s2-gshow.glbimg.com/KIfsgPzVx8g-zWDxOSGy4llwWLw=/0x0:1080x1182/984x0/smart/filters:strip_icc()/i.s3.glbimg.com/v1/AUTH_e84042ef78cb4708aeebdf1c68c6cbd6/internal_photos/bs/2026/y/S/gpOY25TAizQq9IcyUHeg/theacademy-20260102-150058-1663833045.jpg"
+
+// page is what every page gets: the signed-in user (nil for visitors), the
+// CSRF token for its forms, the active ceremony's deadline, the footer entry
+// to highlight and a message about the last action.
+type page struct {
+	User     *models.User
+	CSRF     string
+	Nav      string
+	Ceremony *models.Ceremony
+	Deadline string // in Settings.Location
+	// DeadlineISO feeds the countdown script.
+	DeadlineISO string
+	TimeLeft    string
+	Closed      bool
+	Error       string
+	Notice      string
+}
+
+// userID is the signed-in user's id, or "" for a visitor.
+func (p page) userID() string {
+	if p.User == nil {
+		return ""
+	}
+	return p.User.ID
+}
+
+// newPage loads the common page data; withPageUser has put the user, if
+// any, in the request context.
+func (h *Handler) newPage(w http.ResponseWriter, r *http.Request, nav string) (page, error) {
+	p := page{Nav: nav, CSRF: h.ensureCSRFCookie(w, r), Closed: true}
+	if uid, ok := GetUserIDFromContext(r.Context()); ok {
+		u, err := h.userStore.GetByID(r.Context(), uid)
+		if err != nil {
+			return p, err
+		}
+		p.User = u
+	}
+	cer, err := h.ceremonyStore.GetActive(r.Context())
+	if err != nil {
+		return p, err
+	}
+	if cer != nil {
+		now := time.Now()
+		p.Ceremony = cer
+		p.Deadline = cer.Deadline.In(h.location()).Format("Mon, 02 Jan 2006 15:04 MST")
+		p.DeadlineISO = cer.Deadline.Format(time.RFC3339)
+		p.Closed = !now.Before(cer.Deadline)
+		if !p.Closed {
+			p.TimeLeft = timeLeft(cer.Deadline.Sub(now))
+		}
+	}
+	return p, nil
+}
+
+// timeLeft formats d as "2d 04h 05m", dropping leading zero units.
+func timeLeft(d time.Duration) string {
+	d = d.Round(time.Minute)
+	days, hours, mins := int(d/(24*time.Hour)), int(d/time.Hour)%24, int(d/time.Minute)%60
+	switch {
+	case days > 0:
+		return fmt.Sprintf("%dd %02dh %02dm", days, hours, mins)
+	case hours > 0:
+		return fmt.Sprintf("%dh %02dm", hours, mins)
+	}
+	return fmt.Sprintf("%dm", mins)
+}
+
+// render writes an HTML page of the view set with the given status.
+func (h *Handler) render(w http.ResponseWriter, status int, name string, data interface{}) {
+	if h.pages == nil {
+		writeInternal(w, errors.New("no templates loaded"))
+		return
+	}
+	// rendered to a buffer so a template error can still answer 500
+	var buf bytes.Buffer
+	if err := h.pages.Render(&buf, name, data); err != nil {
+		writeInternal(w, err)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(status)
+	_, _ = buf.WriteTo(w)
+}
+
+// redirectToLogin sends visitors to the login page, which returns them to
+// next once they are signed in.
+func redirectToLogin(w http.ResponseWriter, r *http.Request, next string) {
+	http.Redirect(w, r, "/login/new?next="+url.QueryEscape(next), http.StatusSeeOther)
+}
+
+// localPath returns next if it is a path on this site, "/categories/view"
+// otherwise, so the login form cannot redirect elsewhere.
+func localPath(next string) string {
+	if !strings.HasPrefix(next, "/") || strings.HasPrefix(next, "//") || strings.HasPrefix(next, "/\\") {
+		return "/categories/view"
+	}
+	return next
+}
+
+// ballot is a ceremony's categories in sequence order with their nominees
+// and the titles of the nominees' movies.
+type ballot struct {
+	categories []models.Category
+	nominees   map[string]models.Nominated
+	byCategory map[string][]models.Nominated
+	movies     map[string]string
+}
+
+// loadBallot reads the whole ceremony, paging past the 100-row cap of the
+// List methods.
+func (h *Handler) loadBallot(ctx context.Context, ceremonyID string) (*ballot, error) {
+	cats, err := listAll(func(p store.Page) ([]models.Category, string, error) {
+		return h.categoryStore.ListPage(ctx, store.CategoryFilter{CeremonyID: ceremonyID}, p)
+	})
+	if err != nil {
+		return nil, err
+	}
+	noms, err := listAll(func(p store.Page) ([]models.Nominated, string, error) {
+		return h.nominatedStore.ListPage(ctx, store.NominatedFilter{CeremonyID: ceremonyID}, p)
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(cats, func(i, j int) bool { return cats[i].SequenceOrder < cats[j].SequenceOrder })
+	b := &ballot{categories: cats, nominees: make(map[string]models.Nominated), byCategory: make(map[string][]models.Nominated), movies: make(map[string]string)}
+	movieIDs := make([]string, 0, len(noms))
+	for _, n := range noms {
+		b.nominees[n.ID] = n
+		b.byCategory[n.CategoryID] = append(b.byCategory[n.CategoryID], n)
+		movieIDs = append(movieIDs, n.MovieID)
+	}
+	movies, err := h.movieStore.GetMany(ctx, movieIDs)
+	if err != nil {
+		return nil, err
+	}
+	for _, m := range movies {
+		b.movies[m.ID] = m.Title
+	}
+	return b, nil
+}
+
+// pick is n as the JSON API shows it.
+func (b *ballot) pick(n models.Nominated) *ballotPick {
+	return &ballotPick{NominatedID: n.ID, Name: n.Name, MovieID: n.MovieID, MovieName: b.movies[n.MovieID]}
+}
+
+// nomineeView is a nominee as the pages show it.
+type nomineeView struct {
+	ID, Name, Movie, Image string
+}
+
+func (b *ballot) view(n models.Nominated) nomineeView {
+	v := nomineeView{ID: n.ID, Name: n.Name, Movie: b.movies[n.MovieID], Image: n.UrlImage}
+	if v.Image == "" {
+		v.Image = fallbackImage
+	}
+	return v
+}
+
+// categoryView is a category with a user's pick in it, if any.
+type categoryView struct {
+	ID, Name string
+	Locked   bool
+	Pick     *nomineeView
+}
+
+// categoryViews lists the ballot's categories with the picks (category id ->
+// nominee id) filled in, and counts the picks.
+func (b *ballot) categoryViews(picks map[string]string, closed bool) ([]categoryView, int) {
+	now := time.Now()
+	out := make([]categoryView, 0, len(b.categories))
+	picked := 0
+	for _, c := range b.categories {
+		cv := categoryView{ID: c.ID, Name: c.Name, Locked: closed || c.IsLocked(now)}
+		if n, ok := b.nominees[picks[c.ID]]; ok {
+			v := b.view(n)
+			cv.Pick = &v
+			picked++
+		}
+		out = append(out, cv)
+	}
+	return out, picked
+}
+
+// userPicks maps category id -> nominee id for all of the user's votes in
+// the ceremony; a visitor (userID "") has none.
+func (h *Handler) userPicks(ctx context.Context, userID, ceremonyID string) (map[string]string, error) {
+	picks := make(map[string]string)
+	if userID == "" {
+		return picks, nil
+	}
+	votes, err := listAll(func(p store.Page) ([]models.Vote, string, error) {
+		return h.voteStore.ListPage(ctx, store.VoteFilter{UserID: userID, CeremonyID: ceremonyID}, p)
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, v := range votes {
+		picks[v.CategoryID] = v.NominatedID
+	}
+	return picks, nil
+}
+
+type categoriesPage struct {
+	page
+	Categories []categoryView
+	Picked     int
+}
+
+// ServeCategoriesView renders the active ceremony's categories with the
+// user's pick in each and the time left to vote.
+func (h *Handler) ServeCategoriesView(w http.ResponseWriter, r *http.Request) {
+	p, err := h.newPage(w, r, "vote")
+	if err != nil {
+		writeStoreError(w, err)
+		return
+	}
+	data := categoriesPage{page: p}
+	if p.Ceremony != nil {
+		b, err := h.loadBallot(r.Context(), p.Ceremony.ID)
+		if err != nil {
+			writeStoreError(w, err)
+			return
+		}
+		picks, err := h.userPicks(r.Context(), p.userID(), p.Ceremony.ID)
+		if err != nil {
+			writeStoreError(w, err)
+			return
+		}
+		data.Categories, data.Picked = b.categoryViews(picks, p.Closed)
+	}
+	h.render(w, http.StatusOK, "categories_view.html", data)
+}
+
+type nomineesPage struct {
+	page
+	Category categoryView
+	Nominees []nomineeView
+	Picked   string
+}
+
+// ServeNominatedsView renders the nominees of ?category_id= with a vote
+// button on each; the user's current pick is marked.
+func (h *Handler) ServeNominatedsView(w http.ResponseWriter, r *http.Request) {
+	var notice string
+	if r.URL.Query().Get("voted") != "" {
+		notice = "Your vote was saved."
+	}
+	h.serveNominees(w, r, http.StatusOK, "", notice)
+}
+
+// serveNominees renders the nominees page, with a message about the last
+// vote; VoteFromForm uses it to report a vote it refused.
+func (h *Handler) serveNominees(w http.ResponseWriter, r *http.Request, status int, errMsg, notice string) {
+	categoryID := r.FormValue("category_id")
+	if categoryID == "" {
+		http.Redirect(w, r, "/categories/view", http.StatusSeeOther)
+		return
+	}
+	p, err := h.newPage(w, r, "vote")
+	if err != nil {
+		writeStoreError(w, err)
+		return
+	}
+	p.Error, p.Notice = errMsg, notice
+	cat, err := h.categoryStore.Get(r.Context(), categoryID)
+	if err != nil {
+		writeStoreError(w, err)
+		return
+	}
+	if cat == nil || p.Ceremony == nil || cat.CeremonyID != p.Ceremony.ID {
+		p.Error = "This category is not part of the current ceremony."
+		h.render(w, http.StatusNotFound, "nominateds_view.html", nomineesPage{page: p})
+		return
+	}
+	b, err := h.loadBallot(r.Context(), p.Ceremony.ID)
+	if err != nil {
+		writeStoreError(w, err)
+		return
+	}
+	picks, err := h.userPicks(r.Context(), p.userID(), p.Ceremony.ID)
+	if err != nil {
+		writeStoreError(w, err)
+		return
+	}
+	data := nomineesPage{
+		page:     p,
+		Category: categoryView{ID: cat.ID, Name: cat.Name, Locked: p.Closed || cat.IsLocked(time.Now())},
+		Picked:   picks[cat.ID],
+	}
+	for _, n := range b.byCategory[cat.ID] {
+		data.Nominees = append(data.Nominees, b.view(n))
+	}
+	h.render(w, status, "nominateds_view.html", data)
+}
+
+// VoteFromForm handles the vote buttons of the nominees page (form fields
+// category_id, nominated_id and csrf_token). Visitors are sent to log in.
+func (h *Handler) VoteFromForm(w http.ResponseWriter, r *http.Request) {
+	back := "/nominateds/view?category_id=" + url.QueryEscape(r.FormValue("category_id"))
+	uid, ok := GetUserIDFromContext(r.Context())
+	if !ok {
+		redirectToLogin(w, r, back)
+		return
+	}
+	if !h.validateCSRF(r) {
+		h.serveNominees(w, r, http.StatusForbidden, "Your form expired; please vote again.", "")
+		return
+	}
+	cer, err := h.openCeremony(r.Context())
+	if err == nil {
+		_, _, err = h.castVote(r.Context(), cer, uid, r.FormValue("nominated_id"))
+	}
+	if err != nil {
+		var se *statusError
+		if errors.As(err, &se) {
+			h.serveNominees(w, r, se.status, se.message, "")
+			return
+		}
+		writeStoreError(w, err)
+		return
+	}
+	http.Redirect(w, r, back+"&voted=1", http.StatusSeeOther)
+}
+
+type profilePage struct {
+	page
+	Categories []categoryView
+	Picked     int
+}
+
+// ServeProfileView renders the user's account and their pick in every
+// category, linking to the categories still missing one.
+func (h *Handler) ServeProfileView(w http.ResponseWriter, r *http.Request) {
+	p, err := h.newPage(w, r, "profile")
+	if err != nil {
+		writeStoreError(w, err)
+		return
+	}
+	if p.User == nil {
+		redirectToLogin(w, r, "/profile")
+		return
+	}
+	data := profilePage{page: p}
+	if p.Ceremony != nil {
+		b, err := h.loadBallot(r.Context(), p.Ceremony.ID)
+		if err != nil {
+			writeStoreError(w, err)
+			return
+		}
+		picks, err := h.userPicks(r.Context(), p.userID(), p.Ceremony.ID)
+		if err != nil {
+			writeStoreError(w, err)
+			return
+		}
+		data.Categories, data.Picked = b.categoryViews(picks, p.Closed)
+	}
+	h.render(w, http.StatusOK, "profile_view.html", data)
+}
+
+// participantView is a user with their picks. Picks are only listed once
+// voting has closed, so nobody can copy a ballot before the deadline.
+type participantView struct {
+	Nickname string
+	Picked   int
+	Picks    []categoryView
+}
+
+type participantsPage struct {
+	page
+	Participants []participantView
+	Total        int
+}
+
+// ServeParticipantsView renders the users and how far along their ballots
+// are; after the deadline it lists everyone's picks.
+func (h *Handler) ServeParticipantsView(w http.ResponseWriter, r *http.Request) {
+	p, err := h.newPage(w, r, "participants")
+	if err != nil {
+		writeStoreError(w, err)
+		return
+	}
+	users, err := listAll(func(pg store.Page) ([]models.User, string, error) {
+		return h.userStore.ListPage(r.Context(), store.UserFilter{}, pg)
+	})
+	if err != nil {
+		writeStoreError(w, err)
+		return
+	}
+	data := participantsPage{page: p}
+	var b *ballot
+	picks := make(map[string]map[string]string) // user -> category -> nominee
+	if p.Ceremony != nil {
+		if b, err = h.loadBallot(r.Context(), p.Ceremony.ID); err != nil {
+			writeStoreError(w, err)
+			return
+		}
+		data.Total = len(b.categories)
+		votes, err := listAll(func(pg store.Page) ([]models.Vote, string, error) {
+			return h.voteStore.ListPage(r.Context(), store.VoteFilter{CeremonyID: p.Ceremony.ID}, pg)
+		})
+		if err != nil {
+			writeStoreError(w, err)
+			return
+		}
+		for _, v := range votes {
+			if picks[v.UserID] == nil {
+				picks[v.UserID] = make(map[string]string)
+			}
+			picks[v.UserID][v.CategoryID] = v.NominatedID
+		}
+	}
+	for _, u := range users {
+		pv := participantView{Nickname: u.Nickname}
+		if pv.Nickname == "" {
+			pv.Nickname = "Participant"
+		}
+		if b != nil {
+			cats, picked := b.categoryViews(picks[u.ID], p.Closed)
+			pv.Picked = picked
+			if p.Closed {
+				for _, c := range cats {
+					if c.Pick != nil {
+						pv.Picks = append(pv.Picks, c)
+					}
+				}
+			}
+		}
+		data.Participants = append(data.Participants, pv)
+	}
+	h.render(w, http.StatusOK, "participants_view.html", data)
+}
+
+// leaderboardEntry is a score with its rank; tied users share a rank.
+type leaderboardEntry struct {
+	store.UserScore
+	Rank    int
+	Medal   string
+	Percent int
+}
+
+type scoreView struct {
+	Points, MaxPoints, Percent int
+}
+
+type leaderboardPage struct {
+	page
+	Leagues []models.League
+	League  string
+	Entries []leaderboardEntry
+	Mine    *scoreView
+}
+
+func percent(n, of int) int {
+	if of <= 0 {
+		return 0
+	}
+	return n * 100 / of
+}
+
+// ServeLeaderboardView renders the scores of everyone or, with ?league=, of
+// one of the user's leagues, and the user's own score.
+func (h *Handler) ServeLeaderboardView(w http.ResponseWriter, r *http.Request) {
+	p, err := h.newPage(w, r, "leaderboard")
+	if err != nil {
+		writeStoreError(w, err)
+		return
+	}
+	data := leaderboardPage{page: p}
+	if p.User != nil {
+		if data.Leagues, err = h.leagueStore.ListByUser(r.Context(), p.User.ID); err != nil {
+			writeStoreError(w, err)
+			return
+		}
+	}
+	if p.Ceremony == nil {
+		h.render(w, http.StatusOK, "leaderboard_view.html", data)
+		return
+	}
+	cerID := p.Ceremony.ID
+	var scores []store.UserScore
+	if league := r.URL.Query().Get("league"); league != "" {
+		for _, l := range data.Leagues {
+			if l.ID == league {
+				data.League = league
+			}
+		}
+		if data.League == "" {
+			data.Error = "You are not a member of that league; showing everyone."
+		}
+	}
+	if data.League != "" {
+		scores, err = h.voteStore.GetLeagueScores(r.Context(), data.League, cerID)
+	} else {
+		scores, err = h.voteStore.GetAllScores(r.Context(), cerID)
+	}
+	if err != nil {
+		writeStoreError(w, err)
+		return
+	}
+	medals := map[int]string{1: "🥇", 2: "🥈", 3: "🥉"}
+	rank := 0
+	for i, s := range scores {
+		if i == 0 || s.Points != scores[i-1].Points {
+			rank = i + 1
+		}
+		data.Entries = append(data.Entries, leaderboardEntry{UserScore: s, Rank: rank, Medal: medals[rank], Percent: percent(s.Points, s.MaxPoints)})
+	}
+	if p.User != nil {
+		points, maxPoints, err := h.voteStore.GetUserScore(r.Context(), p.User.ID, cerID)
+		if err != nil {
+			writeStoreError(w, err)
+			return
+		}
+		data.Mine = &scoreView{Points: points, MaxPoints: maxPoints, Percent: percent(points, maxPoints)}
+	}
+	h.render(w, http.StatusOK, "leaderboard_view.html", data)
+}
+
+// winnerNominee is a nominee on the winners page; WinnerID is set when it won.
+type winnerNominee struct {
+	nomineeView
+	WinnerID string
+}
+
+type winnerCategory struct {
+	Name     string
+	Nominees []winnerNominee
+}
+
+type winnersPage struct {
+	page
+	Categories []winnerCategory
+}
+
+// ServeWinnersView renders the admin page for setting the winner of each
+// category.
+func (h *Handler) ServeWinnersView(w http.ResponseWriter, r *http.Request) {
+	h.serveWinners(w, r, http.StatusOK, "")
+}
+
+func (h *Handler) serveWinners(w http.ResponseWriter, r *http.Request, status int, errMsg string) {
+	p, err := h.newPage(w, r, "")
+	if err != nil {
+		writeStoreError(w, err)
+		return
+	}
+	if p.User == nil {
+		redirectToLogin(w, r, "/winners/view")
+		return
+	}
+	p.Error = errMsg
+	if p.User.Role != RoleAdmin {
+		p.Error = "Only admins can set winners."
+		h.render(w, http.StatusForbidden, "winners_view.html", winnersPage{page: p})
+		return
+	}
+	data := winnersPage{page: p}
+	if p.Ceremony != nil {
+		b, err := h.loadBallot(r.Context(), p.Ceremony.ID)
+		if err != nil {
+			writeStoreError(w, err)
+			return
+		}
+		winners, err := h.winnerStore.List(r.Context(), p.Ceremony.ID)
+		if err != nil {
+			writeStoreError(w, err)
+			return
+		}
+		winnerOf := make(map[string]string, len(winners))
+		for _, wn := range winners {
+			winnerOf[wn.NominatedID] = wn.ID
+		}
+		for _, c := range b.categories {
+			wc := winnerCategory{Name: c.Name}
+			for _, n := range b.byCategory[c.ID] {
+				wc.Nominees = append(wc.Nominees, winnerNominee{nomineeView: b.view(n), WinnerID: winnerOf[n.ID]})
+			}
+			data.Categories = append(data.Categories, wc)
+		}
+	}
+	h.render(w, status, "winners_view.html", data)
+}
+
+// WinnerFromForm handles the winners page forms: nominated_id sets a winner,
+// winner_id (with action=remove) removes one.
+func (h *Handler) WinnerFromForm(w http.ResponseWriter, r *http.Request) {
+	if role, _ := GetRoleFromContext(r.Context()); role != RoleAdmin {
+		h.serveWinners(w, r, http.StatusForbidden, "")
+		return
+	}
+	if !h.validateCSRF(r) {
+		h.serveWinners(w, r, http.StatusForbidden, "Your form expired; please try again.")
+		return
+	}
+	var err error
+	if r.FormValue("action") == "remove" {
+		err = h.removeWinner(r.Context(), r.FormValue("winner_id"))
+	} else {
+		_, err = h.setWinner(r.Context(), r.FormValue("nominated_id"))
+	}
+	if err != nil {
+		var se *statusError
+		if errors.As(err, &se) {
+			h.serveWinners(w, r, se.status, se.message)
+			return
+		}
+		writeStoreError(w, err)
+		return
+	}
+	http.Redirect(w, r, "/winners/view", http.StatusSeeOther)
+}
+
+type loginPage struct {
+	page
+	Next, Email, Nickname string
+}
+
+// ServeLoginForm renders the login and registration form; ?next= is where
+// it returns to.
+func (h *Handler) ServeLoginForm(w http.ResponseWriter, r *http.Request) {
+	p, err := h.newPage(w, r, "profile")
+	if err != nil {
+		writeStoreError(w, err)
+		return
+	}
+	h.render(w, http.StatusOK, "login_form.html", loginPage{page: p, Next: localPath(r.URL.Query().Get("next"))})
+}
+
+// LoginFromForm handles the login form: mode=register creates the account
+// first. On success the user is signed in and sent to next.
+func (h *Handler) LoginFromForm(w http.ResponseWriter, r *http.Request) {
+	data := loginPage{Next: localPath(r.FormValue("next")), Email: strings.TrimSpace(r.FormValue("email")), Nickname: strings.TrimSpace(r.FormValue("nickname"))}
+	fail := func(status int, msg string) {
+		p, err := h.newPage(w, r, "profile")
+		if err != nil {
+			writeStoreError(w, err)
+			return
+		}
+		p.Error = msg
+		data.page = p
+		h.render(w, status, "login_form.html", data)
+	}
+	password := r.FormValue("password")
+	if data.Email == "" || password == "" {
+		fail(http.StatusBadRequest, "Email and password are required.")
+		return
+	}
+	var u *models.User
+	var err error
+	if r.FormValue("mode") == "register" {
+		req := registerRequest{Nickname: data.Nickname, Email: data.Email, Password: password}
+		if req.Nickname == "" {
+			req.Nickname, _, _ = strings.Cut(data.Email, "@")
+		}
+		if bio := strings.TrimSpace(r.FormValue("bio")); bio != "" {
+			req.Bio = &bio
+		}
+		u, err = h.registerUser(r.Context(), req)
+		if store.Violation(err) == store.ErrUniqueViolation {
+			fail(http.StatusConflict, "That email is already registered; log in instead.")
+			return
+		}
+	} else {
+		u, err = h.checkCredentials(r.Context(), data.Email, password)
+	}
+	if err != nil {
+		var se *statusError
+		if errors.As(err, &se) {
+			fail(se.status, "Wrong email or password.")
+			return
+		}
+		writeStoreError(w, err)
+		return
+	}
+	if _, err := h.startSession(w, r, u); err != nil {
+		writeInternal(w, err)
+		return
+	}
+	http.Redirect(w, r, data.Next, http.StatusSeeOther)
+}
@@ -0,0 +1,234 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"votacao/internal/store/memstore"
+	"votacao/internal/view"
+	"votacao/models"
+	"votacao/templates"
+)
+
+// pageFixture is a handler over an in-memory store with one active ceremony,
+// one category and two nominees, and the templates loaded.
+type pageFixture struct {
+	t          *testing.T
+	h          *Handler
+	srv        http.Handler
+	ceremonyID string
+	categoryID string
+	nominees   []string
+}
+
+func newPageFixture(t *testing.T, deadline time.Time) *pageFixture {
+	t.Helper()
+	mem := memstore.New()
+	h := New(memstore.NewMovie(mem), memstore.NewCategory(mem), memstore.NewNominated(mem), memstore.NewUser(mem),
+		memstore.NewVote(mem), memstore.NewWinner(mem), memstore.NewCeremony(mem), memstore.NewLeague(mem),
+		memstore.NewSession(mem), nil, "test-secret")
+	pages, err := view.New(templates.FS, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	h.pages = pages
+	ctx := context.Background()
+	cerID, err := h.ceremonyStore.Insert(ctx, &models.Ceremony{Year: 2026, Name: "Oscar 2026", Deadline: deadline, Status: models.CeremonyActive})
+	if err != nil {
+		t.Fatal(err)
+	}
+	catID, err := h.categoryStore.Insert(ctx, &models.Category{CeremonyID: cerID, Name: "Best Picture", Points: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	f := &pageFixture{t: t, h: h, srv: h.Routes(), ceremonyID: cerID, categoryID: catID}
+	for _, title := range []string{"Anora", "Conclave"} {
+		movieID, err := h.movieStore.Insert(ctx, &models.Movie{Title: title})
+		if err != nil {
+			t.Fatal(err)
+		}
+		id, err := h.nominatedStore.Insert(ctx, &models.Nominated{MovieID: movieID, CategoryID: catID, Name: title + " cast"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		f.nominees = append(f.nominees, id)
+	}
+	return f
+}
+
+// do sends a request with the cookies and, for POSTs, the form.
+func (f *pageFixture) do(method, target string, form url.Values, cookies []*http.Cookie) *httptest.ResponseRecorder {
+	f.t.Helper()
+	req := httptest.NewRequest(method, target, strings.NewReader(form.Encode()))
+	if form != nil {
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	}
+	for _, c := range cookies {
+		req.AddCookie(c)
+	}
+	rr := httptest.NewRecorder()
+	f.srv.ServeHTTP(rr, req)
+	return rr
+}
+
+// postJSON sends body as JSON with the cookies and the CSRF header matching
+// register's cookie.
+func (f *pageFixture) postJSON(target string, body interface{}, cookies []*http.Cookie) *httptest.ResponseRecorder {
+	f.t.Helper()
+	b, err := json.Marshal(body)
+	if err != nil {
+		f.t.Fatal(err)
+	}
+	req := httptest.NewRequest(http.MethodPost, target, bytes.NewReader(b))
+	req.Header.Set("X-CSRF-Token", "testcsrf")
+	for _, c := range cookies {
+		req.AddCookie(c)
+	}
+	rr := httptest.NewRecorder()
+	f.srv.ServeHTTP(rr, req)
+	return rr
+}
+
+// addCategory adds a category with n nominees, each of its own movie, after
+// the fixture's, and returns the category id and the nominee ids.
+func (f *pageFixture) addCategory(name string, points, n int) (string, []string) {
+	f.t.Helper()
+	ctx := context.Background()
+	catID, err := f.h.categoryStore.Insert(ctx, &models.Category{CeremonyID: f.ceremonyID, Name: name, Points: points, SequenceOrder: 2})
+	if err != nil {
+		f.t.Fatal(err)
+	}
+	ms := make([]models.Movie, n)
+	for i := range ms {
+		ms[i].Title = fmt.Sprintf("%s movie %03d", name, i)
+	}
+	movieIDs, err := f.h.movieStore.InsertMany(ctx, ms)
+	if err != nil {
+		f.t.Fatal(err)
+	}
+	ns := make([]models.Nominated, n)
+	for i, id := range movieIDs {
+		ns[i] = models.Nominated{MovieID: id, CategoryID: catID, Name: fmt.Sprintf("%s nominee %03d", name, i)}
+	}
+	ids, err := f.h.nominatedStore.InsertMany(ctx, ns)
+	if err != nil {
+		f.t.Fatal(err)
+	}
+	return catID, ids
+}
+
+// register signs a user up through the login form and returns the session
+// cookies plus a CSRF cookie whose value is "testcsrf".
+func (f *pageFixture) register(email string) []*http.Cookie {
+	f.t.Helper()
+	rr := f.do(http.MethodPost, "/login/new", url.Values{"mode": {"register"}, "email": {email}, "password": {"secret123"}, "next": {"/profile"}}, nil)
+	if rr.Code != http.StatusSeeOther || rr.Header().Get("Location") != "/profile" {
+		f.t.Fatalf("register: got %d %s: %s", rr.Code, rr.Header().Get("Location"), rr.Body.String())
+	}
+	return append(rr.Result().Cookies(), &http.Cookie{Name: "csrf_token", Value: "testcsrf"})
+}
+
+func TestPagesUseLayout(t *testing.T) {
+	h := newTestHandler()
+	if rr := serve(h, httptest.NewRequest(http.MethodGet, "/login/new", nil)); rr.Code != http.StatusInternalServerError {
+		t.Fatalf("expected 500 without templates, got %d", rr.Code)
+	}
+	f := newPageFixture(t, time.Now().Add(time.Hour))
+	cookies := f.register("fan@example.com")
+	nominees := "/nominateds/view?category_id=" + f.categoryID
+	for path, want := range map[string]string{
+		"/login/new":        `action="/login/new"`,
+		"/categories/view":  "Best Picture",
+		nominees:            "Anora cast",
+		"/profile":          "0 / 1 votes",
+		"/participants":     "fan",
+		"/leaderboard/view": "Your Score",
+	} {
+		rr := f.do(http.MethodGet, path, nil, cookies)
+		if rr.Code != http.StatusOK || rr.Header().Get("Content-Type") != "text/html; charset=utf-8" {
+			t.Fatalf("%s: got %d %s", path, rr.Code, rr.Header().Get("Content-Type"))
+		}
+		if body := rr.Body.String(); !strings.HasPrefix(body, "<!doctype html>") || !strings.Contains(body, want) {
+			t.Errorf("%s: page lacks the layout or %q", path, want)
+		}
+	}
+	if rr := f.do(http.MethodGet, "/winners/view", nil, cookies); rr.Code != http.StatusForbidden {
+		t.Fatalf("winners page for a user: expected 403 got %d", rr.Code)
+	}
+}
+
+func TestVoteFromForm(t *testing.T) {
+	f := newPageFixture(t, time.Now().Add(time.Hour))
+	vote := url.Values{"category_id": {f.categoryID}, "nominated_id": {f.nominees[1]}, "csrf_token": {"testcsrf"}}
+
+	rr := f.do(http.MethodPost, "/nominateds/view", vote, nil)
+	if rr.Code != http.StatusSeeOther || !strings.HasPrefix(rr.Header().Get("Location"), "/login/new?next=") {
+		t.Fatalf("visitor vote: expected a redirect to login, got %d %s", rr.Code, rr.Header().Get("Location"))
+	}
+	if rr := f.do(http.MethodGet, "/profile", nil, nil); rr.Code != http.StatusSeeOther {
+		t.Fatalf("visitor profile: expected 303 got %d", rr.Code)
+	}
+
+	cookies := f.register("fan@example.com")
+	bad := url.Values{"category_id": {f.categoryID}, "nominated_id": {f.nominees[1]}, "csrf_token": {"stale"}}
+	if rr := f.do(http.MethodPost, "/nominateds/view", bad, cookies); rr.Code != http.StatusForbidden || !strings.Contains(rr.Body.String(), "Your form expired") {
+		t.Fatalf("stale csrf: expected the page with an error, got %d", rr.Code)
+	}
+	rr = f.do(http.MethodPost, "/nominateds/view", vote, cookies)
+	if rr.Code != http.StatusSeeOther {
+		t.Fatalf("vote: expected 303 got %d: %s", rr.Code, rr.Body.String())
+	}
+	body := f.do(http.MethodGet, rr.Header().Get("Location"), nil, cookies).Body.String()
+	if !strings.Contains(body, "Your vote was saved.") || strings.Count(body, ">Voted</button>") != 1 {
+		t.Fatalf("vote not shown on the nominees page:\n%s", body)
+	}
+	if body := f.do(http.MethodGet, "/categories/view", nil, cookies).Body.String(); !strings.Contains(body, "You voted: Conclave cast") {
+		t.Fatal("pick not shown on the categories page")
+	}
+	// picks stay hidden from everyone until voting closes
+	if body := f.do(http.MethodGet, "/participants", nil, nil).Body.String(); strings.Contains(body, "Conclave cast") || !strings.Contains(body, "1 / 1 votes") {
+		t.Fatal("participants page should show progress but not picks before the deadline")
+	}
+}
+
+func TestLoginFromForm(t *testing.T) {
+	f := newPageFixture(t, time.Now().Add(time.Hour))
+	f.register("fan@example.com")
+	login := func(password, next string) *httptest.ResponseRecorder {
+		return f.do(http.MethodPost, "/login/new", url.Values{"email": {"fan@example.com"}, "password": {password}, "next": {next}}, nil)
+	}
+	if rr := login("wrong", "/profile"); rr.Code != http.StatusUnauthorized || !strings.Contains(rr.Body.String(), "Wrong email or password.") {
+		t.Fatalf("bad password: got %d", rr.Code)
+	}
+	if rr := login("secret123", "//evil.example"); rr.Code != http.StatusSeeOther || rr.Header().Get("Location") != "/categories/view" {
+		t.Fatalf("foreign next: got %d %s", rr.Code, rr.Header().Get("Location"))
+	}
+	rr := f.do(http.MethodPost, "/login/new", url.Values{"mode": {"register"}, "email": {"fan@example.com"}, "password": {"other123"}}, nil)
+	if rr.Code != http.StatusConflict {
+		t.Fatalf("duplicate registration: expected 409 got %d", rr.Code)
+	}
+}
+
+func TestParticipantsPastListCap(t *testing.T) {
+	f := newPageFixture(t, time.Now().Add(time.Hour))
+	for i := 0; i < 120; i++ {
+		u := &models.User{Nickname: fmt.Sprintf("player%03d", i), Email: fmt.Sprintf("player%03d@example.com", i), PasswordHash: "x"}
+		if _, err := f.h.userStore.Insert(context.Background(), u); err != nil {
+			t.Fatal(err)
+		}
+	}
+	body := f.do(http.MethodGet, "/participants", nil, nil).Body.String()
+	for _, nick := range []string{"player000", "player119"} {
+		if !strings.Contains(body, nick) {
+			t.Errorf("participants page lacks %s", nick)
+		}
+	}
+}
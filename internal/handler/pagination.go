@@ -0,0 +1,53 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"votacao/internal/store"
+)
+
+// pageParams reads ?limit= and ?cursor= of the list endpoints. limit defaults
+// to store.DefaultPageLimit and is capped at store.MaxPageLimit.
+func pageParams(w http.ResponseWriter, r *http.Request) (store.Page, bool) {
+	q := r.URL.Query()
+	p := store.Page{Cursor: q.Get("cursor")}
+	if v := q.Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 {
+			writeInvalid(w, "limit must be a positive integer", map[string]string{"limit": "must be a positive integer"})
+			return p, false
+		}
+		p.Limit = n
+	}
+	return p, true
+}
+
+// listAll calls a ListPage method with the largest page size until the last
+// page and returns every row, for callers that must not miss any.
+func listAll[T any](list func(p store.Page) ([]T, string, error)) ([]T, error) {
+	var out []T
+	p := store.Page{Limit: store.MaxPageLimit}
+	for {
+		items, next, err := list(p)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, items...)
+		if next == "" {
+			return out, nil
+		}
+		p.Cursor = next
+	}
+}
+
+// writePage writes one page of a list endpoint as {items, next_cursor};
+// next_cursor is omitted on the last page.
+func writePage(w http.ResponseWriter, items interface{}, next string) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(struct {
+		Items      interface{} `json:"items"`
+		NextCursor string      `json:"next_cursor,omitempty"`
+	}{Items: items, NextCursor: next})
+}
@@ -0,0 +1,96 @@
+package handler
+
+import "time"
+
+// Request bodies that are not a models struct, named for the same reason as
+// the response bodies in responses.go. On the /api/v1 routes an id in the path
+// takes precedence over the id field of the body.
+
+type registerRequest struct {
+	Nickname string  `json:"nickname"`
+	Email    string  `json:"email"`
+	Password string  `json:"password"`
+	Bio      *string `json:"bio,omitempty"`
+}
+
+type loginRequest struct {
+	Email    string `json:"email"`
+	Password string `json:"password"`
+}
+
+type refreshRequest struct {
+	RefreshToken string `json:"refresh_token,omitempty"`
+}
+
+type setRoleRequest struct {
+	UserID string `json:"user_id,omitempty"`
+	Role   string `json:"role"`
+}
+
+type updateMovieRequest struct {
+	ID    string `json:"id,omitempty"`
+	Title string `json:"title"`
+}
+
+// categoryRequest is a new category. Points is store.DefaultCategoryPoints
+// when absent; 0 makes a category that scores nothing.
+type categoryRequest struct {
+	CeremonyID    string `json:"ceremony_id,omitempty"`
+	Name          string `json:"name"`
+	SequenceOrder int    `json:"sequence_order,omitempty"`
+	Points        *int   `json:"points,omitempty"`
+}
+
+// updateCategoryRequest changes only the fields that are present.
+type updateCategoryRequest struct {
+	ID            string  `json:"id,omitempty"`
+	Name          *string `json:"name,omitempty"`
+	SequenceOrder *int    `json:"sequence_order,omitempty"`
+	Points        *int    `json:"points,omitempty"`
+}
+
+// updateNominatedRequest changes only the fields that are present.
+type updateNominatedRequest struct {
+	ID       string  `json:"id,omitempty"`
+	MovieID  *string `json:"movie_id,omitempty"`
+	Name     *string `json:"name,omitempty"`
+	UrlImage *string `json:"url_image,omitempty"`
+}
+
+// lockCategoryRequest locks the category when both locked and locks_at are
+// absent; locks_at schedules an automatic lock, and null clears it. An absent
+// field keeps the stored value.
+type lockCategoryRequest struct {
+	ID      string     `json:"id,omitempty"`
+	Locked  *bool      `json:"locked,omitempty"`
+	LocksAt *time.Time `json:"locks_at,omitempty"`
+	// hasLocksAt tells an explicit null locks_at from an absent one
+	hasLocksAt bool
+}
+
+type nominateByNamesRequest struct {
+	CategoryID string   `json:"category_id"`
+	Names      []string `json:"names"`
+}
+
+type nominatedIDRequest struct {
+	NominatedID string `json:"nominated_id"`
+}
+
+// ballotRequest maps category id -> nominee id.
+type ballotRequest struct {
+	Votes map[string]string `json:"votes"`
+}
+
+type leagueRequest struct {
+	Name string `json:"name"`
+}
+
+type joinLeagueRequest struct {
+	InviteCode string `json:"invite_code"`
+}
+
+// recomputeRequest optionally sets new category points, keyed by category id.
+type recomputeRequest struct {
+	Points map[string]int `json:"points,omitempty"`
+}
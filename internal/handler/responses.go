@@ -0,0 +1,189 @@
+package handler
+
+import (
+	"time"
+
+	"votacao/models"
+)
+
+// Response bodies that are not a models struct. They are named, rather than
+// declared inline in the handlers, so the OpenAPI spec can be derived from
+// them (see openapi.go).
+
+// nominatedOut is a nomination enriched with its movie title.
+type nominatedOut struct {
+	ID         string `json:"id,omitempty"`
+	MovieID    string `json:"movie_id"`
+	CategoryID string `json:"category_id"`
+	Name       string `json:"name"`
+	Image      string `json:"image,omitempty"`
+	MovieName  string `json:"movie_name,omitempty"`
+}
+
+// nomineeOut is one nominee of categoryWithNominees.
+type nomineeOut struct {
+	ID        string `json:"id"`
+	Name      string `json:"name"`
+	MovieID   string `json:"movie_id"`
+	MovieName string `json:"movie_name,omitempty"`
+	Image     string `json:"image,omitempty"`
+}
+
+type categoryWithNominees struct {
+	ID            string       `json:"id"`
+	Name          string       `json:"name"`
+	SequenceOrder int          `json:"sequence_order"`
+	Nominees      []nomineeOut `json:"nominees"`
+}
+
+// userOut is the public view of a user returned by register.
+type userOut struct {
+	ID        string    `json:"id"`
+	Nickname  string    `json:"nickname"`
+	Email     string    `json:"email"`
+	Bio       *string   `json:"bio,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// meOut is userOut plus the role, returned to the user themselves.
+type meOut struct {
+	ID        string    `json:"id"`
+	Nickname  string    `json:"nickname"`
+	Email     string    `json:"email"`
+	Bio       *string   `json:"bio,omitempty"`
+	Role      string    `json:"role"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// userSummary is one item of the users list.
+type userSummary struct {
+	ID       string `json:"id"`
+	Nickname string `json:"nickname"`
+	Email    string `json:"email"`
+}
+
+type loginResponse struct {
+	Status string `json:"status"`
+	tokenResponse
+}
+
+type roleResponse struct {
+	ID   string `json:"id"`
+	Role string `json:"role"`
+}
+
+type sessionOut struct {
+	models.Session
+	Current bool `json:"current"`
+}
+
+type revokedResponse struct {
+	Revoked int `json:"revoked"`
+}
+
+// voteResult is returned by add vote; created is false when an earlier pick
+// in the category was replaced.
+type voteResult struct {
+	Created bool        `json:"created"`
+	Vote    models.Vote `json:"vote"`
+}
+
+// ballotResult is returned by submit ballot, in the ceremony's category
+// order; status is "created", "changed" or "unchanged".
+type ballotResult struct {
+	Votes []ballotVote `json:"votes"`
+}
+
+type ballotVote struct {
+	CategoryID  string `json:"category_id"`
+	NominatedID string `json:"nominated_id"`
+	VoteID      int64  `json:"vote_id"`
+	Status      string `json:"status"`
+}
+
+// ballotStatus is the user's progress through a ceremony's ballot. Missing
+// holds the ids of the categories without a pick.
+type ballotStatus struct {
+	CeremonyID string `json:"ceremony_id"`
+	Deadline   string `json:"deadline"`
+	Closed     bool   `json:"closed"`
+	// TimeLeftSeconds is 0 once voting has closed.
+	TimeLeftSeconds int64            `json:"time_left_seconds"`
+	Total           int              `json:"total"`
+	Picked          int              `json:"picked"`
+	Percent         int              `json:"percent"`
+	Categories      []ballotCategory `json:"categories"`
+	Missing         []string         `json:"missing"`
+}
+
+type ballotCategory struct {
+	ID            string      `json:"id"`
+	Name          string      `json:"name"`
+	SequenceOrder int         `json:"sequence_order"`
+	Locked        bool        `json:"locked"`
+	Pick          *ballotPick `json:"pick"`
+}
+
+type ballotPick struct {
+	NominatedID string `json:"nominated_id"`
+	Name        string `json:"name"`
+	MovieID     string `json:"movie_id"`
+	MovieName   string `json:"movie_name"`
+}
+
+// scorecard breaks a user's score down by category. Points and MaxPoints
+// match GET /score.
+type scorecard struct {
+	UserID     string              `json:"user_id"`
+	CeremonyID string              `json:"ceremony_id"`
+	Points     int                 `json:"points"`
+	MaxPoints  int                 `json:"max_points"`
+	Categories []scorecardCategory `json:"categories"`
+}
+
+// scorecardCategory is one category of a scorecard. Pending is true until a
+// winner is announced; Weight is what a correct pick earns.
+type scorecardCategory struct {
+	ID      string       `json:"id"`
+	Name    string       `json:"name"`
+	Weight  int          `json:"weight"`
+	Pick    *ballotPick  `json:"pick"`
+	Winners []ballotPick `json:"winners"`
+	Correct bool         `json:"correct"`
+	Points  int          `json:"points"`
+	Pending bool         `json:"pending"`
+}
+
+type voteHistory struct {
+	UserID            string             `json:"user_id"`
+	Events            []models.VoteEvent `json:"events"`
+	Changes           int                `json:"changes"`
+	ChangesByCategory map[string]int     `json:"changes_by_category"`
+}
+
+// categoryState is the lock state of one category in deadlineResponse.
+type categoryState struct {
+	ID      string `json:"id"`
+	Name    string `json:"name"`
+	LocksAt string `json:"locks_at,omitempty"`
+	Locked  bool   `json:"locked"`
+}
+
+type deadlineResponse struct {
+	CeremonyID string          `json:"ceremony_id"`
+	Ceremony   string          `json:"ceremony"`
+	Deadline   string          `json:"deadline"`
+	ServerTime string          `json:"server_time"`
+	Closed     bool            `json:"closed"`
+	Categories []categoryState `json:"categories"`
+}
+
+type scoreResponse struct {
+	Points    int `json:"points"`
+	MaxPoints int `json:"max_points"`
+}
+
+// errorResponse is the error envelope written by writeAPIError.
+type errorResponse struct {
+	Error apiError `json:"error"`
+}
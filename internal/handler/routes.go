@@ -0,0 +1,298 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"votacao/internal/router"
+)
+
+// param returns the {name} path parameter of the /api/v1 routes, falling back
+// to the ?name= query parameter used by the legacy routes.
+func param(r *http.Request, name string) string {
+	if v := router.Param(r, name); v != "" {
+		return v
+	}
+	return r.URL.Query().Get(name)
+}
+
+// deprecated marks a legacy route's responses with a Deprecation header and a
+// Link to the /api/v1 route replacing it.
+func deprecated(successor string, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Deprecation", "true")
+		w.Header().Set("Link", "<"+successor+`>; rel="successor-version"`)
+		next(w, r)
+	}
+}
+
+// withDBTimeout gives the request context a deadline of Settings.DBTimeout, which
+// the stores pass on to their queries.
+func (h *Handler) withDBTimeout(next http.HandlerFunc) http.HandlerFunc {
+	if h.settings.DBTimeout <= 0 {
+		return next
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(r.Context(), h.settings.DBTimeout)
+		defer cancel()
+		next(w, r.WithContext(ctx))
+	}
+}
+
+// streamRoutes are the long-lived API routes that must not get a DB deadline.
+var streamRoutes = map[string]bool{
+	"GET /api/v1/events": true,
+}
+
+// Routes returns the application's HTTP handler: the /api/v1 resource API, the
+// HTML pages, the OpenAPI spec and the original RPC-style routes, which stay
+// as deprecated aliases until the pages are migrated. Methods are enforced by
+// the router, which answers 405 for any other method. Every response carries
+// an X-Request-ID, which error bodies and the access log repeat.
+func (h *Handler) Routes() http.Handler {
+	rt := router.New()
+	// every route records its pattern for the access log and the metrics
+	handle := func(method, path string, hf http.HandlerFunc) {
+		rt.HandleFunc(method, path, withRoute(path, hf))
+	}
+	api := make(map[string]http.HandlerFunc)
+	for _, rte := range h.apiRoutes() {
+		key := rte.method + " " + rte.path
+		hf := rte.handler
+		if !streamRoutes[key] {
+			hf = h.withDBTimeout(hf)
+		}
+		handle(rte.method, rte.path, hf)
+		api[key] = hf
+	}
+	for _, l := range legacyRoutes {
+		next := api[l.successor]
+		if next == nil || (l.byID != "" && api[l.byID] == nil) {
+			panic("handler: legacy route " + l.path + " has no successor " + l.successor)
+		}
+		if l.byID != "" {
+			next = byID(api[l.byID], next)
+		}
+		handle(l.method, l.path, deprecated(successorPath(l.successor), next))
+	}
+
+	// API description
+	handle(http.MethodGet, "/openapi.json", h.ServeOpenAPI)
+	handle(http.MethodGet, "/docs", h.ServeAPIDocs)
+
+	// HTML pages, rendered from the stores; their forms post back to them
+	pg := func(hf http.HandlerFunc) http.HandlerFunc { return h.withDBTimeout(h.withPageUser(hf)) }
+	handle(http.MethodGet, "/login/new", pg(h.ServeLoginForm))
+	handle(http.MethodPost, "/login/new", pg(h.LoginFromForm))
+	handle(http.MethodGet, "/categories/view", pg(h.ServeCategoriesView))
+	handle(http.MethodGet, "/nominateds/view", pg(h.ServeNominatedsView))
+	handle(http.MethodPost, "/nominateds/view", pg(h.VoteFromForm))
+	handle(http.MethodGet, "/profile", pg(h.ServeProfileView))
+	handle(http.MethodGet, "/participants", pg(h.ServeParticipantsView))
+	handle(http.MethodGet, "/leaderboard/view", pg(h.ServeLeaderboardView))
+	handle(http.MethodGet, "/winners/view", pg(h.ServeWinnersView))
+	handle(http.MethodPost, "/winners/view", pg(h.WinnerFromForm))
+	handle(http.MethodGet, "/nominated/new", pg(h.ServeNominatedForm))
+	handle(http.MethodPost, "/nominated/create", h.withDBTimeout(h.RequireRole(RoleAdmin)(h.CreateNominatedFromForm)))
+
+	handle(http.MethodGet, "/healthz", h.Healthz)
+	// only with a token; otherwise main serves it on the metrics address, if any
+	if h.settings.MetricsToken != "" {
+		handle(http.MethodGet, "/metrics", h.ServeMetrics)
+	}
+
+	// unknown API paths are 404; anything else goes to the categories page
+	rt.NotFound = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasPrefix(r.URL.Path, "/api/") {
+			writeError(w, http.StatusNotFound, codeNotFound, "no such endpoint")
+			return
+		}
+		http.Redirect(w, r, "/categories/view", http.StatusSeeOther)
+	})
+	rt.MethodNotAllowed = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeError(w, http.StatusMethodNotAllowed, codeMethodNotAllowed, "method not allowed; allowed: "+w.Header().Get("Allow"))
+	})
+	return withRequestID(withAccessLog(rt))
+}
+
+// route is one /api/v1 operation; handler has its auth middleware applied.
+type route struct {
+	method, path string
+	handler      http.HandlerFunc
+}
+
+// apiRoutes lists the /api/v1 resource API. Every route must be described in
+// the OpenAPI spec (see openapi.go); the tests enforce it.
+func (h *Handler) apiRoutes() []route {
+	auth := h.RequireAuth
+	admin := h.RequireRole(RoleAdmin)
+	return []route{
+		// movies
+		{http.MethodGet, "/api/v1/movies", h.ListMovies},
+		{http.MethodPost, "/api/v1/movies", admin(h.AddMovie)},
+		{http.MethodPost, "/api/v1/movies/batch", admin(h.AddMovies)},
+		{http.MethodGet, "/api/v1/movies/{id}", h.GetMovie},
+		{http.MethodPatch, "/api/v1/movies/{id}", admin(h.UpdateMovie)},
+		{http.MethodDelete, "/api/v1/movies/{id}", admin(h.DeleteMovie)},
+
+		// categories
+		{http.MethodGet, "/api/v1/categories", h.ListCategories},
+		{http.MethodPost, "/api/v1/categories", admin(h.AddCategory)},
+		{http.MethodPost, "/api/v1/categories/batch", admin(h.AddCategories)},
+		{http.MethodGet, "/api/v1/categories/{id}", h.GetCategory},
+		{http.MethodPatch, "/api/v1/categories/{id}", admin(h.UpdateCategory)},
+		{http.MethodDelete, "/api/v1/categories/{id}", admin(h.DeleteCategory)},
+		{http.MethodPut, "/api/v1/categories/{id}/lock", admin(h.LockCategory)},
+		{http.MethodGet, "/api/v1/categories/{category_id}/nominees", h.ListNominatedsByCategory},
+
+		// nominees
+		{http.MethodGet, "/api/v1/nominees", h.ListNominateds},
+		{http.MethodPost, "/api/v1/nominees", admin(h.AddNominated)},
+		{http.MethodPost, "/api/v1/nominees/batch", admin(h.AddNominateds)},
+		{http.MethodPost, "/api/v1/nominees/batch_by_name", admin(h.AddNominatedsByNames)},
+		{http.MethodGet, "/api/v1/nominees/by_category", h.NomineesByCategory},
+		{http.MethodGet, "/api/v1/nominees/{id}", h.GetNominated},
+		{http.MethodPatch, "/api/v1/nominees/{id}", admin(h.UpdateNominated)},
+		{http.MethodDelete, "/api/v1/nominees/{id}", admin(h.DeleteNominated)},
+
+		// winners
+		{http.MethodGet, "/api/v1/winners", h.ListWinners},
+		{http.MethodPost, "/api/v1/winners", admin(h.AddWinner)},
+		{http.MethodDelete, "/api/v1/winners/{id}", admin(h.DeleteWinner)},
+
+		// ceremonies
+		{http.MethodGet, "/api/v1/ceremonies", h.ListCeremonies},
+		{http.MethodPost, "/api/v1/ceremonies", admin(h.AddCeremony)},
+		{http.MethodGet, "/api/v1/ceremonies/active", h.GetActiveCeremony},
+		{http.MethodPost, "/api/v1/ceremonies/{id}/activate", admin(h.ActivateCeremony)},
+		{http.MethodGet, "/api/v1/deadline", h.GetDeadline},
+
+		// auth and sessions
+		{http.MethodPost, "/api/v1/register", h.Register},
+		{http.MethodPost, "/api/v1/login", h.Login},
+		{http.MethodPost, "/api/v1/logout", h.Logout},
+		{http.MethodGet, "/api/v1/me", auth(h.Me)},
+		{http.MethodPost, "/api/v1/token/refresh", h.RefreshToken},
+		{http.MethodGet, "/api/v1/sessions", auth(h.ListSessions)},
+		{http.MethodDelete, "/api/v1/sessions", auth(h.RevokeAllSessions)},
+		{http.MethodDelete, "/api/v1/sessions/{id}", auth(h.RevokeSession)},
+
+		// users and admin
+		{http.MethodGet, "/api/v1/users", h.ListUsers},
+		{http.MethodPut, "/api/v1/users/{id}/role", admin(h.SetUserRole)},
+		{http.MethodGet, "/api/v1/users/{user_id}/votes/history", admin(h.GetUserVoteHistory)},
+		{http.MethodGet, "/api/v1/users/{id}/scorecard", auth(h.GetUserScorecard)},
+		{http.MethodPost, "/api/v1/admin/bootstrap", auth(h.BootstrapAdmin)},
+		{http.MethodPost, "/api/v1/admin/scores/recompute", admin(h.RecomputeScores)},
+
+		// votes and scores
+		{http.MethodGet, "/api/v1/votes", auth(h.ListVotes)},
+		{http.MethodPost, "/api/v1/votes", auth(h.AddVote)},
+		{http.MethodPost, "/api/v1/ballot", auth(h.SubmitBallot)},
+		{http.MethodGet, "/api/v1/ballot/status", auth(h.GetBallotStatus)},
+		{http.MethodGet, "/api/v1/votes/history", auth(h.GetVoteHistory)},
+		{http.MethodGet, "/api/v1/score", auth(h.GetMyScore)},
+		{http.MethodGet, "/api/v1/scorecard", auth(h.GetScorecard)},
+		{http.MethodGet, "/api/v1/leaderboard", h.GetLeaderboard},
+
+		// leagues
+		{http.MethodGet, "/api/v1/leagues", auth(h.ListMyLeagues)},
+		{http.MethodPost, "/api/v1/leagues", auth(h.CreateLeague)},
+		{http.MethodPost, "/api/v1/leagues/join", auth(h.JoinLeague)},
+		{http.MethodPost, "/api/v1/leagues/{id}/leave", auth(h.LeaveLeague)},
+		{http.MethodGet, "/api/v1/leagues/{id}/leaderboard", auth(h.GetLeagueLeaderboard)},
+
+		// live results stream (Server-Sent Events)
+		{http.MethodGet, "/api/v1/events", h.ServeEvents},
+	}
+}
+
+// legacyRoute is an original RPC-style route kept as a deprecated alias of an
+// /api/v1 route; ids are passed as ?id= instead of in the path. successor and
+// byID are "METHOD /api/v1/path" keys of apiRoutes; when byID is set, requests
+// carrying ?id= are served by it instead of successor.
+type legacyRoute struct {
+	method, path    string
+	successor, byID string
+}
+
+var legacyRoutes = []legacyRoute{
+	{http.MethodPost, "/add_movie", "POST /api/v1/movies", ""},
+	{http.MethodPost, "/add_movies", "POST /api/v1/movies/batch", ""},
+	{http.MethodPost, "/update_movie", "PATCH /api/v1/movies/{id}", ""},
+	{http.MethodDelete, "/delete_movie", "DELETE /api/v1/movies/{id}", ""},
+	{http.MethodGet, "/movies", "GET /api/v1/movies", "GET /api/v1/movies/{id}"},
+
+	{http.MethodPost, "/add_category", "POST /api/v1/categories", ""},
+	{http.MethodPost, "/add_categories", "POST /api/v1/categories/batch", ""},
+	{http.MethodPost, "/update_category", "PATCH /api/v1/categories/{id}", ""},
+	{http.MethodPost, "/lock_category", "PUT /api/v1/categories/{id}/lock", ""},
+	{http.MethodDelete, "/delete_category", "DELETE /api/v1/categories/{id}", ""},
+	{http.MethodGet, "/categories", "GET /api/v1/categories", "GET /api/v1/categories/{id}"},
+
+	{http.MethodPost, "/add_nominated", "POST /api/v1/nominees", ""},
+	{http.MethodPost, "/add_nominateds", "POST /api/v1/nominees/batch", ""},
+	{http.MethodPost, "/add_nominateds_names", "POST /api/v1/nominees/batch_by_name", ""},
+	{http.MethodPost, "/update_nominated", "PATCH /api/v1/nominees/{id}", ""},
+	{http.MethodDelete, "/delete_nominated", "DELETE /api/v1/nominees/{id}", ""},
+	{http.MethodGet, "/nominateds", "GET /api/v1/nominees", "GET /api/v1/nominees/{id}"},
+	{http.MethodGet, "/nominateds/by_category", "GET /api/v1/categories/{category_id}/nominees", ""},
+	{http.MethodGet, "/nominees_by_category", "GET /api/v1/nominees/by_category", ""},
+
+	{http.MethodGet, "/winners", "GET /api/v1/winners", ""},
+	{http.MethodPost, "/add_winner", "POST /api/v1/winners", ""},
+	{http.MethodDelete, "/delete_winner", "DELETE /api/v1/winners/{id}", ""},
+
+	{http.MethodGet, "/ceremonies", "GET /api/v1/ceremonies", ""},
+	{http.MethodGet, "/ceremonies/active", "GET /api/v1/ceremonies/active", ""},
+	{http.MethodPost, "/ceremonies/activate", "POST /api/v1/ceremonies/{id}/activate", ""},
+	{http.MethodPost, "/add_ceremony", "POST /api/v1/ceremonies", ""},
+	{http.MethodGet, "/deadline", "GET /api/v1/deadline", ""},
+
+	{http.MethodPost, "/register", "POST /api/v1/register", ""},
+	{http.MethodPost, "/login", "POST /api/v1/login", ""},
+	{http.MethodPost, "/logout", "POST /api/v1/logout", ""},
+	{http.MethodGet, "/me", "GET /api/v1/me", ""},
+	{http.MethodPost, "/token/refresh", "POST /api/v1/token/refresh", ""},
+	{http.MethodGet, "/sessions", "GET /api/v1/sessions", ""},
+	{http.MethodPost, "/sessions/revoke", "DELETE /api/v1/sessions/{id}", ""},
+	{http.MethodPost, "/sessions/revoke_all", "DELETE /api/v1/sessions", ""},
+
+	{http.MethodGet, "/users", "GET /api/v1/users", ""},
+	{http.MethodPost, "/admin/bootstrap", "POST /api/v1/admin/bootstrap", ""},
+	{http.MethodPost, "/admin/users/role", "PUT /api/v1/users/{id}/role", ""},
+	{http.MethodGet, "/admin/votes/history", "GET /api/v1/users/{user_id}/votes/history", ""},
+	{http.MethodPost, "/admin/scores/recompute", "POST /api/v1/admin/scores/recompute", ""},
+
+	{http.MethodPost, "/add_vote", "POST /api/v1/votes", ""},
+	{http.MethodGet, "/votes", "GET /api/v1/votes", ""},
+	{http.MethodGet, "/votes/history", "GET /api/v1/votes/history", ""},
+	{http.MethodGet, "/score", "GET /api/v1/score", ""},
+	{http.MethodGet, "/leaderboard", "GET /api/v1/leaderboard", ""},
+
+	{http.MethodGet, "/leagues", "GET /api/v1/leagues", ""},
+	{http.MethodPost, "/add_league", "POST /api/v1/leagues", ""},
+	{http.MethodPost, "/leagues/join", "POST /api/v1/leagues/join", ""},
+	{http.MethodPost, "/leagues/leave", "POST /api/v1/leagues/{id}/leave", ""},
+	{http.MethodGet, "/leagues/{id}/leaderboard", "GET /api/v1/leagues/{id}/leaderboard", ""},
+
+	{http.MethodGet, "/events", "GET /api/v1/events", ""},
+}
+
+// byID serves get when ?id= is present and list otherwise.
+func byID(get, list http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("id") != "" {
+			get(w, r)
+			return
+		}
+		list(w, r)
+	}
+}
+
+// successorPath returns the path of a "METHOD /path" route key.
+func successorPath(key string) string {
+	_, path, _ := strings.Cut(key, " ")
+	return path
+}
@@ -0,0 +1,328 @@
+package handler
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"time"
+
+	"votacao/models"
+)
+
+// refreshReuseGrace is how long the previous refresh token of a session is
+// still accepted after a rotation. It covers a browser firing several requests
+// with the same expired cookie; reuse after the grace period is treated as
+// token theft and revokes the session.
+const refreshReuseGrace = 30 * time.Second
+
+var errInvalidRefreshToken = errors.New("invalid refresh token")
+
+// hashToken returns the hex sha256 of a refresh token; only hashes are stored.
+func hashToken(tok string) string {
+	sum := sha256.Sum256([]byte(tok))
+	return hex.EncodeToString(sum[:])
+}
+
+// newRefreshToken returns a random opaque refresh token.
+func newRefreshToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+func clientIP(r *http.Request) string {
+	if host, _, err := net.SplitHostPort(r.RemoteAddr); err == nil {
+		return host
+	}
+	return r.RemoteAddr
+}
+
+// setRefreshCookie stores the refresh token in the HttpOnly "refresh_token" cookie.
+func (h *Handler) setRefreshCookie(w http.ResponseWriter, tok string) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     "refresh_token",
+		Value:    tok,
+		Path:     "/",
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
+		Secure:   h.settings.CookieSecure,
+		Expires:  time.Now().Add(refreshTokenTTL),
+	})
+}
+
+// clearAuthCookies removes the access and refresh token cookies.
+func (h *Handler) clearAuthCookies(w http.ResponseWriter) {
+	for _, name := range []string{"jwt", "refresh_token"} {
+		http.SetCookie(w, &http.Cookie{
+			Name:     name,
+			Value:    "",
+			Path:     "/",
+			HttpOnly: true,
+			Expires:  time.Unix(0, 0),
+			MaxAge:   -1,
+			Secure:   h.settings.CookieSecure,
+		})
+	}
+}
+
+func hasRefreshCookie(r *http.Request) bool {
+	c, err := r.Cookie("refresh_token")
+	return err == nil && c.Value != ""
+}
+
+// tokenResponse is returned by login and /token/refresh for API clients.
+type tokenResponse struct {
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token,omitempty"`
+	TokenType    string `json:"token_type"`
+	ExpiresIn    int    `json:"expires_in"`
+}
+
+func newTokenResponse(access, refresh string) tokenResponse {
+	return tokenResponse{AccessToken: access, RefreshToken: refresh, TokenType: "Bearer", ExpiresIn: int(accessTokenTTL.Seconds())}
+}
+
+// startSession creates a session for the user and sets the jwt and
+// refresh_token cookies. It returns the tokens for API clients.
+func (h *Handler) startSession(w http.ResponseWriter, r *http.Request, u *models.User) (tokenResponse, error) {
+	refresh, err := newRefreshToken()
+	if err != nil {
+		return tokenResponse{}, err
+	}
+	sess := &models.Session{
+		UserID:    u.ID,
+		TokenHash: hashToken(refresh),
+		UserAgent: r.UserAgent(),
+		IP:        clientIP(r),
+		ExpiresAt: time.Now().Add(refreshTokenTTL),
+	}
+	id, err := h.sessionStore.Insert(r.Context(), sess)
+	if err != nil {
+		return tokenResponse{}, err
+	}
+	access, err := h.generateToken(u, id)
+	if err != nil {
+		return tokenResponse{}, err
+	}
+	h.setJWTCookie(w, access)
+	h.setRefreshCookie(w, refresh)
+	return newTokenResponse(access, refresh), nil
+}
+
+// rotateSession exchanges a refresh token for a new one and returns the
+// session, its user and the new refresh token. Presenting the previous token
+// within refreshReuseGrace of the rotation returns an empty new token (the
+// concurrent request that rotated already holds it); any later reuse revokes
+// the session.
+func (h *Handler) rotateSession(ctx context.Context, refresh string) (*models.Session, *models.User, string, error) {
+	hash := hashToken(refresh)
+	sess, err := h.sessionStore.GetByTokenHash(ctx, hash)
+	if err != nil {
+		return nil, nil, "", err
+	}
+	now := time.Now()
+	if sess == nil || !sess.IsActive(now) {
+		return nil, nil, "", errInvalidRefreshToken
+	}
+	u, err := h.userStore.GetByID(ctx, sess.UserID)
+	if err != nil {
+		return nil, nil, "", err
+	}
+	if u == nil {
+		return nil, nil, "", errInvalidRefreshToken
+	}
+	if sess.TokenHash != hash {
+		if now.Sub(sess.RotatedAt) > refreshReuseGrace {
+			if err := h.sessionStore.Revoke(ctx, sess.ID); err != nil {
+				return nil, nil, "", err
+			}
+			return nil, nil, "", errInvalidRefreshToken
+		}
+		if err := h.sessionStore.Touch(ctx, sess.ID, now); err != nil {
+			return nil, nil, "", err
+		}
+		return sess, u, "", nil
+	}
+	next, err := newRefreshToken()
+	if err != nil {
+		return nil, nil, "", err
+	}
+	if err := h.sessionStore.Rotate(ctx, sess.ID, hash, hashToken(next), now.Add(refreshTokenTTL)); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			// lost a race with a concurrent refresh of the same token
+			return sess, u, "", nil
+		}
+		return nil, nil, "", err
+	}
+	return sess, u, next, nil
+}
+
+// refreshFromCookie renews the browser's tokens from the refresh_token cookie
+// and sets the new cookies. Used by RequireAuth when the access token expired.
+func (h *Handler) refreshFromCookie(w http.ResponseWriter, r *http.Request) (*models.Session, *models.User, error) {
+	c, err := r.Cookie("refresh_token")
+	if err != nil {
+		return nil, nil, errInvalidRefreshToken
+	}
+	sess, u, next, err := h.rotateSession(r.Context(), c.Value)
+	if err != nil {
+		if errors.Is(err, errInvalidRefreshToken) {
+			h.clearAuthCookies(w)
+		}
+		return nil, nil, err
+	}
+	access, err := h.generateToken(u, sess.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+	h.setJWTCookie(w, access)
+	if next != "" {
+		h.setRefreshCookie(w, next)
+	}
+	return sess, u, nil
+}
+
+// RefreshToken handles POST /token/refresh. The refresh token is read from the
+// JSON body {refresh_token} or from the refresh_token cookie. It is rotated:
+// the response carries a new access token and a new refresh token, and the old
+// refresh token stops working.
+func (h *Handler) RefreshToken(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, codeBadRequest, "failed to read body")
+		return
+	}
+	defer r.Body.Close()
+	var req refreshRequest
+	if len(body) > 0 {
+		if err := json.Unmarshal(body, &req); err != nil {
+			writeError(w, http.StatusBadRequest, codeInvalidJSON, "invalid json: "+err.Error())
+			return
+		}
+	}
+	fromCookie := false
+	if req.RefreshToken == "" {
+		if c, err := r.Cookie("refresh_token"); err == nil {
+			req.RefreshToken, fromCookie = c.Value, true
+		}
+	}
+	if req.RefreshToken == "" {
+		writeInvalid(w, "refresh_token is required", map[string]string{"refresh_token": "is required"})
+		return
+	}
+	sess, u, next, err := h.rotateSession(r.Context(), req.RefreshToken)
+	if errors.Is(err, errInvalidRefreshToken) {
+		if fromCookie {
+			h.clearAuthCookies(w)
+		}
+		writeError(w, http.StatusUnauthorized, codeUnauthorized, err.Error())
+		return
+	}
+	if err != nil {
+		writeStoreError(w, err)
+		return
+	}
+	access, err := h.generateToken(u, sess.ID)
+	if err != nil {
+		writeInternal(w, err)
+		return
+	}
+	if fromCookie {
+		h.setJWTCookie(w, access)
+		if next != "" {
+			h.setRefreshCookie(w, next)
+		}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(newTokenResponse(access, next))
+}
+
+// ListSessions handles GET /sessions: the current user's active sessions,
+// with "current" set on the one making the request.
+func (h *Handler) ListSessions(w http.ResponseWriter, r *http.Request) {
+	uid, ok := GetUserIDFromContext(r.Context())
+	if !ok {
+		writeError(w, http.StatusUnauthorized, codeUnauthorized, "unauthorized")
+		return
+	}
+	sid, _ := GetSessionIDFromContext(r.Context())
+	sessions, err := h.sessionStore.ListByUser(r.Context(), uid)
+	if err != nil {
+		writeStoreError(w, err)
+		return
+	}
+	out := make([]sessionOut, 0, len(sessions))
+	for _, s := range sessions {
+		out = append(out, sessionOut{Session: s, Current: s.ID == sid})
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(out)
+}
+
+// RevokeSession handles POST /sessions/revoke?id=. Users may only revoke their
+// own sessions; revoking the current one also clears the auth cookies.
+func (h *Handler) RevokeSession(w http.ResponseWriter, r *http.Request) {
+	if !h.validateCSRF(r) {
+		writeError(w, http.StatusForbidden, codeInvalidCSRF, "invalid csrf token")
+		return
+	}
+	uid, ok := GetUserIDFromContext(r.Context())
+	if !ok {
+		writeError(w, http.StatusUnauthorized, codeUnauthorized, "unauthorized")
+		return
+	}
+	id := param(r, "id")
+	if id == "" {
+		writeInvalid(w, "id is required", map[string]string{"id": "is required"})
+		return
+	}
+	sess, err := h.sessionStore.Get(r.Context(), id)
+	if err != nil {
+		writeStoreError(w, err)
+		return
+	}
+	if sess == nil || sess.UserID != uid {
+		writeError(w, http.StatusNotFound, codeNotFound, "session not found")
+		return
+	}
+	if err := h.sessionStore.Revoke(r.Context(), id); err != nil {
+		writeStoreError(w, err)
+		return
+	}
+	if sid, _ := GetSessionIDFromContext(r.Context()); sid == id {
+		h.clearAuthCookies(w)
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// RevokeAllSessions handles POST /sessions/revoke_all ("log out everywhere"),
+// including the current session.
+func (h *Handler) RevokeAllSessions(w http.ResponseWriter, r *http.Request) {
+	if !h.validateCSRF(r) {
+		writeError(w, http.StatusForbidden, codeInvalidCSRF, "invalid csrf token")
+		return
+	}
+	uid, ok := GetUserIDFromContext(r.Context())
+	if !ok {
+		writeError(w, http.StatusUnauthorized, codeUnauthorized, "unauthorized")
+		return
+	}
+	n, err := h.sessionStore.RevokeAllByUser(r.Context(), uid)
+	if err != nil {
+		writeStoreError(w, err)
+		return
+	}
+	h.clearAuthCookies(w)
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(revokedResponse{Revoked: n})
+}
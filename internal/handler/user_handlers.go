@@ -1,11 +1,17 @@
 package handler
 
 import (
+	"context"
+	"database/sql"
 	"encoding/json"
+	"errors"
 	"io"
+	"log/slog"
 	"net/http"
 	"time"
 
+	"votacao/internal/router"
+	"votacao/internal/store"
 	"votacao/models"
 
 	"golang.org/x/crypto/bcrypt"
@@ -14,70 +20,34 @@ import (
 // Register accepts POST /register with JSON {nickname, email, password, bio?}
 // It hashes the password and creates a new user.
 func (h *Handler) Register(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodPost {
-		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
-		return
-	}
 	body, err := io.ReadAll(r.Body)
 	if err != nil {
-		http.Error(w, "failed to read body", http.StatusBadRequest)
+		writeError(w, http.StatusBadRequest, codeBadRequest, "failed to read body")
 		return
 	}
 	defer r.Body.Close()
-	var req struct {
-		Nickname string  `json:"nickname"`
-		Email    string  `json:"email"`
-		Password string  `json:"password"`
-		Bio      *string `json:"bio,omitempty"`
-	}
+	var req registerRequest
 	if err := json.Unmarshal(body, &req); err != nil {
-		http.Error(w, "invalid json: "+err.Error(), http.StatusBadRequest)
+		writeError(w, http.StatusBadRequest, codeInvalidJSON, "invalid json: "+err.Error())
 		return
 	}
 	if req.Nickname == "" || req.Email == "" || req.Password == "" {
-		http.Error(w, "nickname, email and password are required", http.StatusBadRequest)
-		return
-	}
-	// hash password
-	hash, err := bcrypt.GenerateFromPassword([]byte(req.Password), 12)
-	if err != nil {
-		http.Error(w, "failed to hash password", http.StatusInternalServerError)
+		writeInvalid(w, "nickname, email and password are required", map[string]string{"nickname": "is required", "email": "is required", "password": "is required"})
 		return
 	}
-	u := &models.User{
-		Nickname:     req.Nickname,
-		Email:        req.Email,
-		Bio:          req.Bio,
-		PasswordHash: string(hash),
-		CreatedAt:    time.Now(),
-	}
-	id, err := h.userStore.Insert(u)
+	u, err := h.registerUser(r.Context(), req)
 	if err != nil {
-		http.Error(w, "db error: "+err.Error(), http.StatusInternalServerError)
+		writeInsertError(w, "email", err)
 		return
 	}
-	u.ID = id
 	// respond with limited user info
-	out := struct {
-		ID        string    `json:"id"`
-		Nickname  string    `json:"nickname"`
-		Email     string    `json:"email"`
-		Bio       *string   `json:"bio,omitempty"`
-		CreatedAt time.Time `json:"created_at"`
-	}{ID: u.ID, Nickname: u.Nickname, Email: u.Email, Bio: u.Bio, CreatedAt: u.CreatedAt}
+	out := userOut{ID: u.ID, Nickname: u.Nickname, Email: u.Email, Bio: u.Bio, CreatedAt: u.CreatedAt}
 
-	// Optionally set JWT cookie on successful registration so user is logged in
-	tok, _ := h.generateToken(u)
-	cookie := &http.Cookie{
-		Name:     "jwt",
-		Value:    tok,
-		Path:     "/",
-		HttpOnly: true,
-		SameSite: http.SameSiteLaxMode,
-		Secure:   cookieSecure(),
-		Expires:  time.Now().Add(24 * time.Hour),
-	}
-	http.SetCookie(w, cookie)
+	// start a session on successful registration so the user is logged in
+	if _, err := h.startSession(w, r, u); err != nil {
+		writeInternal(w, err)
+		return
+	}
 	// ensure csrf cookie for double-submit pattern
 	h.ensureCSRFCookie(w, r)
 	w.Header().Set("Content-Type", "application/json")
@@ -85,134 +55,224 @@ func (h *Handler) Register(w http.ResponseWriter, r *http.Request) {
 	_ = json.NewEncoder(w).Encode(out)
 }
 
-// Login accepts POST /login with JSON {email, password} and returns {token: "..."}
+// Login accepts POST /login with JSON {email, password}. It starts a session,
+// sets the jwt and refresh_token cookies and returns the tokens for API clients.
 func (h *Handler) Login(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodPost {
-		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
-		return
-	}
 	body, err := io.ReadAll(r.Body)
 	if err != nil {
-		http.Error(w, "failed to read body", http.StatusBadRequest)
+		writeError(w, http.StatusBadRequest, codeBadRequest, "failed to read body")
 		return
 	}
 	defer r.Body.Close()
-	var req struct {
-		Email    string `json:"email"`
-		Password string `json:"password"`
-	}
+	var req loginRequest
 	if err := json.Unmarshal(body, &req); err != nil {
-		http.Error(w, "invalid json: "+err.Error(), http.StatusBadRequest)
+		writeError(w, http.StatusBadRequest, codeInvalidJSON, "invalid json: "+err.Error())
 		return
 	}
 	if req.Email == "" || req.Password == "" {
-		http.Error(w, "email and password are required", http.StatusBadRequest)
+		writeInvalid(w, "email and password are required", map[string]string{"email": "is required", "password": "is required"})
 		return
 	}
-	u, err := h.userStore.GetByEmail(req.Email)
+	u, err := h.checkCredentials(r.Context(), req.Email, req.Password)
 	if err != nil {
-		http.Error(w, "db error: "+err.Error(), http.StatusInternalServerError)
-		return
-	}
-	if u == nil {
-		http.Error(w, "invalid credentials", http.StatusUnauthorized)
-		return
-	}
-	if err := bcrypt.CompareHashAndPassword([]byte(u.PasswordHash), []byte(req.Password)); err != nil {
-		http.Error(w, "invalid credentials", http.StatusUnauthorized)
+		writeErr(w, err)
 		return
 	}
-	tok, err := h.generateToken(u)
+	tokens, err := h.startSession(w, r, u)
 	if err != nil {
-		http.Error(w, "failed to generate token: "+err.Error(), http.StatusInternalServerError)
+		writeInternal(w, err)
 		return
 	}
-	// set HttpOnly cookie with JWT
-	cookie := &http.Cookie{
-		Name:     "jwt",
-		Value:    tok,
-		Path:     "/",
-		HttpOnly: true,
-		SameSite: http.SameSiteLaxMode,
-		Secure:   cookieSecure(),
-		Expires:  time.Now().Add(24 * time.Hour),
-	}
-	http.SetCookie(w, cookie)
 	// ensure csrf cookie for double-submit pattern
 	h.ensureCSRFCookie(w, r)
 	w.Header().Set("Content-Type", "application/json")
-	_ = json.NewEncoder(w).Encode(map[string]string{"status": "ok"})
+	_ = json.NewEncoder(w).Encode(loginResponse{Status: "ok", tokenResponse: tokens})
+}
+
+// registerUser creates a user with a hashed password. Insert errors are
+// returned as is, so callers can report a taken email.
+func (h *Handler) registerUser(ctx context.Context, req registerRequest) (*models.User, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(req.Password), 12)
+	if err != nil {
+		return nil, err
+	}
+	u := &models.User{
+		Nickname:     req.Nickname,
+		Email:        req.Email,
+		Bio:          req.Bio,
+		PasswordHash: string(hash),
+		CreatedAt:    time.Now(),
+	}
+	id, err := h.userStore.Insert(ctx, u)
+	if err != nil {
+		return nil, err
+	}
+	u.ID = id
+	registrationsTotal.Inc()
+	slog.InfoContext(ctx, "user registered", "user_id", id)
+	return u, nil
+}
+
+// checkCredentials returns the user with the email and password, or a 401
+// invalid credentials error.
+func (h *Handler) checkCredentials(ctx context.Context, email, password string) (*models.User, error) {
+	u, err := h.userStore.GetByEmail(ctx, email)
+	if err != nil {
+		return nil, err
+	}
+	if u == nil {
+		loginsTotal.Inc("failure")
+		slog.WarnContext(ctx, "login failed", "reason", "unknown email")
+		return nil, &statusError{http.StatusUnauthorized, codeUnauthorized, "invalid credentials"}
+	}
+	if err := bcrypt.CompareHashAndPassword([]byte(u.PasswordHash), []byte(password)); err != nil {
+		loginsTotal.Inc("failure")
+		slog.WarnContext(ctx, "login failed", "reason", "wrong password", "user_id", u.ID)
+		return nil, &statusError{http.StatusUnauthorized, codeUnauthorized, "invalid credentials"}
+	}
+	loginsTotal.Inc("success")
+	slog.InfoContext(ctx, "login", "user_id", u.ID)
+	return u, nil
 }
 
-// Logout clears the auth cookie and redirects to the login page
+// Logout revokes the browser's session, clears the auth cookies and
+// redirects to the login page. It is a POST with the CSRF token, so another
+// site cannot end the session.
 func (h *Handler) Logout(w http.ResponseWriter, r *http.Request) {
-	cookie := &http.Cookie{
-		Name:     "jwt",
-		Value:    "",
-		Path:     "/",
-		HttpOnly: true,
-		Expires:  time.Unix(0, 0),
-		MaxAge:   -1,
-		Secure:   cookieSecure(),
-	}
-	http.SetCookie(w, cookie)
+	if !h.validateCSRF(r) {
+		writeError(w, http.StatusForbidden, codeInvalidCSRF, "invalid csrf token")
+		return
+	}
+	if c, err := r.Cookie("refresh_token"); err == nil && c.Value != "" {
+		if sess, err := h.sessionStore.GetByTokenHash(r.Context(), hashToken(c.Value)); err == nil && sess != nil {
+			_ = h.sessionStore.Revoke(r.Context(), sess.ID)
+		}
+	}
+	h.clearAuthCookies(w)
 	http.Redirect(w, r, "/login/new", http.StatusSeeOther)
 }
 
 // Me returns current user info; requires authentication via RequireAuth middleware.
 func (h *Handler) Me(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodGet {
-		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
-		return
-	}
 	uid, ok := GetUserIDFromContext(r.Context())
 	if !ok {
-		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		writeError(w, http.StatusUnauthorized, codeUnauthorized, "unauthorized")
 		return
 	}
-	u, err := h.userStore.GetByID(uid)
+	u, err := h.userStore.GetByID(r.Context(), uid)
 	if err != nil {
-		http.Error(w, "db error: "+err.Error(), http.StatusInternalServerError)
+		writeStoreError(w, err)
 		return
 	}
 	if u == nil {
-		http.Error(w, "user not found", http.StatusNotFound)
+		writeError(w, http.StatusNotFound, codeNotFound, "user not found")
 		return
 	}
-	out := struct {
-		ID        string    `json:"id"`
-		Nickname  string    `json:"nickname"`
-		Email     string    `json:"email"`
-		Bio       *string   `json:"bio,omitempty"`
-		CreatedAt time.Time `json:"created_at"`
-	}{ID: u.ID, Nickname: u.Nickname, Email: u.Email, Bio: u.Bio, CreatedAt: u.CreatedAt}
+	out := meOut{ID: u.ID, Nickname: u.Nickname, Email: u.Email, Bio: u.Bio, Role: u.Role, CreatedAt: u.CreatedAt}
 	w.Header().Set("Content-Type", "application/json")
 	_ = json.NewEncoder(w).Encode(out)
 }
 
-// ListUsers returns a list of users (public).
+// ListUsers handles GET /users[?nickname=<prefix>&limit=&cursor=] (public)
+// and returns one page of users as {items, next_cursor}.
 func (h *Handler) ListUsers(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodGet {
-		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	p, ok := pageParams(w, r)
+	if !ok {
 		return
 	}
-	us, err := h.userStore.List()
+	us, next, err := h.userStore.ListPage(r.Context(), store.UserFilter{NicknamePrefix: r.URL.Query().Get("nickname")}, p)
 	if err != nil {
-		http.Error(w, "db error: "+err.Error(), http.StatusInternalServerError)
+		writeStoreError(w, err)
 		return
 	}
-	out := make([]struct {
-		ID       string `json:"id"`
-		Nickname string `json:"nickname"`
-		Email    string `json:"email"`
-	}, 0, len(us))
+	out := make([]userSummary, 0, len(us))
 	for _, u := range us {
-		out = append(out, struct {
-			ID       string `json:"id"`
-			Nickname string `json:"nickname"`
-			Email    string `json:"email"`
-		}{ID: u.ID, Nickname: u.Nickname, Email: u.Email})
+		out = append(out, userSummary{ID: u.ID, Nickname: u.Nickname, Email: u.Email})
+	}
+	writePage(w, out, next)
+}
+
+// BootstrapAdmin handles POST /admin/bootstrap. It promotes the authenticated
+// user to admin, but only while no admin exists yet, so a fresh deployment can
+// get its first admin without touching the database. When ADMIN_BOOTSTRAP_TOKEN
+// is configured the request must also carry it in the X-Bootstrap-Token header.
+func (h *Handler) BootstrapAdmin(w http.ResponseWriter, r *http.Request) {
+	if !h.validateCSRF(r) {
+		writeError(w, http.StatusForbidden, codeInvalidCSRF, "invalid csrf token")
+		return
+	}
+	if tok := h.settings.BootstrapToken; tok != "" && r.Header.Get("X-Bootstrap-Token") != tok {
+		writeError(w, http.StatusForbidden, codeForbidden, "invalid bootstrap token")
+		return
+	}
+	uid, ok := GetUserIDFromContext(r.Context())
+	if !ok || uid == "" {
+		writeError(w, http.StatusUnauthorized, codeUnauthorized, "unauthorized")
+		return
+	}
+	promoted, err := h.userStore.PromoteFirstAdmin(r.Context(), uid)
+	if err != nil {
+		writeStoreError(w, err)
+		return
+	}
+	if !promoted {
+		writeError(w, http.StatusConflict, codeConflict, "an admin already exists")
+		return
+	}
+	u, err := h.userStore.GetByID(r.Context(), uid)
+	if err != nil {
+		writeStoreError(w, err)
+		return
+	}
+	if u == nil {
+		writeError(w, http.StatusNotFound, codeNotFound, "user not found")
+		return
+	}
+	// reissue the token so the role claim reflects the promotion
+	sid, _ := GetSessionIDFromContext(r.Context())
+	tok, err := h.generateToken(u, sid)
+	if err != nil {
+		writeInternal(w, err)
+		return
 	}
+	h.setJWTCookie(w, tok)
 	w.Header().Set("Content-Type", "application/json")
-	_ = json.NewEncoder(w).Encode(out)
+	_ = json.NewEncoder(w).Encode(roleResponse{ID: u.ID, Role: u.Role})
+}
+
+// SetUserRole handles POST /admin/users/role with JSON {user_id, role}.
+// Admin only; role must be "user" or "admin".
+func (h *Handler) SetUserRole(w http.ResponseWriter, r *http.Request) {
+	if !h.validateCSRF(r) {
+		writeError(w, http.StatusForbidden, codeInvalidCSRF, "invalid csrf token")
+		return
+	}
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, codeBadRequest, "failed to read body")
+		return
+	}
+	defer r.Body.Close()
+	var req setRoleRequest
+	if err := json.Unmarshal(body, &req); err != nil {
+		writeError(w, http.StatusBadRequest, codeInvalidJSON, "invalid json: "+err.Error())
+		return
+	}
+	if id := router.Param(r, "id"); id != "" {
+		req.UserID = id
+	}
+	if req.UserID == "" || (req.Role != RoleUser && req.Role != RoleAdmin) {
+		writeInvalid(w, "user_id and role (user|admin) are required", map[string]string{"user_id": "is required", "role": "must be user or admin"})
+		return
+	}
+	if err := h.userStore.SetRole(r.Context(), req.UserID, req.Role); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			writeError(w, http.StatusNotFound, codeNotFound, "user not found")
+			return
+		}
+		writeStoreError(w, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(roleResponse{ID: req.UserID, Role: req.Role})
 }
@@ -1,178 +1,591 @@
 package handler
 
 import (
+	"context"
+	"database/sql"
 	"encoding/json"
-	"html/template"
+	"errors"
 	"io"
+	"log/slog"
 	"net/http"
+	"strings"
 	"time"
 
+	"votacao/internal/events"
+	"votacao/internal/router"
+	"votacao/internal/store"
 	"votacao/models"
 )
 
 // AddVote accepts POST /add_vote and creates a vote for the authenticated user.
 // Body: { "nominated_id": <int> }
 func (h *Handler) AddVote(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodPost {
-		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	// a closed ballot is reported before anything else
+	cer, err := h.openCeremony(r.Context())
+	if err != nil {
+		writeErr(w, err)
 		return
 	}
-
-	// Voting deadline: votes allowed only until 7:00 PM (19:00) UTC-3 on March 15, 2026
-	// After that time, voting is closed.
-	loc, err := time.LoadLocation("America/Sao_Paulo")
+	uid, ok := GetUserIDFromContext(r.Context())
+	if !ok || uid == "" {
+		writeError(w, http.StatusUnauthorized, codeUnauthorized, "unauthorized")
+		return
+	}
+	// validate CSRF token (double-submit cookie)
+	if !h.validateCSRF(r) {
+		writeError(w, http.StatusForbidden, codeInvalidCSRF, "invalid csrf token")
+		return
+	}
+	body, err := io.ReadAll(r.Body)
 	if err != nil {
-		// fallback: use fixed offset UTC-3
-		loc = time.FixedZone("UTC-3", -3*60*60)
+		writeError(w, http.StatusBadRequest, codeBadRequest, "failed to read body")
+		return
 	}
-	if time.Now().In(loc).After(VotingDeadline) {
-		http.Error(w, "voting is closed", http.StatusForbidden)
+	defer r.Body.Close()
+	var req nominatedIDRequest
+	if err := json.Unmarshal(body, &req); err != nil {
+		writeError(w, http.StatusBadRequest, codeInvalidJSON, "invalid json: "+err.Error())
 		return
 	}
-
-	uid, ok := GetUserIDFromContext(r.Context())
-	if !ok || uid == "" {
-		http.Error(w, "unauthorized", http.StatusUnauthorized)
+	if req.NominatedID == "" {
+		writeInvalid(w, "nominated_id is required", map[string]string{"nominated_id": "is required"})
+		return
+	}
+	v, created, err := h.castVote(r.Context(), cer, uid, req.NominatedID)
+	if err != nil {
+		writeErr(w, err)
 		return
 	}
+	w.Header().Set("Content-Type", "application/json")
+	if created {
+		w.WriteHeader(http.StatusCreated)
+	} else {
+		w.WriteHeader(http.StatusOK)
+	}
+	out := voteResult{Created: created, Vote: *v}
+	_ = json.NewEncoder(w).Encode(out)
+}
 
+// castVote saves the user's pick of a nominee, replacing their earlier pick
+// in its category. cer is the open ceremony, as returned by openCeremony;
+// votes are only accepted while the category is open. It reports whether the
+// vote is new.
+func (h *Handler) castVote(ctx context.Context, cer *models.Ceremony, uid, nominatedID string) (*models.Vote, bool, error) {
 	// ensure the user exists (DB may have been reset)
 	if h.userStore != nil {
-		if u, err := h.userStore.GetByID(uid); err != nil {
-			http.Error(w, "db error: "+err.Error(), http.StatusInternalServerError)
-			return
+		if u, err := h.userStore.GetByID(ctx, uid); err != nil {
+			return nil, false, err
 		} else if u == nil {
-			http.Error(w, "unauthorized: user not found", http.StatusUnauthorized)
-			return
+			return nil, false, &statusError{http.StatusUnauthorized, codeUnauthorized, "unauthorized: user not found"}
 		}
 	}
-	// validate CSRF token (double-submit cookie)
+	nom, err := h.nominatedStore.Get(ctx, nominatedID)
+	if err != nil {
+		return nil, false, err
+	}
+	if nom == nil {
+		return nil, false, &statusError{http.StatusBadRequest, codeBadRequest, "nominated not found"}
+	}
+	cat, err := h.categoryStore.Get(ctx, nom.CategoryID)
+	if err != nil {
+		return nil, false, err
+	}
+	if cat == nil || cat.CeremonyID != cer.ID {
+		return nil, false, &statusError{http.StatusBadRequest, codeBadRequest, "nominated is not part of the active ceremony"}
+	}
+	if cat.IsLocked(time.Now()) {
+		return nil, false, &statusError{http.StatusForbidden, codeVotingClosed, "voting is closed for this category"}
+	}
+	v := &models.Vote{UserID: uid, NominatedID: nominatedID, CategoryID: nom.CategoryID}
+	id, action, err := h.voteStore.Insert(ctx, v)
+	if err != nil {
+		return nil, false, err
+	}
+	v.ID = id
+	countVote(action)
+	slog.InfoContext(ctx, "vote saved", "vote_id", id, "category_id", v.CategoryID, "nominated_id", v.NominatedID, "action", action)
+	return v, action == models.VoteCreated, nil
+}
+
+// countVote adds a saved vote to votes_total; re-submitting the same pick
+// is not counted, so both vote paths agree.
+func countVote(action string) {
+	if action != store.VoteUnchanged {
+		votesTotal.Inc(action)
+	}
+}
+
+// SubmitBallot handles POST /ballot with { "votes": { "<category_id>":
+// "<nominated_id>", ... } } and saves all the picks in one transaction, so a
+// ballot is never saved halfway. Every pick is checked first; if any is
+// invalid or its category locked, nothing is saved.
+func (h *Handler) SubmitBallot(w http.ResponseWriter, r *http.Request) {
+	cer, err := h.openCeremony(r.Context())
+	if err != nil {
+		writeErr(w, err)
+		return
+	}
+	uid, ok := GetUserIDFromContext(r.Context())
+	if !ok || uid == "" {
+		writeError(w, http.StatusUnauthorized, codeUnauthorized, "unauthorized")
+		return
+	}
 	if !h.validateCSRF(r) {
-		http.Error(w, "invalid csrf token", http.StatusForbidden)
+		writeError(w, http.StatusForbidden, codeInvalidCSRF, "invalid csrf token")
 		return
 	}
 	body, err := io.ReadAll(r.Body)
 	if err != nil {
-		http.Error(w, "failed to read body", http.StatusBadRequest)
+		writeError(w, http.StatusBadRequest, codeBadRequest, "failed to read body")
 		return
 	}
 	defer r.Body.Close()
-	var req struct {
-		NominatedID string `json:"nominated_id"`
-	}
+	var req ballotRequest
 	if err := json.Unmarshal(body, &req); err != nil {
-		http.Error(w, "invalid json: "+err.Error(), http.StatusBadRequest)
+		writeError(w, http.StatusBadRequest, codeInvalidJSON, "invalid json: "+err.Error())
 		return
 	}
-	if req.NominatedID == "" {
-		http.Error(w, "nominated_id is required", http.StatusBadRequest)
+	if len(req.Votes) == 0 {
+		writeInvalid(w, "votes is required", map[string]string{"votes": "is required"})
+		return
+	}
+	if u, err := h.userStore.GetByID(r.Context(), uid); err != nil {
+		writeStoreError(w, err)
+		return
+	} else if u == nil {
+		writeError(w, http.StatusUnauthorized, codeUnauthorized, "unauthorized: user not found")
 		return
 	}
-	nom, err := h.nominatedStore.Get(req.NominatedID)
+	b, err := h.loadBallot(r.Context(), cer.ID)
 	if err != nil {
-		http.Error(w, "db error: "+err.Error(), http.StatusInternalServerError)
+		writeStoreError(w, err)
 		return
 	}
-	if nom == nil {
-		http.Error(w, "nominated not found", http.StatusBadRequest)
+
+	// votes follow the ceremony's category order
+	now := time.Now()
+	fields := make(map[string]string)
+	var locked []string
+	var votes []models.Vote
+	known := make(map[string]bool, len(b.categories))
+	for _, c := range b.categories {
+		known[c.ID] = true
+		nomID, ok := req.Votes[c.ID]
+		if !ok {
+			continue
+		}
+		if n, found := b.nominees[nomID]; !found || n.CategoryID != c.ID {
+			fields[c.ID] = "is not a nominee of this category"
+		} else if c.IsLocked(now) {
+			locked = append(locked, c.Name)
+		}
+		votes = append(votes, models.Vote{UserID: uid, NominatedID: nomID, CategoryID: c.ID})
+	}
+	for catID := range req.Votes {
+		if !known[catID] {
+			fields[catID] = "is not a category of the active ceremony"
+		}
+	}
+	if len(fields) > 0 {
+		writeInvalid(w, "the ballot has invalid votes", fields)
 		return
 	}
-	v := &models.Vote{UserID: uid, NominatedID: req.NominatedID, CategoryID: nom.CategoryID}
-	id, created, err := h.voteStore.Insert(v)
+	if len(locked) > 0 {
+		writeError(w, http.StatusForbidden, codeVotingClosed, "voting is closed for "+strings.Join(locked, ", "))
+		return
+	}
+
+	actions, err := h.voteStore.InsertBallot(r.Context(), votes)
 	if err != nil {
-		http.Error(w, "db error: "+err.Error(), http.StatusInternalServerError)
+		writeStoreError(w, err)
 		return
 	}
-	v.ID = id
+	out := ballotResult{Votes: make([]ballotVote, len(votes))}
+	for i, v := range votes {
+		out.Votes[i] = ballotVote{CategoryID: v.CategoryID, NominatedID: v.NominatedID, VoteID: v.ID, Status: actions[i]}
+		countVote(actions[i])
+	}
+	slog.InfoContext(r.Context(), "ballot saved", "user_id", uid, "votes", len(votes))
 	w.Header().Set("Content-Type", "application/json")
-	if created {
-		w.WriteHeader(http.StatusCreated)
-	} else {
-		w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(out)
+}
+
+// GetBallotStatus handles GET /ballot/status and returns every category of
+// the active (or ?ceremony_id=) ceremony in sequence order with the user's
+// pick, the categories still missing one, the completion percentage and the
+// time left to vote.
+func (h *Handler) GetBallotStatus(w http.ResponseWriter, r *http.Request) {
+	uid, ok := GetUserIDFromContext(r.Context())
+	if !ok || uid == "" {
+		writeError(w, http.StatusUnauthorized, codeUnauthorized, "unauthorized")
+		return
+	}
+	cer, ok := h.requireCeremony(w, r)
+	if !ok {
+		return
+	}
+	b, err := h.loadBallot(r.Context(), cer.ID)
+	if err != nil {
+		writeStoreError(w, err)
+		return
+	}
+	picks, err := h.userPicks(r.Context(), uid, cer.ID)
+	if err != nil {
+		writeStoreError(w, err)
+		return
+	}
+
+	loc := h.location()
+	now := time.Now()
+	out := ballotStatus{
+		CeremonyID: cer.ID,
+		Deadline:   cer.Deadline.In(loc).Format(time.RFC3339),
+		Closed:     cer.Status != models.CeremonyActive || now.After(cer.Deadline),
+		Total:      len(b.categories),
+		Categories: make([]ballotCategory, 0, len(b.categories)),
+		Missing:    make([]string, 0),
+	}
+	if !out.Closed {
+		out.TimeLeftSeconds = int64(cer.Deadline.Sub(now) / time.Second)
 	}
-	out := struct {
-		Created bool        `json:"created"`
-		Vote    models.Vote `json:"vote"`
-	}{Created: created, Vote: *v}
+	for _, c := range b.categories {
+		bc := ballotCategory{ID: c.ID, Name: c.Name, SequenceOrder: c.SequenceOrder, Locked: out.Closed || c.IsLocked(now)}
+		if n, ok := b.nominees[picks[c.ID]]; ok {
+			bc.Pick = b.pick(n)
+			out.Picked++
+		} else {
+			out.Missing = append(out.Missing, c.ID)
+		}
+		out.Categories = append(out.Categories, bc)
+	}
+	out.Percent = percent(out.Picked, out.Total)
+	w.Header().Set("Content-Type", "application/json")
 	_ = json.NewEncoder(w).Encode(out)
 }
 
-// ListVotes returns votes for the authenticated user.
+// GetScorecard handles GET /scorecard and returns the user's score in the
+// active (or ?ceremony_id=) ceremony category by category.
+func (h *Handler) GetScorecard(w http.ResponseWriter, r *http.Request) {
+	uid, ok := GetUserIDFromContext(r.Context())
+	if !ok || uid == "" {
+		writeError(w, http.StatusUnauthorized, codeUnauthorized, "unauthorized")
+		return
+	}
+	cer, ok := h.requireCeremony(w, r)
+	if !ok {
+		return
+	}
+	h.writeScorecard(w, r, uid, cer)
+}
+
+// GetUserScorecard handles GET /users/{id}/scorecard. Other users' picks stay
+// hidden until voting closes, except from admins.
+func (h *Handler) GetUserScorecard(w http.ResponseWriter, r *http.Request) {
+	uid := router.Param(r, "id")
+	cer, ok := h.requireCeremony(w, r)
+	if !ok {
+		return
+	}
+	// authorize before looking the user up, so a 404 reveals nothing while
+	// picks are hidden
+	caller, _ := GetUserIDFromContext(r.Context())
+	role, _ := GetRoleFromContext(r.Context())
+	open := cer.Status == models.CeremonyActive && !time.Now().After(cer.Deadline)
+	if open && caller != uid && role != RoleAdmin {
+		writeError(w, http.StatusForbidden, codeForbidden, "picks are hidden until voting closes")
+		return
+	}
+	u, err := h.userStore.GetByID(r.Context(), uid)
+	if err != nil {
+		writeStoreError(w, err)
+		return
+	}
+	if u == nil {
+		writeError(w, http.StatusNotFound, codeNotFound, "user not found")
+		return
+	}
+	h.writeScorecard(w, r, uid, cer)
+}
+
+// writeScorecard writes every category of cer in sequence order with the
+// user's pick, the winners and what the pick earned. The points come from
+// VoteStore.ScoredVotes so they always agree with GET /score.
+func (h *Handler) writeScorecard(w http.ResponseWriter, r *http.Request, userID string, cer *models.Ceremony) {
+	b, err := h.loadBallot(r.Context(), cer.ID)
+	if err != nil {
+		writeStoreError(w, err)
+		return
+	}
+	scored, err := h.voteStore.ScoredVotes(r.Context(), userID, cer.ID)
+	if err != nil {
+		writeStoreError(w, err)
+		return
+	}
+	winners, err := h.winnerStore.List(r.Context(), cer.ID)
+	if err != nil {
+		writeStoreError(w, err)
+		return
+	}
+	byCategory := make(map[string]store.ScoredVote, len(scored))
+	for _, sv := range scored {
+		byCategory[sv.CategoryID] = sv
+	}
+	won := make(map[string][]ballotPick)
+	for _, wn := range winners {
+		if n, ok := b.nominees[wn.NominatedID]; ok {
+			won[n.CategoryID] = append(won[n.CategoryID], *b.pick(n))
+		}
+	}
+
+	out := scorecard{UserID: userID, CeremonyID: cer.ID, Categories: make([]scorecardCategory, 0, len(b.categories))}
+	for _, c := range b.categories {
+		sc := scorecardCategory{ID: c.ID, Name: c.Name, Weight: c.Points, Winners: won[c.ID], Pending: len(won[c.ID]) == 0}
+		if sc.Winners == nil {
+			sc.Winners = []ballotPick{}
+		}
+		if sv, ok := byCategory[c.ID]; ok {
+			sc.Weight, sc.Correct, sc.Points = sv.Weight, sv.Correct, sv.Points
+			if n, ok := b.nominees[sv.NominatedID]; ok {
+				sc.Pick = b.pick(n)
+			}
+			out.Points += sv.Points
+			out.MaxPoints += sv.Weight
+		}
+		out.Categories = append(out.Categories, sc)
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(out)
+}
+
+// openCeremony returns the active ceremony, or a voting_closed error when
+// there is none or its deadline has passed.
+func (h *Handler) openCeremony(ctx context.Context) (*models.Ceremony, error) {
+	cer, err := h.ceremonyStore.GetActive(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if cer == nil || time.Now().After(cer.Deadline) {
+		return nil, &statusError{http.StatusForbidden, codeVotingClosed, "voting is closed"}
+	}
+	return cer, nil
+}
+
+// ListVotes handles GET /votes[?category_id=&limit=&cursor=] and returns one
+// page of the authenticated user's votes in the active (or ?ceremony_id=)
+// ceremony as {items, next_cursor}.
 func (h *Handler) ListVotes(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodGet {
-		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	uid, ok := GetUserIDFromContext(r.Context())
+	if !ok || uid == "" {
+		writeError(w, http.StatusUnauthorized, codeUnauthorized, "unauthorized")
+		return
+	}
+	cer, ok := h.requireCeremony(w, r)
+	if !ok {
+		return
+	}
+	p, ok := pageParams(w, r)
+	if !ok {
+		return
+	}
+	out, next, err := h.voteStore.ListPage(r.Context(), store.VoteFilter{UserID: uid, CeremonyID: cer.ID, CategoryID: r.URL.Query().Get("category_id")}, p)
+	if err != nil {
+		writeStoreError(w, err)
 		return
 	}
+	writePage(w, out, next)
+}
+
+// GetVoteHistory handles GET /votes/history and returns the authenticated
+// user's vote history in the active (or ?ceremony_id=) ceremony.
+func (h *Handler) GetVoteHistory(w http.ResponseWriter, r *http.Request) {
 	uid, ok := GetUserIDFromContext(r.Context())
 	if !ok || uid == "" {
-		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		writeError(w, http.StatusUnauthorized, codeUnauthorized, "unauthorized")
 		return
 	}
-	out, err := h.voteStore.ListByUser(uid)
+	h.writeVoteHistory(w, r, uid)
+}
+
+// GetUserVoteHistory handles GET /admin/votes/history?user_id=<id> (admin only).
+func (h *Handler) GetUserVoteHistory(w http.ResponseWriter, r *http.Request) {
+	uid := param(r, "user_id")
+	if uid == "" {
+		writeInvalid(w, "user_id is required", map[string]string{"user_id": "is required"})
+		return
+	}
+	u, err := h.userStore.GetByID(r.Context(), uid)
 	if err != nil {
-		http.Error(w, "db error: "+err.Error(), http.StatusInternalServerError)
+		writeStoreError(w, err)
 		return
 	}
+	if u == nil {
+		writeError(w, http.StatusNotFound, codeNotFound, "user not found")
+		return
+	}
+	h.writeVoteHistory(w, r, uid)
+}
+
+// writeVoteHistory writes { "events": [...], "changes": N, "changes_by_category": { "<category_id>": n } }
+// where changes counts the times the user replaced an earlier pick.
+func (h *Handler) writeVoteHistory(w http.ResponseWriter, r *http.Request, userID string) {
+	cer, ok := h.requireCeremony(w, r)
+	if !ok {
+		return
+	}
+	evs, err := h.voteStore.ListEvents(r.Context(), userID, cer.ID)
+	if err != nil {
+		writeStoreError(w, err)
+		return
+	}
+	changes := 0
+	byCategory := make(map[string]int)
+	for _, e := range evs {
+		if e.Action == models.VoteChanged {
+			changes++
+			byCategory[e.CategoryID]++
+		}
+	}
+	out := voteHistory{UserID: userID, Events: evs, Changes: changes, ChangesByCategory: byCategory}
 	w.Header().Set("Content-Type", "application/json")
 	_ = json.NewEncoder(w).Encode(out)
 }
 
-// VotingDeadline is the shared deadline used by AddVote and GetDeadline.
-var VotingDeadline = func() time.Time {
+// location is the timezone the deadline is displayed in.
+func (h *Handler) location() *time.Location {
+	if h.settings.Location != nil {
+		return h.settings.Location
+	}
 	loc, err := time.LoadLocation("America/Sao_Paulo")
 	if err != nil {
 		loc = time.FixedZone("UTC-3", -3*60*60)
 	}
-	return time.Date(2026, time.March, 15, 19, 0, 0, 0, loc)
-}()
+	return loc
+}
 
-// GetDeadline returns the voting deadline as JSON so the frontend can display a countdown.
-// GET /deadline -> { "deadline": "2026-03-15T00:00:00-03:00", "server_time": "...", "closed": bool }
+// GetDeadline returns the voting deadline of the active (or ?ceremony_id=) ceremony
+// and the lock state of each of its categories, so the frontend can display a
+// countdown and disable categories that were already announced.
+// GET /deadline -> { "ceremony_id": "...", "ceremony": "Oscar 2026", "deadline": "2026-03-15T19:00:00-03:00",
+// "server_time": "...", "closed": bool, "categories": [{ "id": "...", "name": "...", "locks_at": "...", "locked": bool }] }
 func (h *Handler) GetDeadline(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodGet {
-		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	cer, ok := h.requireCeremony(w, r)
+	if !ok {
 		return
 	}
-	loc, _ := time.LoadLocation("America/Sao_Paulo")
-	if loc == nil {
-		loc = time.FixedZone("UTC-3", -3*60*60)
+	categories, err := h.categoryStore.List(r.Context(), cer.ID)
+	if err != nil {
+		writeStoreError(w, err)
+		return
 	}
+	loc := h.location()
 	now := time.Now().In(loc)
-	out := struct {
-		Deadline   string `json:"deadline"`
-		ServerTime string `json:"server_time"`
-		Closed     bool   `json:"closed"`
-	}{
-		Deadline:   VotingDeadline.Format(time.RFC3339),
+	closed := cer.Status != models.CeremonyActive || now.After(cer.Deadline)
+
+	states := make([]categoryState, 0, len(categories))
+	for _, c := range categories {
+		st := categoryState{ID: c.ID, Name: c.Name, Locked: closed || c.IsLocked(now)}
+		if c.LocksAt != nil {
+			st.LocksAt = c.LocksAt.In(loc).Format(time.RFC3339)
+		}
+		states = append(states, st)
+	}
+	out := deadlineResponse{
+		CeremonyID: cer.ID,
+		Ceremony:   cer.Name,
+		Deadline:   cer.Deadline.In(loc).Format(time.RFC3339),
 		ServerTime: now.Format(time.RFC3339),
-		Closed:     now.After(VotingDeadline),
+		Closed:     closed,
+		Categories: states,
 	}
 	w.Header().Set("Content-Type", "application/json")
 	_ = json.NewEncoder(w).Encode(out)
 }
 
+// LockCategory handles POST /lock_category with JSON {id, locked?, locks_at?}.
+// Without a body beyond the id it locks the category immediately, which is what
+// admins do the moment a category is announced. Send "locked": false to reopen
+// it, and "locks_at" to schedule (or, with null, clear) an automatic lock; a
+// field left out keeps its stored value. category_locked is published only
+// when this request leaves the category locked: a scheduled lock that passes
+// later is not announced over SSE (clients see it in GET /deadline).
+func (h *Handler) LockCategory(w http.ResponseWriter, r *http.Request) {
+	if !h.validateCSRF(r) {
+		writeError(w, http.StatusForbidden, codeInvalidCSRF, "invalid csrf token")
+		return
+	}
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, codeBadRequest, "failed to read body")
+		return
+	}
+	defer r.Body.Close()
+
+	var req lockCategoryRequest
+	// the /api/v1 route names the category in the path and may send no body
+	if len(body) > 0 {
+		var fields map[string]json.RawMessage
+		if err := json.Unmarshal(body, &req); err != nil {
+			writeError(w, http.StatusBadRequest, codeInvalidJSON, "invalid json: "+err.Error())
+			return
+		}
+		_ = json.Unmarshal(body, &fields)
+		_, req.hasLocksAt = fields["locks_at"]
+	}
+	if id := router.Param(r, "id"); id != "" {
+		req.ID = id
+	}
+	if req.ID == "" {
+		writeInvalid(w, "id is required", map[string]string{"id": "is required"})
+		return
+	}
+	c, err := h.categoryStore.Get(r.Context(), req.ID)
+	if err != nil {
+		writeStoreError(w, err)
+		return
+	}
+	if c == nil {
+		writeError(w, http.StatusNotFound, codeNotFound, "category not found")
+		return
+	}
+	switch {
+	case req.Locked != nil:
+		c.Locked = *req.Locked
+	case !req.hasLocksAt:
+		c.Locked = true
+	}
+	if req.hasLocksAt {
+		c.LocksAt = req.LocksAt
+	}
+	if err := h.categoryStore.SetLock(r.Context(), c.ID, c.Locked, c.LocksAt); err != nil {
+		writeStoreError(w, err)
+		return
+	}
+	if c.IsLocked(time.Now()) {
+		h.events.Publish(events.Event{Type: events.CategoryLocked, Data: c})
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(c)
+}
+
 // GetMyScore returns the current user's score (correct votes vs total votes).
 // GET /score -> { "points": X, "max_points": Y }
-// Points: Best Picture=3, Actor/Actress in Leading Role=2, others=1
+// Each category is weighted by its points (see /update_category).
 func (h *Handler) GetMyScore(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodGet {
-		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
-		return
-	}
 	uid, ok := GetUserIDFromContext(r.Context())
 	if !ok || uid == "" {
-		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		writeError(w, http.StatusUnauthorized, codeUnauthorized, "unauthorized")
 		return
 	}
-	points, maxPoints, err := h.voteStore.GetUserScore(uid)
+	cer, ok := h.requireCeremony(w, r)
+	if !ok {
+		return
+	}
+	points, maxPoints, err := h.voteStore.GetUserScore(r.Context(), uid, cer.ID)
 	if err != nil {
-		http.Error(w, "db error: "+err.Error(), http.StatusInternalServerError)
+		writeStoreError(w, err)
 		return
 	}
-	out := struct {
-		Points    int `json:"points"`
-		MaxPoints int `json:"max_points"`
-	}{
+	out := scoreResponse{
 		Points:    points,
 		MaxPoints: maxPoints,
 	}
@@ -180,36 +593,75 @@ func (h *Handler) GetMyScore(w http.ResponseWriter, r *http.Request) {
 	_ = json.NewEncoder(w).Encode(out)
 }
 
-// GetLeaderboard returns all users' scores ordered by points.
-// GET /leaderboard -> [{ "user_id": "...", "nickname": "...", "points": X, "max_points": Y, ... }, ...]
+// GetLeaderboard returns users' scores in the active (or ?ceremony_id=) ceremony ordered by points.
+// GET /leaderboard[?nickname=<prefix>&limit=&cursor=] ->
+// {"items": [{ "user_id": "...", "nickname": "...", "points": X, "max_points": Y, ... }, ...], "next_cursor": "..."}
 func (h *Handler) GetLeaderboard(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodGet {
-		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	cer, ok := h.requireCeremony(w, r)
+	if !ok {
+		return
+	}
+	p, ok := pageParams(w, r)
+	if !ok {
 		return
 	}
-	scores, err := h.voteStore.GetAllScores()
+	f := store.ScoreFilter{CeremonyID: cer.ID, NicknamePrefix: r.URL.Query().Get("nickname")}
+	scores, next, err := h.voteStore.ScoresPage(r.Context(), f, p)
 	if err != nil {
-		http.Error(w, "db error: "+err.Error(), http.StatusInternalServerError)
+		writeStoreError(w, err)
 		return
 	}
-	w.Header().Set("Content-Type", "application/json")
-	_ = json.NewEncoder(w).Encode(scores)
+	writePage(w, scores, next)
 }
 
-// ServeLeaderboardView serves the leaderboard HTML page.
-func (h *Handler) ServeLeaderboardView(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodGet {
-		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+// RecomputeScores handles POST /admin/scores/recompute. The optional JSON body
+// { "points": { "<category_id>": n, ... } } applies a new weighting atomically;
+// the leaderboard of the active (or ?ceremony_id=) ceremony is then recomputed
+// and returned.
+func (h *Handler) RecomputeScores(w http.ResponseWriter, r *http.Request) {
+	if !h.validateCSRF(r) {
+		writeError(w, http.StatusForbidden, codeInvalidCSRF, "invalid csrf token")
 		return
 	}
-	tpl, err := template.ParseFiles("templates/leaderboard_view.html", "templates/footer.html")
+	body, err := io.ReadAll(r.Body)
 	if err != nil {
-		http.Error(w, "template error: "+err.Error(), http.StatusInternalServerError)
+		writeError(w, http.StatusBadRequest, codeBadRequest, "failed to read body")
+		return
+	}
+	defer r.Body.Close()
+
+	var req recomputeRequest
+	if len(body) > 0 {
+		if err := json.Unmarshal(body, &req); err != nil {
+			writeError(w, http.StatusBadRequest, codeInvalidJSON, "invalid json: "+err.Error())
+			return
+		}
+	}
+	for id, p := range req.Points {
+		if p < 0 {
+			writeInvalid(w, "points must be >= 0", map[string]string{"points." + id: "must be >= 0"})
+			return
+		}
+	}
+	cer, ok := h.requireCeremony(w, r)
+	if !ok {
 		return
 	}
-	w.Header().Set("Content-Type", "text/html; charset=utf-8")
-	if err := tpl.Execute(w, nil); err != nil {
-		http.Error(w, "template render error: "+err.Error(), http.StatusInternalServerError)
+	// only the resolved ceremony's categories can be reweighted
+	if err := h.categoryStore.SetPoints(r.Context(), cer.ID, req.Points); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			writeError(w, http.StatusNotFound, codeNotFound, "category not found in this ceremony")
+			return
+		}
+		writeStoreError(w, err)
 		return
 	}
+	scores, err := h.voteStore.GetAllScores(r.Context(), cer.ID)
+	if err != nil {
+		writeStoreError(w, err)
+		return
+	}
+	h.publishScores(cer.ID, scores)
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(scores)
 }
@@ -0,0 +1,295 @@
+// Package metrics is a small Prometheus client: counters, histograms and
+// gauges read at scrape time, exposed in the Prometheus text format (0.0.4).
+// Metrics are registered once, usually in package-level vars, on Default.
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// DefBuckets are latency buckets in seconds, from 5ms to 10s.
+var DefBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+
+// Default is the registry the application registers its metrics on.
+var Default = NewRegistry()
+
+// metric is one registered family.
+type metric interface {
+	name() string
+	write(w io.Writer)
+}
+
+// Registry holds metric families in registration order.
+type Registry struct {
+	mu      sync.Mutex
+	metrics []metric
+	names   map[string]bool
+}
+
+func NewRegistry() *Registry {
+	return &Registry{names: make(map[string]bool)}
+}
+
+func (r *Registry) register(m metric) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.names[m.name()] {
+		panic("metrics: duplicate metric " + m.name())
+	}
+	r.names[m.name()] = true
+	r.metrics = append(r.metrics, m)
+}
+
+// WriteText writes every family in the Prometheus text format.
+func (r *Registry) WriteText(w io.Writer) {
+	r.mu.Lock()
+	ms := append([]metric(nil), r.metrics...)
+	r.mu.Unlock()
+	for _, m := range ms {
+		m.write(w)
+	}
+}
+
+// Handler serves the registry in the Prometheus text format.
+func (r *Registry) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		r.WriteText(w)
+	})
+}
+
+// vec is the label handling shared by CounterVec and HistogramVec.
+type vec[T any] struct {
+	fullName, help string
+	labels         []string
+	newSeries      func() *T
+
+	mu     sync.Mutex
+	series map[string]*T
+	values map[string][]string
+}
+
+func (v *vec[T]) name() string { return v.fullName }
+
+// with returns the series for the label values, creating it on first use.
+func (v *vec[T]) with(values []string) *T {
+	if len(values) != len(v.labels) {
+		panic(fmt.Sprintf("metrics: %s wants %d label values, got %d", v.fullName, len(v.labels), len(values)))
+	}
+	key := strings.Join(values, "\xff")
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	s, ok := v.series[key]
+	if !ok {
+		s = v.newSeries()
+		v.series[key] = s
+		v.values[key] = append([]string(nil), values...)
+	}
+	return s
+}
+
+// each calls f for every series, ordered by label values.
+func (v *vec[T]) each(f func(labels string, s *T)) {
+	v.mu.Lock()
+	keys := make([]string, 0, len(v.series))
+	for k := range v.series {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	type entry struct {
+		labels string
+		s      *T
+	}
+	entries := make([]entry, 0, len(keys))
+	for _, k := range keys {
+		entries = append(entries, entry{labelPairs(v.labels, v.values[k]), v.series[k]})
+	}
+	v.mu.Unlock()
+	for _, e := range entries {
+		f(e.labels, e.s)
+	}
+}
+
+func newVec[T any](name, help string, labels []string, newSeries func() *T) *vec[T] {
+	return &vec[T]{fullName: name, help: help, labels: labels, newSeries: newSeries,
+		series: make(map[string]*T), values: make(map[string][]string)}
+}
+
+// CounterVec is a counter partitioned by labels; with no labels it is a
+// single counter.
+type CounterVec struct {
+	*vec[counter]
+}
+
+type counter struct {
+	mu sync.Mutex
+	v  float64
+}
+
+func (r *Registry) NewCounterVec(name, help string, labels ...string) *CounterVec {
+	c := &CounterVec{newVec(name, help, labels, func() *counter { return &counter{} })}
+	r.register(c)
+	return c
+}
+
+// Inc adds 1 to the series with the given label values.
+func (c *CounterVec) Inc(labelValues ...string) { c.Add(1, labelValues...) }
+
+// Add adds v, which must not be negative, to the series.
+func (c *CounterVec) Add(v float64, labelValues ...string) {
+	if v < 0 {
+		panic("metrics: counter " + c.fullName + " cannot decrease")
+	}
+	s := c.with(labelValues)
+	s.mu.Lock()
+	s.v += v
+	s.mu.Unlock()
+}
+
+// Value returns the current value of the series; it is meant for tests.
+func (c *CounterVec) Value(labelValues ...string) float64 {
+	s := c.with(labelValues)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.v
+}
+
+func (c *CounterVec) write(w io.Writer) {
+	header(w, c.fullName, c.help, "counter")
+	c.each(func(labels string, s *counter) {
+		s.mu.Lock()
+		v := s.v
+		s.mu.Unlock()
+		fmt.Fprintf(w, "%s%s %s\n", c.fullName, braces(labels), formatFloat(v))
+	})
+}
+
+// HistogramVec is a histogram partitioned by labels.
+type HistogramVec struct {
+	*vec[histogram]
+	buckets []float64
+}
+
+type histogram struct {
+	mu     sync.Mutex
+	counts []uint64 // per bucket, not cumulative
+	sum    float64
+	count  uint64
+}
+
+// NewHistogramVec registers a histogram with the given upper bounds, which
+// must be sorted; the +Inf bucket is implicit.
+func (r *Registry) NewHistogramVec(name, help string, buckets []float64, labels ...string) *HistogramVec {
+	if !sort.Float64sAreSorted(buckets) {
+		panic("metrics: buckets of " + name + " are not sorted")
+	}
+	h := &HistogramVec{buckets: buckets}
+	h.vec = newVec(name, help, labels, func() *histogram { return &histogram{counts: make([]uint64, len(buckets))} })
+	r.register(h)
+	return h
+}
+
+// Observe records v in the series with the given label values.
+func (h *HistogramVec) Observe(v float64, labelValues ...string) {
+	s := h.with(labelValues)
+	i := sort.SearchFloat64s(h.buckets, v)
+	s.mu.Lock()
+	if i < len(s.counts) {
+		s.counts[i]++
+	}
+	s.sum += v
+	s.count++
+	s.mu.Unlock()
+}
+
+// Count returns the number of observations of the series; it is meant for tests.
+func (h *HistogramVec) Count(labelValues ...string) uint64 {
+	s := h.with(labelValues)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.count
+}
+
+func (h *HistogramVec) write(w io.Writer) {
+	header(w, h.fullName, h.help, "histogram")
+	h.each(func(labels string, s *histogram) {
+		s.mu.Lock()
+		counts := append([]uint64(nil), s.counts...)
+		sum, count := s.sum, s.count
+		s.mu.Unlock()
+		sep := ""
+		if labels != "" {
+			sep = ","
+		}
+		var cum uint64
+		for i, b := range h.buckets {
+			cum += counts[i]
+			fmt.Fprintf(w, "%s_bucket{%s%sle=\"%s\"} %d\n", h.fullName, labels, sep, formatFloat(b), cum)
+		}
+		fmt.Fprintf(w, "%s_bucket{%s%sle=\"+Inf\"} %d\n", h.fullName, labels, sep, count)
+		fmt.Fprintf(w, "%s_sum%s %s\n", h.fullName, braces(labels), formatFloat(sum))
+		fmt.Fprintf(w, "%s_count%s %d\n", h.fullName, braces(labels), count)
+	})
+}
+
+// funcMetric is a gauge or counter whose value is read at scrape time.
+type funcMetric struct {
+	fullName, help, typ string
+	f                   func() float64
+}
+
+func (m *funcMetric) name() string { return m.fullName }
+
+func (m *funcMetric) write(w io.Writer) {
+	header(w, m.fullName, m.help, m.typ)
+	fmt.Fprintf(w, "%s %s\n", m.fullName, formatFloat(m.f()))
+}
+
+// NewGaugeFunc registers a gauge whose value is f() at scrape time.
+func (r *Registry) NewGaugeFunc(name, help string, f func() float64) {
+	r.register(&funcMetric{name, help, "gauge", f})
+}
+
+// NewCounterFunc registers a counter kept elsewhere, read with f at scrape time.
+func (r *Registry) NewCounterFunc(name, help string, f func() float64) {
+	r.register(&funcMetric{name, help, "counter", f})
+}
+
+func header(w io.Writer, name, help, typ string) {
+	help = strings.NewReplacer(`\`, `\\`, "\n", `\n`).Replace(help)
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, typ)
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func labelPairs(names, values []string) string {
+	pairs := make([]string, len(names))
+	for i, n := range names {
+		pairs[i] = n + `="` + labelEscaper.Replace(values[i]) + `"`
+	}
+	return strings.Join(pairs, ",")
+}
+
+func braces(labels string) string {
+	if labels == "" {
+		return ""
+	}
+	return "{" + labels + "}"
+}
+
+func formatFloat(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
@@ -0,0 +1,68 @@
+package metrics
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestWriteText(t *testing.T) {
+	r := NewRegistry()
+	votes := r.NewCounterVec("votes_total", "Votes saved.", "result")
+	logins := r.NewCounterVec("logins_total", "Logins.")
+	latency := r.NewHistogramVec("latency_seconds", "Request latency.", []float64{0.1, 1}, "route")
+	r.NewGaugeFunc("open_connections", "Open connections.", func() float64 { return 3 })
+
+	votes.Inc("created")
+	votes.Inc("created")
+	votes.Add(1, `cha"nged`)
+	logins.Inc()
+	latency.Observe(0.05, "/a")
+	latency.Observe(0.1, "/a")
+	latency.Observe(3, "/a")
+
+	var b strings.Builder
+	r.WriteText(&b)
+	want := `# HELP votes_total Votes saved.
+# TYPE votes_total counter
+votes_total{result="cha\"nged"} 1
+votes_total{result="created"} 2
+# HELP logins_total Logins.
+# TYPE logins_total counter
+logins_total 1
+# HELP latency_seconds Request latency.
+# TYPE latency_seconds histogram
+latency_seconds_bucket{route="/a",le="0.1"} 2
+latency_seconds_bucket{route="/a",le="1"} 2
+latency_seconds_bucket{route="/a",le="+Inf"} 3
+latency_seconds_sum{route="/a"} 3.15
+latency_seconds_count{route="/a"} 3
+# HELP open_connections Open connections.
+# TYPE open_connections gauge
+open_connections 3
+`
+	if got := b.String(); got != want {
+		t.Fatalf("got:\n%s\nwant:\n%s", got, want)
+	}
+	if votes.Value("created") != 2 || latency.Count("/a") != 3 {
+		t.Fatal("Value/Count disagree with the exposition")
+	}
+}
+
+func TestRegistryPanics(t *testing.T) {
+	r := NewRegistry()
+	c := r.NewCounterVec("c_total", "c", "a")
+	for name, f := range map[string]func(){
+		"duplicate name":     func() { r.NewCounterVec("c_total", "again") },
+		"wrong label count":  func() { c.Inc() },
+		"negative increment": func() { c.Add(-1, "x") },
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("%s: no panic", name)
+				}
+			}()
+			f()
+		}()
+	}
+}
@@ -0,0 +1,99 @@
+package openapi
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+type inner struct {
+	Name string `json:"name"`
+}
+
+type sample struct {
+	ID      string         `json:"id,omitempty"`
+	Count   int            `json:"count"`
+	When    time.Time      `json:"when"`
+	Note    *string        `json:"note,omitempty"`
+	Tags    []string       `json:"tags"`
+	Extra   map[string]int `json:"extra"`
+	Secret  string         `json:"-"`
+	Inner   inner          `json:"inner"`
+	Any     interface{}    `json:"any"`
+	private string
+	Labels  map[string]string `json:"labels,omitempty"`
+	inner
+}
+
+func TestSchemaOf(t *testing.T) {
+	d := New("t", "1")
+	d.Define("Inner", inner{})
+	s := d.SchemaOf(sample{})
+
+	if s.Type != "object" {
+		t.Fatalf("type = %q", s.Type)
+	}
+	want := []string{"any", "count", "extra", "inner", "name", "tags", "when"}
+	if !reflect.DeepEqual(s.Required, want) {
+		t.Fatalf("required = %v, want %v", s.Required, want)
+	}
+	if _, ok := s.Properties["Secret"]; ok {
+		t.Fatal(`json:"-" field in schema`)
+	}
+	if s.Properties["when"].Format != "date-time" || !s.Properties["note"].Nullable || s.Properties["tags"].Items.Type != "string" {
+		t.Fatalf("unexpected property schemas: %+v", s.Properties)
+	}
+	if s.Properties["extra"].AdditionalProperties.Type != "integer" {
+		t.Fatal("map values not described")
+	}
+	if s.Properties["inner"].Ref != "#/components/schemas/Inner" {
+		t.Fatalf("defined type not referenced: %+v", s.Properties["inner"])
+	}
+}
+
+func TestValidate(t *testing.T) {
+	d := New("t", "1")
+	ref := d.Define("Sample", sample{})
+
+	valid := `{"count": 1, "when": "2026-03-15T19:00:00Z", "tags": [], "extra": {"a": 1}, "inner": {"name": "x"}, "name": "y", "any": null, "note": null}`
+	if err := d.Validate(ref, decode(t, valid)); err != nil {
+		t.Fatalf("valid document rejected: %v", err)
+	}
+
+	for _, tc := range []struct{ doc, err string }{
+		{`{"when": "2026-03-15T19:00:00Z", "tags": [], "extra": {}, "inner": {"name": "x"}, "name": "y", "any": 1}`, `missing required property "count"`},
+		{`{"count": 1.5, "when": "2026-03-15T19:00:00Z", "tags": [], "extra": {}, "inner": {"name": "x"}, "name": "y", "any": 1}`, "want integer"},
+		{`{"count": 1, "when": "yesterday", "tags": [], "extra": {}, "inner": {"name": "x"}, "name": "y", "any": 1}`, "want date-time"},
+		{`{"count": 1, "when": "2026-03-15T19:00:00Z", "tags": null, "extra": {}, "inner": {"name": "x"}, "name": "y", "any": 1}`, "$.tags: null"},
+		{`{"count": 1, "when": "2026-03-15T19:00:00Z", "tags": [1], "extra": {}, "inner": {"name": "x"}, "name": "y", "any": 1}`, "$.tags[0]: want string"},
+		{`{"count": 1, "when": "2026-03-15T19:00:00Z", "tags": [], "extra": {}, "inner": {}, "name": "y", "any": 1}`, `$.inner: missing required property "name"`},
+		{`{"count": 1, "when": "2026-03-15T19:00:00Z", "tags": [], "extra": {}, "inner": {"name": "x"}, "name": "y", "any": 1, "new": true}`, `property "new" is not in the spec`},
+	} {
+		err := d.Validate(ref, decode(t, tc.doc))
+		if err == nil || !strings.Contains(err.Error(), tc.err) {
+			t.Errorf("Validate(%s) = %v, want error containing %q", tc.doc, err, tc.err)
+		}
+	}
+}
+
+func TestValidateOneOf(t *testing.T) {
+	d := New("t", "1")
+	s := &Schema{OneOf: []*Schema{ArrayOf(&Schema{Type: "string"}), {Type: "object"}}}
+	if err := d.Validate(s, decode(t, `["a"]`)); err != nil {
+		t.Fatal(err)
+	}
+	if err := d.Validate(s, decode(t, `1`)); err == nil {
+		t.Fatal("number matched oneOf [array, object]")
+	}
+}
+
+func decode(t *testing.T, s string) interface{} {
+	t.Helper()
+	var v interface{}
+	if err := json.Unmarshal([]byte(s), &v); err != nil {
+		t.Fatal(err)
+	}
+	return v
+}
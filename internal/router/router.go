@@ -0,0 +1,156 @@
+// Package router is a small HTTP router: routes are a method plus a path
+// pattern whose segments may be {name} parameters. It answers 404 for unknown
+// paths and 405 (with Allow) for known paths requested with another method, so
+// handlers do not have to check r.Method themselves.
+package router
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+type route struct {
+	method string
+	segs   []string
+	h      http.Handler
+}
+
+// Router dispatches requests to the handler of the most specific matching
+// route: at the first segment where two patterns differ, a literal segment
+// wins over a parameter, so /ceremonies/active is preferred to /ceremonies/{id}.
+type Router struct {
+	routes []route
+	// NotFound handles paths no route matches; http.NotFound when nil.
+	NotFound http.Handler
+	// MethodNotAllowed handles known paths requested with another method,
+	// after the Allow header is set; a plain-text 405 when nil.
+	MethodNotAllowed http.Handler
+}
+
+func New() *Router { return &Router{} }
+
+// Handle registers h for method and pattern, e.g. "GET", "/movies/{id}".
+// A GET route also answers HEAD.
+func (rt *Router) Handle(method, pattern string, h http.Handler) {
+	if !strings.HasPrefix(pattern, "/") {
+		panic("router: pattern must start with /: " + pattern)
+	}
+	rt.routes = append(rt.routes, route{method: method, segs: split(pattern), h: h})
+}
+
+func (rt *Router) HandleFunc(method, pattern string, h http.HandlerFunc) {
+	rt.Handle(method, pattern, h)
+}
+
+func (rt *Router) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	segs := split(r.URL.Path)
+	var best *route
+	var params map[string]string
+	allowed := map[string]bool{}
+	for i := range rt.routes {
+		rte := &rt.routes[i]
+		p, ok := match(rte.segs, segs)
+		if !ok {
+			continue
+		}
+		allowed[rte.method] = true
+		if rte.method == http.MethodGet {
+			allowed[http.MethodHead] = true
+		}
+		if rte.method != r.Method && !(r.Method == http.MethodHead && rte.method == http.MethodGet) {
+			continue
+		}
+		if best == nil || moreSpecific(rte.segs, best.segs) {
+			best, params = rte, p
+		}
+	}
+	if best == nil {
+		if len(allowed) > 0 {
+			methods := make([]string, 0, len(allowed))
+			for m := range allowed {
+				methods = append(methods, m)
+			}
+			sort.Strings(methods)
+			w.Header().Set("Allow", strings.Join(methods, ", "))
+			if rt.MethodNotAllowed != nil {
+				rt.MethodNotAllowed.ServeHTTP(w, r)
+				return
+			}
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		if rt.NotFound != nil {
+			rt.NotFound.ServeHTTP(w, r)
+			return
+		}
+		http.NotFound(w, r)
+		return
+	}
+	if len(params) > 0 {
+		r = r.WithContext(context.WithValue(r.Context(), paramsKey{}, params))
+	}
+	best.h.ServeHTTP(w, r)
+}
+
+type paramsKey struct{}
+
+// Param returns the value of the {name} path parameter of the matched route,
+// or "" when the route has no such parameter.
+func Param(r *http.Request, name string) string {
+	p, _ := r.Context().Value(paramsKey{}).(map[string]string)
+	return p[name]
+}
+
+// WithParams returns a copy of r carrying the given path parameters, as if it
+// had been routed; for calling handlers directly in tests.
+func WithParams(r *http.Request, params map[string]string) *http.Request {
+	return r.WithContext(context.WithValue(r.Context(), paramsKey{}, params))
+}
+
+// split turns "/a/b/" into ["a", "b"]; a trailing slash is ignored.
+func split(path string) []string {
+	path = strings.Trim(path, "/")
+	if path == "" {
+		return nil
+	}
+	return strings.Split(path, "/")
+}
+
+func isParam(seg string) bool {
+	return len(seg) > 2 && seg[0] == '{' && seg[len(seg)-1] == '}'
+}
+
+func match(pattern, segs []string) (map[string]string, bool) {
+	if len(pattern) != len(segs) {
+		return nil, false
+	}
+	var params map[string]string
+	for i, p := range pattern {
+		if isParam(p) {
+			if segs[i] == "" {
+				return nil, false
+			}
+			if params == nil {
+				params = make(map[string]string)
+			}
+			params[p[1:len(p)-1]] = segs[i]
+			continue
+		}
+		if p != segs[i] {
+			return nil, false
+		}
+	}
+	return params, true
+}
+
+// moreSpecific reports whether pattern a beats pattern b; both match the same path.
+func moreSpecific(a, b []string) bool {
+	for i := range a {
+		if pa, pb := isParam(a[i]), isParam(b[i]); pa != pb {
+			return pb
+		}
+	}
+	return false
+}
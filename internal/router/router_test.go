@@ -0,0 +1,52 @@
+package router
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRouter(t *testing.T) {
+	rt := New()
+	reply := func(s string) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte(s + ":" + Param(r, "id")))
+		}
+	}
+	rt.HandleFunc(http.MethodGet, "/ceremonies/{id}", reply("get"))
+	rt.HandleFunc(http.MethodGet, "/ceremonies/active", reply("active"))
+	rt.HandleFunc(http.MethodDelete, "/ceremonies/{id}", reply("delete"))
+	rt.HandleFunc(http.MethodPost, "/ceremonies/{id}/activate", reply("activate"))
+
+	for _, tc := range []struct {
+		method, path string
+		code         int
+		body         string
+	}{
+		{http.MethodGet, "/ceremonies/c1", http.StatusOK, "get:c1"},
+		{http.MethodGet, "/ceremonies/c1/", http.StatusOK, "get:c1"},
+		{http.MethodGet, "/ceremonies/active", http.StatusOK, "active:"},
+		{http.MethodDelete, "/ceremonies/c1", http.StatusOK, "delete:c1"},
+		{http.MethodPost, "/ceremonies/c1/activate", http.StatusOK, "activate:c1"},
+		{http.MethodHead, "/ceremonies/c1", http.StatusOK, ""},
+		{http.MethodPut, "/ceremonies/c1", http.StatusMethodNotAllowed, ""},
+		{http.MethodGet, "/ceremonies", http.StatusNotFound, ""},
+		{http.MethodGet, "/ceremonies/c1/activate/x", http.StatusNotFound, ""},
+	} {
+		rr := httptest.NewRecorder()
+		rt.ServeHTTP(rr, httptest.NewRequest(tc.method, tc.path, nil))
+		if rr.Code != tc.code {
+			t.Errorf("%s %s: expected %d got %d", tc.method, tc.path, tc.code, rr.Code)
+			continue
+		}
+		if tc.body != "" && rr.Body.String() != tc.body {
+			t.Errorf("%s %s: expected %q got %q", tc.method, tc.path, tc.body, rr.Body.String())
+		}
+	}
+
+	rr := httptest.NewRecorder()
+	rt.ServeHTTP(rr, httptest.NewRequest(http.MethodPut, "/ceremonies/c1", nil))
+	if got := rr.Header().Get("Allow"); got != "DELETE, GET, HEAD" {
+		t.Fatalf("Allow: got %q", got)
+	}
+}
@@ -0,0 +1,59 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+)
+
+// ErrInUse is matched (errors.Is) by the *InUseError returned when a delete
+// would cascade to votes or winners and force was not set.
+var ErrInUse = errors.New("referenced by votes or winners")
+
+// InUseError reports how many votes and winners reference a row that was not deleted.
+type InUseError struct {
+	Votes   int
+	Winners int
+}
+
+func (e *InUseError) Error() string {
+	return fmt.Sprintf("referenced by %d vote(s) and %d winner(s)", e.Votes, e.Winners)
+}
+
+func (e *InUseError) Is(target error) bool { return target == ErrInUse }
+
+// deleteUnlessInUse deletes one row in a transaction. lockQuery locks the row
+// (and reports sql.ErrNoRows if it is missing); countQuery returns the number
+// of votes and winners that the FK cascades would delete along with it.
+func deleteUnlessInUse(ctx context.Context, db *sql.DB, what, lockQuery, countQuery, deleteQuery, id string, force bool) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback()
+	var locked string
+	if err := tx.QueryRowContext(ctx, lockQuery, id).Scan(&locked); err != nil {
+		return fmt.Errorf("delete %s: %w", what, err)
+	}
+	if !force {
+		var in InUseError
+		if err := tx.QueryRowContext(ctx, countQuery, id).Scan(&in.Votes, &in.Winners); err != nil {
+			return fmt.Errorf("count %s references: %w", what, err)
+		}
+		if in.Votes > 0 || in.Winners > 0 {
+			return fmt.Errorf("delete %s: %w", what, &in)
+		}
+	}
+	if _, err := tx.ExecContext(ctx, deleteQuery, id); err != nil {
+		return fmt.Errorf("delete %s: %w", what, err)
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit: %w", err)
+	}
+	if force {
+		slog.InfoContext(ctx, "forced delete", "what", what, "id", id)
+	}
+	return nil
+}
@@ -0,0 +1,231 @@
+package memstore
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+
+	"votacao/internal/store"
+	"votacao/models"
+)
+
+// CategoryStore implements store.CategoryStore in memory.
+type CategoryStore struct{ db *DB }
+
+func NewCategory(db *DB) *CategoryStore { return &CategoryStore{db: db} }
+
+func (s *CategoryStore) Insert(ctx context.Context, c *models.Category) (string, error) {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+	id, err := s.db.insertCategory(c)
+	if err != nil {
+		return "", fmt.Errorf("insert category: %w", err)
+	}
+	return id, nil
+}
+
+// InsertMany inserts all categories or none, returning their IDs in order.
+func (s *CategoryStore) InsertMany(ctx context.Context, cs []models.Category) ([]string, error) {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+	ids := make([]string, 0, len(cs))
+	for i := range cs {
+		id, err := s.db.insertCategory(&cs[i])
+		if err != nil {
+			for _, id := range ids {
+				delete(s.db.categories, id)
+			}
+			return nil, fmt.Errorf("insert categories: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+func (db *DB) insertCategory(c *models.Category) (string, error) {
+	if c.Points < 0 {
+		return "", fmt.Errorf("insert category: points %d: %w", c.Points, ErrCheckViolation)
+	}
+	if _, ok := db.ceremonies[c.CeremonyID]; !ok {
+		return "", fmt.Errorf("ceremony %q: %w", c.CeremonyID, ErrForeignKeyViolation)
+	}
+	if err := db.checkCategoryName(c.CeremonyID, c.Name, ""); err != nil {
+		return "", err
+	}
+	id := uuid.NewString()
+	db.categories[id] = models.Category{
+		ID:            id,
+		CeremonyID:    c.CeremonyID,
+		Name:          c.Name,
+		SequenceOrder: c.SequenceOrder,
+		Points:        c.Points,
+	}
+	return id, nil
+}
+
+// checkCategoryName enforces UNIQUE (ceremony_id, name), ignoring the row being updated.
+func (db *DB) checkCategoryName(ceremonyID, name, exceptID string) error {
+	for id, o := range db.categories {
+		if id != exceptID && o.CeremonyID == ceremonyID && o.Name == name {
+			return fmt.Errorf("category %q: %w", name, ErrUniqueViolation)
+		}
+	}
+	return nil
+}
+
+// category returns a copy of a stored category, so callers cannot share LocksAt.
+func (db *DB) category(id string) (models.Category, bool) {
+	c, ok := db.categories[id]
+	if ok && c.LocksAt != nil {
+		t := *c.LocksAt
+		c.LocksAt = &t
+	}
+	return c, ok
+}
+
+func (s *CategoryStore) Get(ctx context.Context, id string) (*models.Category, error) {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+	c, ok := s.db.category(id)
+	if !ok {
+		return nil, nil
+	}
+	return &c, nil
+}
+
+// List returns up to 100 categories of the ceremony ordered by sequence_order, then id descending.
+func (s *CategoryStore) List(ctx context.Context, ceremonyID string) ([]models.Category, error) {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+	var out []models.Category
+	for id, c := range s.db.categories {
+		if c.CeremonyID == ceremonyID {
+			c, _ = s.db.category(id)
+			out = append(out, c)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].SequenceOrder != out[j].SequenceOrder {
+			return out[i].SequenceOrder < out[j].SequenceOrder
+		}
+		return out[i].ID > out[j].ID
+	})
+	if len(out) > listLimit {
+		out = out[:listLimit]
+	}
+	return out, nil
+}
+
+// Update changes a category's name, sequence order and points.
+// It returns sql.ErrNoRows if the category does not exist.
+func (s *CategoryStore) Update(ctx context.Context, c *models.Category) error {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+	cur, ok := s.db.categories[c.ID]
+	if !ok {
+		return fmt.Errorf("update category: %w", sql.ErrNoRows)
+	}
+	if c.Points < 0 {
+		return fmt.Errorf("update category: points %d: %w", c.Points, ErrCheckViolation)
+	}
+	if err := s.db.checkCategoryName(cur.CeremonyID, c.Name, c.ID); err != nil {
+		return fmt.Errorf("update category: %w", err)
+	}
+	cur.Name, cur.SequenceOrder, cur.Points = c.Name, c.SequenceOrder, c.Points
+	s.db.categories[c.ID] = cur
+	return nil
+}
+
+// SetPoints updates the points of several categories (category id -> points)
+// of ceremonyID all at once: nothing changes if any id is unknown, belongs to
+// another ceremony or a weight is negative.
+func (s *CategoryStore) SetPoints(ctx context.Context, ceremonyID string, points map[string]int) error {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+	for id, p := range points {
+		if c, ok := s.db.categories[id]; !ok || c.CeremonyID != ceremonyID {
+			return fmt.Errorf("set points for category %s: %w", id, sql.ErrNoRows)
+		}
+		if p < 0 {
+			return fmt.Errorf("set points: points %d: %w", p, ErrCheckViolation)
+		}
+	}
+	for id, p := range points {
+		c := s.db.categories[id]
+		c.Points = p
+		s.db.categories[id] = c
+	}
+	return nil
+}
+
+// SetLock sets the locked flag and the scheduled lock time (nil clears it).
+// It returns sql.ErrNoRows if the category does not exist.
+func (s *CategoryStore) SetLock(ctx context.Context, id string, locked bool, locksAt *time.Time) error {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+	c, ok := s.db.categories[id]
+	if !ok {
+		return fmt.Errorf("set category lock: %w", sql.ErrNoRows)
+	}
+	c.Locked = locked
+	c.LocksAt = nil
+	if locksAt != nil {
+		t := *locksAt
+		c.LocksAt = &t
+	}
+	s.db.categories[id] = c
+	return nil
+}
+
+// Delete removes the category and cascades to its nominees, which requires
+// force when votes or winners reference them.
+func (s *CategoryStore) Delete(ctx context.Context, id string, force bool) error {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+	if _, ok := s.db.categories[id]; !ok {
+		return fmt.Errorf("delete category: %w", sql.ErrNoRows)
+	}
+	nominees := make(map[string]bool)
+	for nid, r := range s.db.nominees {
+		if r.n.CategoryID == id {
+			nominees[nid] = true
+		}
+	}
+	if err := s.db.deleteUnlessInUse("category", nominees, force); err != nil {
+		return err
+	}
+	delete(s.db.categories, id)
+	return nil
+}
+
+type categoryKey struct {
+	SequenceOrder int    `json:"seq"`
+	ID            string `json:"id"`
+}
+
+// ListPage returns categories by sequence order, like List, one page at a time.
+func (s *CategoryStore) ListPage(ctx context.Context, f store.CategoryFilter, p store.Page) ([]models.Category, string, error) {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+	var rows []models.Category
+	for _, c := range s.db.categories {
+		if f.CeremonyID == "" || c.CeremonyID == f.CeremonyID {
+			rows = append(rows, c)
+		}
+	}
+	sort.Slice(rows, func(i, j int) bool {
+		if rows[i].SequenceOrder != rows[j].SequenceOrder {
+			return rows[i].SequenceOrder < rows[j].SequenceOrder
+		}
+		return rows[i].ID > rows[j].ID
+	})
+	return page(rows, p,
+		func(c models.Category) categoryKey { return categoryKey{SequenceOrder: c.SequenceOrder, ID: c.ID} },
+		func(c models.Category, k categoryKey) bool {
+			return c.SequenceOrder > k.SequenceOrder || (c.SequenceOrder == k.SequenceOrder && c.ID < k.ID)
+		})
+}
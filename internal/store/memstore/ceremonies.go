@@ -0,0 +1,94 @@
+package memstore
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+
+	"github.com/google/uuid"
+
+	"votacao/models"
+)
+
+// CeremonyStore implements store.CeremonyStore in memory.
+type CeremonyStore struct{ db *DB }
+
+func NewCeremony(db *DB) *CeremonyStore { return &CeremonyStore{db: db} }
+
+func (s *CeremonyStore) Insert(ctx context.Context, c *models.Ceremony) (string, error) {
+	if c.Status == "" {
+		c.Status = models.CeremonyUpcoming
+	}
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+	switch c.Status {
+	case models.CeremonyUpcoming, models.CeremonyActive, models.CeremonyArchived:
+	default:
+		return "", fmt.Errorf("insert ceremony: status %q: %w", c.Status, ErrCheckViolation)
+	}
+	for _, o := range s.db.ceremonies {
+		if o.Year == c.Year {
+			return "", fmt.Errorf("insert ceremony: year %d: %w", c.Year, ErrUniqueViolation)
+		}
+		if c.Status == models.CeremonyActive && o.Status == models.CeremonyActive {
+			return "", fmt.Errorf("insert ceremony: second active ceremony: %w", ErrUniqueViolation)
+		}
+	}
+	id := uuid.NewString()
+	cer := *c
+	cer.ID = id
+	s.db.ceremonies[id] = cer
+	return id, nil
+}
+
+func (s *CeremonyStore) Get(ctx context.Context, id string) (*models.Ceremony, error) {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+	c, ok := s.db.ceremonies[id]
+	if !ok {
+		return nil, nil
+	}
+	return &c, nil
+}
+
+func (s *CeremonyStore) GetActive(ctx context.Context) (*models.Ceremony, error) {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+	for _, c := range s.db.ceremonies {
+		if c.Status == models.CeremonyActive {
+			return &c, nil
+		}
+	}
+	return nil, nil
+}
+
+func (s *CeremonyStore) List(ctx context.Context) ([]models.Ceremony, error) {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+	out := make([]models.Ceremony, 0, len(s.db.ceremonies))
+	for _, c := range s.db.ceremonies {
+		out = append(out, c)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Year > out[j].Year })
+	return out, nil
+}
+
+// SetActive archives the currently active ceremony and activates the given one.
+func (s *CeremonyStore) SetActive(ctx context.Context, id string) error {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+	c, ok := s.db.ceremonies[id]
+	if !ok {
+		return fmt.Errorf("activate ceremony: %w", sql.ErrNoRows)
+	}
+	for oid, o := range s.db.ceremonies {
+		if oid != id && o.Status == models.CeremonyActive {
+			o.Status = models.CeremonyArchived
+			s.db.ceremonies[oid] = o
+		}
+	}
+	c.Status = models.CeremonyActive
+	s.db.ceremonies[id] = c
+	return nil
+}
@@ -0,0 +1,122 @@
+package memstore
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base32"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"votacao/models"
+)
+
+// LeagueStore implements store.LeagueStore in memory.
+type LeagueStore struct{ db *DB }
+
+func NewLeague(db *DB) *LeagueStore { return &LeagueStore{db: db} }
+
+// newInviteCode returns a short random code, in the same format as the SQL store.
+func newInviteCode() (string, error) {
+	b := make([]byte, 5)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return strings.ToUpper(base32.StdEncoding.EncodeToString(b)), nil
+}
+
+// Insert creates the league and adds its owner as the first member.
+// An invite code is generated when none is set.
+func (s *LeagueStore) Insert(ctx context.Context, l *models.League) (string, error) {
+	if l.InviteCode == "" {
+		code, err := newInviteCode()
+		if err != nil {
+			return "", fmt.Errorf("generate invite code: %w", err)
+		}
+		l.InviteCode = code
+	}
+	if l.CreatedAt.IsZero() {
+		l.CreatedAt = time.Now()
+	}
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+	if _, ok := s.db.users[l.OwnerID]; !ok {
+		return "", fmt.Errorf("insert league: owner %q: %w", l.OwnerID, ErrForeignKeyViolation)
+	}
+	for _, o := range s.db.leagues {
+		if o.InviteCode == l.InviteCode {
+			return "", fmt.Errorf("insert league: invite code: %w", ErrUniqueViolation)
+		}
+	}
+	id := uuid.NewString()
+	lg := *l
+	lg.ID = id
+	s.db.leagues[id] = lg
+	s.db.members[id] = map[string]bool{l.OwnerID: true}
+	return id, nil
+}
+
+func (s *LeagueStore) Get(ctx context.Context, id string) (*models.League, error) {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+	l, ok := s.db.leagues[id]
+	if !ok {
+		return nil, nil
+	}
+	return &l, nil
+}
+
+func (s *LeagueStore) GetByInviteCode(ctx context.Context, code string) (*models.League, error) {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+	for _, l := range s.db.leagues {
+		if l.InviteCode == code {
+			return &l, nil
+		}
+	}
+	return nil, nil
+}
+
+// ListByUser returns the leagues the user is a member of, by name.
+func (s *LeagueStore) ListByUser(ctx context.Context, userID string) ([]models.League, error) {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+	out := make([]models.League, 0)
+	for id, l := range s.db.leagues {
+		if s.db.members[id][userID] {
+			out = append(out, l)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out, nil
+}
+
+// AddMember adds the user to the league; joining twice is a no-op.
+func (s *LeagueStore) AddMember(ctx context.Context, leagueID, userID string) error {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+	if _, ok := s.db.leagues[leagueID]; !ok {
+		return fmt.Errorf("add league member: league %q: %w", leagueID, ErrForeignKeyViolation)
+	}
+	if _, ok := s.db.users[userID]; !ok {
+		return fmt.Errorf("add league member: user %q: %w", userID, ErrForeignKeyViolation)
+	}
+	s.db.members[leagueID][userID] = true
+	return nil
+}
+
+func (s *LeagueStore) RemoveMember(ctx context.Context, leagueID, userID string) error {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+	delete(s.db.members[leagueID], userID)
+	return nil
+}
+
+func (s *LeagueStore) IsMember(ctx context.Context, leagueID, userID string) (bool, error) {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+	return s.db.members[leagueID][userID], nil
+}
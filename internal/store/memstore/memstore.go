@@ -0,0 +1,186 @@
+// Package memstore implements the store interfaces in memory. It mirrors the
+// Postgres stores (constraints, upserts, ordering, 100-row limits and
+// scoring) so it can back the handler tests and run the app without a
+// database (STORE=memory). Data is lost when the process exits.
+package memstore
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"votacao/internal/store"
+	"votacao/models"
+)
+
+// Errors standing in for the Postgres constraint violations; store.Violation
+// recognises them.
+var (
+	ErrUniqueViolation     = store.ErrUniqueViolation
+	ErrForeignKeyViolation = store.ErrForeignKeyViolation
+	ErrCheckViolation      = store.ErrCheckViolation
+)
+
+// The memory stores implement every store interface.
+var (
+	_ store.MovieStore     = (*MovieStore)(nil)
+	_ store.CeremonyStore  = (*CeremonyStore)(nil)
+	_ store.CategoryStore  = (*CategoryStore)(nil)
+	_ store.NominatedStore = (*NominatedStore)(nil)
+	_ store.UserStore      = (*UserStore)(nil)
+	_ store.VoteStore      = (*VoteStore)(nil)
+	_ store.WinnerStore    = (*WinnerStore)(nil)
+	_ store.LeagueStore    = (*LeagueStore)(nil)
+	_ store.SessionStore   = (*SessionStore)(nil)
+)
+
+// listLimit matches the LIMIT 100 of the SQL list queries.
+const listLimit = 100
+
+// defaultURLImage is the nominees.url_image column default (migration 007).
+const defaultURLImage = "https://s2-gshow.glbimg.com/KIfsgPzVx8g-zWDxOSGy4llwWLw=/0x0:1080x1182/984x0/smart/filters:strip_icc()/i.s3.glbimg.com/v1/AUTH_e84042ef78cb4708aeebdf1c68c6cbd6/internal_photos/bs/2026/y/S/gpOY25TAizQq9IcyUHeg/theacademy-20260102-150058-1663833045.jpg"
+
+// DB holds every table. The stores created from the same DB share it, the way
+// the SQL stores share one *sql.DB, so joins (scores, ceremony scoping) work.
+type DB struct {
+	mu sync.Mutex
+	// seq orders rows that the SQL stores sort by created_at.
+	seq int64
+
+	movies     map[string]models.Movie
+	ceremonies map[string]models.Ceremony
+	categories map[string]models.Category
+	nominees   map[string]nomineeRow
+	users      map[string]userRow
+	votes      map[int64]voteRow
+	nextVoteID int64
+	events     []models.VoteEvent
+	winners    map[string]winnerRow
+	leagues    map[string]models.League
+	members    map[string]map[string]bool // league id -> user ids
+	sessions   map[string]models.Session
+}
+
+type nomineeRow struct {
+	n   models.Nominated
+	seq int64
+}
+
+type userRow struct {
+	u   models.User
+	seq int64
+}
+
+type voteRow struct {
+	v   models.Vote
+	seq int64
+}
+
+type winnerRow struct {
+	w   models.Winner
+	seq int64
+}
+
+// New returns an empty in-memory database.
+func New() *DB {
+	return &DB{
+		movies:     make(map[string]models.Movie),
+		ceremonies: make(map[string]models.Ceremony),
+		categories: make(map[string]models.Category),
+		nominees:   make(map[string]nomineeRow),
+		users:      make(map[string]userRow),
+		votes:      make(map[int64]voteRow),
+		winners:    make(map[string]winnerRow),
+		leagues:    make(map[string]models.League),
+		members:    make(map[string]map[string]bool),
+		sessions:   make(map[string]models.Session),
+	}
+}
+
+// next returns the next insertion sequence number; callers hold mu.
+func (db *DB) next() int64 {
+	db.seq++
+	return db.seq
+}
+
+// nomineeRefs counts the votes and winners referencing any of the nominees.
+// Callers hold mu.
+func (db *DB) nomineeRefs(nominees map[string]bool) *store.InUseError {
+	var in store.InUseError
+	for _, r := range db.votes {
+		if nominees[r.v.NominatedID] {
+			in.Votes++
+		}
+	}
+	for _, r := range db.winners {
+		if nominees[r.w.NominatedID] {
+			in.Winners++
+		}
+	}
+	return &in
+}
+
+// deleteNominees removes the nominees and, like ON DELETE CASCADE, their
+// votes and winners. Callers hold mu.
+func (db *DB) deleteNominees(nominees map[string]bool) {
+	for id, r := range db.votes {
+		if nominees[r.v.NominatedID] {
+			delete(db.votes, id)
+		}
+	}
+	for id, r := range db.winners {
+		if nominees[r.w.NominatedID] {
+			delete(db.winners, id)
+		}
+	}
+	for id := range nominees {
+		delete(db.nominees, id)
+	}
+}
+
+// deleteUnlessInUse applies the force rule of the Delete methods to the
+// nominees a delete would cascade to. Callers hold mu.
+func (db *DB) deleteUnlessInUse(what string, nominees map[string]bool, force bool) error {
+	if !force {
+		if in := db.nomineeRefs(nominees); in.Votes > 0 || in.Winners > 0 {
+			return fmt.Errorf("delete %s: %w", what, in)
+		}
+	}
+	db.deleteNominees(nominees)
+	return nil
+}
+
+// now is the timestamp written where Postgres would use now().
+func now() time.Time { return time.Now() }
+
+// page returns the page of the sorted rows that follows p.Cursor and the
+// cursor of the next page. key returns a row's cursor key and after reports
+// whether a row sorts after a decoded key.
+func page[T, K any](rows []T, p store.Page, key func(T) K, after func(T, K) bool) ([]T, string, error) {
+	if p.Cursor != "" {
+		var k K
+		if err := store.DecodeCursor(p.Cursor, &k); err != nil {
+			return nil, "", err
+		}
+		i := 0
+		for i < len(rows) && !after(rows[i], k) {
+			i++
+		}
+		rows = rows[i:]
+	}
+	if rows == nil {
+		rows = make([]T, 0)
+	}
+	size := p.Size()
+	if len(rows) <= size {
+		return rows, "", nil
+	}
+	rows = rows[:size]
+	return rows, store.EncodeCursor(key(rows[size-1])), nil
+}
+
+// hasPrefixFold reports whether s starts with prefix, ignoring case.
+func hasPrefixFold(s, prefix string) bool {
+	return strings.HasPrefix(strings.ToLower(s), strings.ToLower(prefix))
+}
@@ -0,0 +1,52 @@
+package memstore
+
+import (
+	"strings"
+	"testing"
+
+	"votacao/internal/metrics"
+	"votacao/internal/store"
+	"votacao/internal/store/storetest"
+)
+
+func TestConformance(t *testing.T) {
+	storetest.Run(t, func(t *testing.T) storetest.Stores {
+		db := New()
+		return storetest.Stores{
+			Movies:     NewMovie(db),
+			Ceremonies: NewCeremony(db),
+			Categories: NewCategory(db),
+			Nominated:  NewNominated(db),
+			Users:      NewUser(db),
+			Votes:      NewVote(db),
+			Winners:    NewWinner(db),
+			Leagues:    NewLeague(db),
+			Sessions:   NewSession(db),
+		}
+	})
+}
+
+// TestMeteredConformance runs the suite through the Metered wrappers, which
+// must forward every call unchanged.
+func TestMeteredConformance(t *testing.T) {
+	storetest.Run(t, func(t *testing.T) storetest.Stores {
+		db := New()
+		return storetest.Stores{
+			Movies:     store.MeteredMovie(NewMovie(db)),
+			Ceremonies: store.MeteredCeremony(NewCeremony(db)),
+			Categories: store.MeteredCategory(NewCategory(db)),
+			Nominated:  store.MeteredNominated(NewNominated(db)),
+			Users:      store.MeteredUser(NewUser(db)),
+			Votes:      store.MeteredVote(NewVote(db)),
+			Winners:    store.MeteredWinner(NewWinner(db)),
+			Leagues:    store.MeteredLeague(NewLeague(db)),
+			Sessions:   store.MeteredSession(NewSession(db)),
+		}
+	})
+
+	var b strings.Builder
+	metrics.Default.WriteText(&b)
+	if !strings.Contains(b.String(), `votacao_store_duration_seconds_count{method="VoteStore.Insert"}`) {
+		t.Fatalf("store calls not timed:\n%s", b.String())
+	}
+}
@@ -0,0 +1,157 @@
+package memstore
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+
+	"github.com/google/uuid"
+
+	"votacao/internal/store"
+	"votacao/models"
+)
+
+// MovieStore implements store.MovieStore in memory.
+type MovieStore struct{ db *DB }
+
+func NewMovie(db *DB) *MovieStore { return &MovieStore{db: db} }
+
+func (s *MovieStore) Insert(ctx context.Context, m *models.Movie) (string, error) {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+	id, err := s.db.insertMovie(m)
+	if err != nil {
+		return "", fmt.Errorf("insert: %w", err)
+	}
+	return id, nil
+}
+
+// InsertMany inserts all movies or none, returning their IDs in order.
+func (s *MovieStore) InsertMany(ctx context.Context, ms []models.Movie) ([]string, error) {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+	ids := make([]string, 0, len(ms))
+	for i := range ms {
+		id, err := s.db.insertMovie(&ms[i])
+		if err != nil {
+			for _, id := range ids {
+				delete(s.db.movies, id)
+			}
+			return nil, fmt.Errorf("insert many: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+func (db *DB) insertMovie(m *models.Movie) (string, error) {
+	for _, o := range db.movies {
+		if o.Title == m.Title {
+			return "", fmt.Errorf("movies title %q: %w", m.Title, ErrUniqueViolation)
+		}
+	}
+	id := uuid.NewString()
+	db.movies[id] = models.Movie{ID: id, Title: m.Title}
+	return id, nil
+}
+
+func (s *MovieStore) Get(ctx context.Context, id string) (*models.Movie, error) {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+	m, ok := s.db.movies[id]
+	if !ok {
+		return nil, nil
+	}
+	return &m, nil
+}
+
+// GetMany returns the movies with the given ids, ordered by id descending.
+func (s *MovieStore) GetMany(ctx context.Context, ids []string) ([]models.Movie, error) {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+	out := make([]models.Movie, 0, len(ids))
+	seen := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		if m, ok := s.db.movies[id]; ok && !seen[id] {
+			seen[id] = true
+			out = append(out, m)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].ID > out[j].ID })
+	return out, nil
+}
+
+// List returns up to 100 movies ordered by id descending, like the SQL store.
+func (s *MovieStore) List(ctx context.Context) ([]models.Movie, error) {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+	var out []models.Movie
+	for _, m := range s.db.movies {
+		out = append(out, m)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].ID > out[j].ID })
+	if len(out) > listLimit {
+		out = out[:listLimit]
+	}
+	return out, nil
+}
+
+// Update changes a movie's title. It returns sql.ErrNoRows if the movie does not exist.
+func (s *MovieStore) Update(ctx context.Context, m *models.Movie) error {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+	cur, ok := s.db.movies[m.ID]
+	if !ok {
+		return fmt.Errorf("update movie: %w", sql.ErrNoRows)
+	}
+	for _, o := range s.db.movies {
+		if o.ID != m.ID && o.Title == m.Title {
+			return fmt.Errorf("update movie: movies title %q: %w", m.Title, ErrUniqueViolation)
+		}
+	}
+	cur.Title = m.Title
+	s.db.movies[m.ID] = cur
+	return nil
+}
+
+// Delete removes the movie and cascades to its nominees, which requires force
+// when votes or winners reference them.
+func (s *MovieStore) Delete(ctx context.Context, id string, force bool) error {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+	if _, ok := s.db.movies[id]; !ok {
+		return fmt.Errorf("delete movie: %w", sql.ErrNoRows)
+	}
+	nominees := make(map[string]bool)
+	for nid, r := range s.db.nominees {
+		if r.n.MovieID == id {
+			nominees[nid] = true
+		}
+	}
+	if err := s.db.deleteUnlessInUse("movie", nominees, force); err != nil {
+		return err
+	}
+	delete(s.db.movies, id)
+	return nil
+}
+
+type movieKey struct {
+	ID string `json:"id"`
+}
+
+// ListPage returns movies by id descending, like List, one page at a time.
+func (s *MovieStore) ListPage(ctx context.Context, f store.MovieFilter, p store.Page) ([]models.Movie, string, error) {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+	var rows []models.Movie
+	for _, m := range s.db.movies {
+		if f.TitlePrefix == "" || hasPrefixFold(m.Title, f.TitlePrefix) {
+			rows = append(rows, m)
+		}
+	}
+	sort.Slice(rows, func(i, j int) bool { return rows[i].ID > rows[j].ID })
+	return page(rows, p,
+		func(m models.Movie) movieKey { return movieKey{ID: m.ID} },
+		func(m models.Movie, k movieKey) bool { return m.ID < k.ID })
+}
@@ -0,0 +1,187 @@
+package memstore
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+
+	"github.com/google/uuid"
+
+	"votacao/internal/store"
+	"votacao/models"
+)
+
+// NominatedStore implements store.NominatedStore in memory.
+type NominatedStore struct{ db *DB }
+
+func NewNominated(db *DB) *NominatedStore { return &NominatedStore{db: db} }
+
+func (s *NominatedStore) Insert(ctx context.Context, n *models.Nominated) (string, error) {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+	id, err := s.db.insertNominee(n)
+	if err != nil {
+		return "", fmt.Errorf("insert nominated: %w", err)
+	}
+	return id, nil
+}
+
+// InsertMany inserts all nominations or none, returning their IDs in order.
+func (s *NominatedStore) InsertMany(ctx context.Context, ns []models.Nominated) ([]string, error) {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+	ids := make([]string, 0, len(ns))
+	for i := range ns {
+		id, err := s.db.insertNominee(&ns[i])
+		if err != nil {
+			for _, id := range ids {
+				delete(s.db.nominees, id)
+			}
+			return nil, fmt.Errorf("insert many nominated: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+func (db *DB) insertNominee(n *models.Nominated) (string, error) {
+	if _, ok := db.movies[n.MovieID]; !ok {
+		return "", fmt.Errorf("movie %q: %w", n.MovieID, ErrForeignKeyViolation)
+	}
+	if _, ok := db.categories[n.CategoryID]; !ok {
+		return "", fmt.Errorf("category %q: %w", n.CategoryID, ErrForeignKeyViolation)
+	}
+	// UNIQUE (movie_id, category_id, nominee_name); an empty name is NULL and never conflicts
+	if n.Name != "" {
+		for _, o := range db.nominees {
+			if o.n.MovieID == n.MovieID && o.n.CategoryID == n.CategoryID && o.n.Name == n.Name {
+				return "", fmt.Errorf("nominee %q: %w", n.Name, ErrUniqueViolation)
+			}
+		}
+	}
+	url := n.UrlImage
+	if url == "" {
+		url = defaultURLImage
+	}
+	id := uuid.NewString()
+	db.nominees[id] = nomineeRow{
+		n:   models.Nominated{ID: id, MovieID: n.MovieID, CategoryID: n.CategoryID, Name: n.Name, UrlImage: url},
+		seq: db.next(),
+	}
+	return id, nil
+}
+
+func (s *NominatedStore) Get(ctx context.Context, id string) (*models.Nominated, error) {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+	r, ok := s.db.nominees[id]
+	if !ok {
+		return nil, nil
+	}
+	return &r.n, nil
+}
+
+// List returns up to 100 nominations of the ceremony, newest first.
+func (s *NominatedStore) List(ctx context.Context, ceremonyID string) ([]models.Nominated, error) {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+	out := s.db.listNominees(func(n models.Nominated) bool {
+		return s.db.categories[n.CategoryID].CeremonyID == ceremonyID
+	})
+	if len(out) == 0 {
+		return nil, nil
+	}
+	return out, nil
+}
+
+// ListByCategory returns up to 100 nominations of the category, newest first.
+func (s *NominatedStore) ListByCategory(ctx context.Context, categoryID string) ([]models.Nominated, error) {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+	return s.db.listNominees(func(n models.Nominated) bool { return n.CategoryID == categoryID }), nil
+}
+
+func (db *DB) listNominees(keep func(models.Nominated) bool) []models.Nominated {
+	rows := make([]nomineeRow, 0)
+	for _, r := range db.nominees {
+		if keep(r.n) {
+			rows = append(rows, r)
+		}
+	}
+	sort.Slice(rows, func(i, j int) bool { return rows[i].seq > rows[j].seq })
+	if len(rows) > listLimit {
+		rows = rows[:listLimit]
+	}
+	out := make([]models.Nominated, 0, len(rows))
+	for _, r := range rows {
+		out = append(out, r.n)
+	}
+	return out
+}
+
+// Update changes the nomination's movie, name and image URL; the category is fixed.
+func (s *NominatedStore) Update(ctx context.Context, n *models.Nominated) error {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+	cur, ok := s.db.nominees[n.ID]
+	if !ok {
+		return fmt.Errorf("update nominated: %w", sql.ErrNoRows)
+	}
+	if _, ok := s.db.movies[n.MovieID]; !ok {
+		return fmt.Errorf("update nominated: movie %q: %w", n.MovieID, ErrForeignKeyViolation)
+	}
+	if n.Name != "" {
+		for id, o := range s.db.nominees {
+			if id != n.ID && o.n.MovieID == n.MovieID && o.n.CategoryID == cur.n.CategoryID && o.n.Name == n.Name {
+				return fmt.Errorf("update nominated: nominee %q: %w", n.Name, ErrUniqueViolation)
+			}
+		}
+	}
+	cur.n.MovieID, cur.n.Name, cur.n.UrlImage = n.MovieID, n.Name, n.UrlImage
+	s.db.nominees[n.ID] = cur
+	return nil
+}
+
+// Delete removes the nomination, which requires force when votes or a winner
+// reference it.
+func (s *NominatedStore) Delete(ctx context.Context, id string, force bool) error {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+	if _, ok := s.db.nominees[id]; !ok {
+		return fmt.Errorf("delete nominated: %w", sql.ErrNoRows)
+	}
+	return s.db.deleteUnlessInUse("nominated", map[string]bool{id: true}, force)
+}
+
+type seqKey struct {
+	Seq int64 `json:"seq"`
+}
+
+// ListPage returns nominations newest first, one page at a time.
+func (s *NominatedStore) ListPage(ctx context.Context, f store.NominatedFilter, p store.Page) ([]models.Nominated, string, error) {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+	var rows []nomineeRow
+	for _, r := range s.db.nominees {
+		if f.CeremonyID != "" && s.db.categories[r.n.CategoryID].CeremonyID != f.CeremonyID {
+			continue
+		}
+		if (f.CategoryID != "" && r.n.CategoryID != f.CategoryID) || (f.MovieID != "" && r.n.MovieID != f.MovieID) {
+			continue
+		}
+		rows = append(rows, r)
+	}
+	sort.Slice(rows, func(i, j int) bool { return rows[i].seq > rows[j].seq })
+	rows, next, err := page(rows, p,
+		func(r nomineeRow) seqKey { return seqKey{Seq: r.seq} },
+		func(r nomineeRow, k seqKey) bool { return r.seq < k.Seq })
+	if err != nil {
+		return nil, "", err
+	}
+	out := make([]models.Nominated, 0, len(rows))
+	for _, r := range rows {
+		out = append(out, r.n)
+	}
+	return out, next, nil
+}
@@ -0,0 +1,155 @@
+package memstore
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+
+	"votacao/models"
+)
+
+// SessionStore implements store.SessionStore in memory.
+type SessionStore struct{ db *DB }
+
+func NewSession(db *DB) *SessionStore { return &SessionStore{db: db} }
+
+// session returns a copy of a stored session; callers hold mu.
+func (db *DB) session(id string) (models.Session, bool) {
+	s, ok := db.sessions[id]
+	if ok && s.RevokedAt != nil {
+		t := *s.RevokedAt
+		s.RevokedAt = &t
+	}
+	return s, ok
+}
+
+func (s *SessionStore) Insert(ctx context.Context, sess *models.Session) (string, error) {
+	now := now()
+	if sess.CreatedAt.IsZero() {
+		sess.CreatedAt = now
+	}
+	if sess.LastUsedAt.IsZero() {
+		sess.LastUsedAt = sess.CreatedAt
+	}
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+	if _, ok := s.db.users[sess.UserID]; !ok {
+		return "", fmt.Errorf("insert session: user %q: %w", sess.UserID, ErrForeignKeyViolation)
+	}
+	for _, o := range s.db.sessions {
+		if o.TokenHash == sess.TokenHash {
+			return "", fmt.Errorf("insert session: token hash: %w", ErrUniqueViolation)
+		}
+	}
+	id := uuid.NewString()
+	s.db.sessions[id] = models.Session{
+		ID:         id,
+		UserID:     sess.UserID,
+		UserAgent:  sess.UserAgent,
+		IP:         sess.IP,
+		CreatedAt:  sess.CreatedAt,
+		LastUsedAt: sess.LastUsedAt,
+		ExpiresAt:  sess.ExpiresAt,
+		TokenHash:  sess.TokenHash,
+	}
+	return id, nil
+}
+
+func (s *SessionStore) Get(ctx context.Context, id string) (*models.Session, error) {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+	sess, ok := s.db.session(id)
+	if !ok {
+		return nil, nil
+	}
+	return &sess, nil
+}
+
+func (s *SessionStore) GetByTokenHash(ctx context.Context, hash string) (*models.Session, error) {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+	for id, o := range s.db.sessions {
+		if o.TokenHash == hash || (o.PreviousTokenHash != "" && o.PreviousTokenHash == hash) {
+			sess, _ := s.db.session(id)
+			return &sess, nil
+		}
+	}
+	return nil, nil
+}
+
+// Rotate swaps the refresh token hash only if oldHash is still current.
+func (s *SessionStore) Rotate(ctx context.Context, id, oldHash, newHash string, expiresAt time.Time) error {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+	sess, ok := s.db.sessions[id]
+	if !ok || sess.TokenHash != oldHash || sess.RevokedAt != nil {
+		return fmt.Errorf("rotate session: %w", sql.ErrNoRows)
+	}
+	t := now()
+	sess.PreviousTokenHash, sess.TokenHash = sess.TokenHash, newHash
+	sess.RotatedAt, sess.LastUsedAt, sess.ExpiresAt = t, t, expiresAt
+	s.db.sessions[id] = sess
+	return nil
+}
+
+func (s *SessionStore) Touch(ctx context.Context, id string, at time.Time) error {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+	if sess, ok := s.db.sessions[id]; ok {
+		sess.LastUsedAt = at
+		s.db.sessions[id] = sess
+	}
+	return nil
+}
+
+// ListByUser returns the user's sessions that are neither revoked nor expired,
+// most recently used first.
+func (s *SessionStore) ListByUser(ctx context.Context, userID string) ([]models.Session, error) {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+	t := now()
+	out := make([]models.Session, 0)
+	for id, o := range s.db.sessions {
+		if o.UserID == userID && o.IsActive(t) {
+			sess, _ := s.db.session(id)
+			out = append(out, sess)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].LastUsedAt.After(out[j].LastUsedAt) })
+	return out, nil
+}
+
+func (s *SessionStore) Revoke(ctx context.Context, id string) error {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+	sess, ok := s.db.sessions[id]
+	if !ok {
+		return fmt.Errorf("revoke session: %w", sql.ErrNoRows)
+	}
+	if sess.RevokedAt == nil {
+		t := now()
+		sess.RevokedAt = &t
+		s.db.sessions[id] = sess
+	}
+	return nil
+}
+
+func (s *SessionStore) RevokeAllByUser(ctx context.Context, userID string) (int, error) {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+	t := now()
+	n := 0
+	for id, sess := range s.db.sessions {
+		if sess.UserID == userID && sess.RevokedAt == nil {
+			rt := t
+			sess.RevokedAt = &rt
+			s.db.sessions[id] = sess
+			n++
+		}
+	}
+	return n, nil
+}
@@ -0,0 +1,173 @@
+package memstore
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+
+	"votacao/internal/store"
+	"votacao/models"
+)
+
+// UserStore implements store.UserStore in memory.
+type UserStore struct{ db *DB }
+
+func NewUser(db *DB) *UserStore { return &UserStore{db: db} }
+
+func (s *UserStore) Insert(ctx context.Context, u *models.User) (string, error) {
+	if u.ID == "" {
+		u.ID = uuid.NewString()
+	}
+	if u.CreatedAt.IsZero() {
+		u.CreatedAt = time.Now()
+	}
+	// Default role to "user" if not set
+	if u.Role == "" {
+		u.Role = "user"
+	}
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+	for id, o := range s.db.users {
+		if id == u.ID {
+			return "", fmt.Errorf("insert user: id %q: %w", u.ID, ErrUniqueViolation)
+		}
+		if o.u.Email == u.Email {
+			return "", fmt.Errorf("insert user: email %q: %w", u.Email, ErrUniqueViolation)
+		}
+	}
+	cp := *u
+	if u.Bio != nil {
+		bio := *u.Bio
+		cp.Bio = &bio
+	}
+	s.db.users[u.ID] = userRow{u: cp, seq: s.db.next()}
+	return u.ID, nil
+}
+
+// user returns a copy of a stored user; callers hold mu.
+func (db *DB) user(id string) (models.User, bool) {
+	r, ok := db.users[id]
+	if ok && r.u.Bio != nil {
+		bio := *r.u.Bio
+		r.u.Bio = &bio
+	}
+	return r.u, ok
+}
+
+func (s *UserStore) GetByID(ctx context.Context, id string) (*models.User, error) {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+	u, ok := s.db.user(id)
+	if !ok {
+		return nil, nil
+	}
+	return &u, nil
+}
+
+func (s *UserStore) GetByEmail(ctx context.Context, email string) (*models.User, error) {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+	for id, r := range s.db.users {
+		if r.u.Email == email {
+			u, _ := s.db.user(id)
+			return &u, nil
+		}
+	}
+	return nil, nil
+}
+
+// List returns up to 100 users, newest first.
+func (s *UserStore) List(ctx context.Context) ([]models.User, error) {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+	rows := make([]userRow, 0, len(s.db.users))
+	for _, r := range s.db.users {
+		rows = append(rows, r)
+	}
+	sort.Slice(rows, func(i, j int) bool {
+		if !rows[i].u.CreatedAt.Equal(rows[j].u.CreatedAt) {
+			return rows[i].u.CreatedAt.After(rows[j].u.CreatedAt)
+		}
+		return rows[i].seq > rows[j].seq
+	})
+	if len(rows) > listLimit {
+		rows = rows[:listLimit]
+	}
+	out := make([]models.User, 0, len(rows))
+	for _, r := range rows {
+		u, _ := s.db.user(r.u.ID)
+		out = append(out, u)
+	}
+	return out, nil
+}
+
+func (s *UserStore) SetRole(ctx context.Context, id, role string) error {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+	r, ok := s.db.users[id]
+	if !ok {
+		return fmt.Errorf("set role: %w", sql.ErrNoRows)
+	}
+	r.u.Role = role
+	s.db.users[id] = r
+	return nil
+}
+
+// PromoteFirstAdmin promotes the user only while no user has the admin role.
+func (s *UserStore) PromoteFirstAdmin(ctx context.Context, id string) (bool, error) {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+	r, ok := s.db.users[id]
+	if !ok {
+		return false, nil
+	}
+	for _, o := range s.db.users {
+		if o.u.Role == "admin" {
+			return false, nil
+		}
+	}
+	r.u.Role = "admin"
+	s.db.users[id] = r
+	return true, nil
+}
+
+type userKey struct {
+	CreatedAt time.Time `json:"created_at"`
+	Seq       int64     `json:"seq"`
+}
+
+// ListPage returns users newest first, one page at a time.
+func (s *UserStore) ListPage(ctx context.Context, f store.UserFilter, p store.Page) ([]models.User, string, error) {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+	var rows []userRow
+	for _, r := range s.db.users {
+		if f.NicknamePrefix == "" || hasPrefixFold(r.u.Nickname, f.NicknamePrefix) {
+			rows = append(rows, r)
+		}
+	}
+	sort.Slice(rows, func(i, j int) bool {
+		if !rows[i].u.CreatedAt.Equal(rows[j].u.CreatedAt) {
+			return rows[i].u.CreatedAt.After(rows[j].u.CreatedAt)
+		}
+		return rows[i].seq > rows[j].seq
+	})
+	rows, next, err := page(rows, p,
+		func(r userRow) userKey { return userKey{CreatedAt: r.u.CreatedAt, Seq: r.seq} },
+		func(r userRow, k userKey) bool {
+			return r.u.CreatedAt.Before(k.CreatedAt) || (r.u.CreatedAt.Equal(k.CreatedAt) && r.seq < k.Seq)
+		})
+	if err != nil {
+		return nil, "", err
+	}
+	out := make([]models.User, 0, len(rows))
+	for _, r := range rows {
+		u, _ := s.db.user(r.u.ID)
+		out = append(out, u)
+	}
+	return out, next, nil
+}
@@ -0,0 +1,328 @@
+package memstore
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"votacao/internal/store"
+	"votacao/models"
+)
+
+// VoteStore implements store.VoteStore in memory.
+type VoteStore struct{ db *DB }
+
+func NewVote(db *DB) *VoteStore { return &VoteStore{db: db} }
+
+// Insert creates or updates the user's vote in the category, like the SQL
+// upsert, and appends a vote event for every new or changed pick.
+func (s *VoteStore) Insert(ctx context.Context, v *models.Vote) (int64, string, error) {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+	if err := s.db.checkVote(v); err != nil {
+		return 0, "", err
+	}
+	id, action := s.db.upsertVote(v)
+	return id, action, nil
+}
+
+// InsertBallot upserts the votes like Insert; it checks them all first, so
+// either all are saved or none.
+func (s *VoteStore) InsertBallot(ctx context.Context, votes []models.Vote) ([]string, error) {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+	for i := range votes {
+		if err := s.db.checkVote(&votes[i]); err != nil {
+			return nil, err
+		}
+	}
+	actions := make([]string, len(votes))
+	for i := range votes {
+		votes[i].ID, actions[i] = s.db.upsertVote(&votes[i])
+	}
+	return actions, nil
+}
+
+// checkVote enforces the foreign keys of a vote; callers hold mu.
+func (db *DB) checkVote(v *models.Vote) error {
+	if _, ok := db.users[v.UserID]; !ok {
+		return fmt.Errorf("upsert vote: user %q: %w", v.UserID, ErrForeignKeyViolation)
+	}
+	if _, ok := db.nominees[v.NominatedID]; !ok {
+		return fmt.Errorf("upsert vote: nominee %q: %w", v.NominatedID, ErrForeignKeyViolation)
+	}
+	if _, ok := db.categories[v.CategoryID]; !ok {
+		return fmt.Errorf("upsert vote: category %q: %w", v.CategoryID, ErrForeignKeyViolation)
+	}
+	return nil
+}
+
+// upsertVote saves a checked vote and returns its id and models.VoteCreated,
+// models.VoteChanged or store.VoteUnchanged; callers hold mu.
+func (db *DB) upsertVote(v *models.Vote) (int64, string) {
+	t := now()
+	var prev *voteRow
+	for id, r := range db.votes {
+		if r.v.UserID == v.UserID && r.v.CategoryID == v.CategoryID {
+			r := db.votes[id]
+			prev = &r
+			break
+		}
+	}
+	if prev == nil {
+		db.nextVoteID++
+		id := db.nextVoteID
+		db.votes[id] = voteRow{
+			v:   models.Vote{ID: id, UserID: v.UserID, NominatedID: v.NominatedID, CategoryID: v.CategoryID, CreatedAt: t},
+			seq: db.next(),
+		}
+		db.appendEvent(id, v, "", models.VoteCreated, t)
+		return id, models.VoteCreated
+	}
+
+	previous := prev.v.NominatedID
+	prev.v.NominatedID, prev.v.CreatedAt, prev.seq = v.NominatedID, t, db.next()
+	db.votes[prev.v.ID] = *prev
+	// re-submitting the same pick is not a change
+	if previous == v.NominatedID {
+		return prev.v.ID, store.VoteUnchanged
+	}
+	db.appendEvent(prev.v.ID, v, previous, models.VoteChanged, t)
+	return prev.v.ID, models.VoteChanged
+}
+
+func (db *DB) appendEvent(voteID int64, v *models.Vote, previous, action string, at time.Time) {
+	db.events = append(db.events, models.VoteEvent{
+		ID:                  int64(len(db.events) + 1),
+		VoteID:              voteID,
+		UserID:              v.UserID,
+		CategoryID:          v.CategoryID,
+		NominatedID:         v.NominatedID,
+		PreviousNominatedID: previous,
+		Action:              action,
+		CreatedAt:           at,
+	})
+}
+
+// ListEvents returns the user's vote history in the ceremony, oldest first.
+func (s *VoteStore) ListEvents(ctx context.Context, userID, ceremonyID string) ([]models.VoteEvent, error) {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+	out := make([]models.VoteEvent, 0)
+	for _, e := range s.db.events {
+		if e.UserID == userID && s.db.categories[e.CategoryID].CeremonyID == ceremonyID {
+			out = append(out, e)
+		}
+	}
+	return out, nil
+}
+
+func (s *VoteStore) Get(ctx context.Context, id int64) (*models.Vote, error) {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+	r, ok := s.db.votes[id]
+	if !ok {
+		return nil, nil
+	}
+	return &r.v, nil
+}
+
+// ListByUser returns up to 100 of the user's votes in the ceremony, most recent first.
+func (s *VoteStore) ListByUser(ctx context.Context, userID, ceremonyID string) ([]models.Vote, error) {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+	rows := make([]voteRow, 0)
+	for _, r := range s.db.votes {
+		if r.v.UserID == userID && s.db.inCeremony(r.v, ceremonyID) {
+			rows = append(rows, r)
+		}
+	}
+	sort.Slice(rows, func(i, j int) bool { return rows[i].seq > rows[j].seq })
+	if len(rows) > listLimit {
+		rows = rows[:listLimit]
+	}
+	out := make([]models.Vote, 0, len(rows))
+	for _, r := range rows {
+		out = append(out, r.v)
+	}
+	return out, nil
+}
+
+func (db *DB) inCeremony(v models.Vote, ceremonyID string) bool {
+	c, ok := db.categories[v.CategoryID]
+	return ok && c.CeremonyID == ceremonyID
+}
+
+// isWinner reports whether the nominee has been declared a winner; callers hold mu.
+func (db *DB) isWinner(nominatedID string) bool {
+	for _, w := range db.winners {
+		if w.w.NominatedID == nominatedID {
+			return true
+		}
+	}
+	return false
+}
+
+// GetUserScore returns (points, max_points) for the user in the ceremony.
+// Each correct pick is worth its category's points.
+func (s *VoteStore) GetUserScore(ctx context.Context, userID, ceremonyID string) (int, int, error) {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+	var points, maxPoints int
+	for _, sv := range s.db.scoredVotes(userID, ceremonyID) {
+		points += sv.Points
+		maxPoints += sv.Weight
+	}
+	return points, maxPoints, nil
+}
+
+// ScoredVotes returns the user's votes in the ceremony with their weight and
+// earned points.
+func (s *VoteStore) ScoredVotes(ctx context.Context, userID, ceremonyID string) ([]store.ScoredVote, error) {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+	return s.db.scoredVotes(userID, ceremonyID), nil
+}
+
+func (db *DB) scoredVotes(userID, ceremonyID string) []store.ScoredVote {
+	out := make([]store.ScoredVote, 0)
+	for _, r := range db.votes {
+		if r.v.UserID == userID && db.inCeremony(r.v, ceremonyID) {
+			out = append(out, db.scoreVote(r.v))
+		}
+	}
+	return out
+}
+
+// scoreVote is the scoring rule of the SQL store's scoredVotesQuery: a pick
+// that won earns its category's points. Callers hold mu.
+func (db *DB) scoreVote(v models.Vote) store.ScoredVote {
+	sv := store.ScoredVote{CategoryID: v.CategoryID, NominatedID: v.NominatedID, Weight: db.categories[v.CategoryID].Points}
+	if db.isWinner(v.NominatedID) {
+		sv.Correct, sv.Points = true, sv.Weight
+	}
+	return sv
+}
+
+// GetAllScores returns scores for all users who have voted in the ceremony,
+// ordered by points, then correct votes, then total votes (descending).
+func (s *VoteStore) GetAllScores(ctx context.Context, ceremonyID string) ([]store.UserScore, error) {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+	return s.db.scores(ceremonyID, func(string) bool { return true }), nil
+}
+
+// GetLeagueScores is GetAllScores restricted to the members of a league.
+func (s *VoteStore) GetLeagueScores(ctx context.Context, leagueID, ceremonyID string) ([]store.UserScore, error) {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+	members := s.db.members[leagueID]
+	return s.db.scores(ceremonyID, func(userID string) bool { return members[userID] }), nil
+}
+
+func (db *DB) scores(ceremonyID string, include func(userID string) bool) []store.UserScore {
+	byUser := make(map[string]*store.UserScore)
+	for _, r := range db.votes {
+		if !db.inCeremony(r.v, ceremonyID) || !include(r.v.UserID) {
+			continue
+		}
+		u, ok := db.users[r.v.UserID]
+		if !ok {
+			continue
+		}
+		sc := byUser[r.v.UserID]
+		if sc == nil {
+			sc = &store.UserScore{UserID: u.u.ID, Nickname: u.u.Nickname}
+			byUser[r.v.UserID] = sc
+		}
+		sv := db.scoreVote(r.v)
+		sc.TotalVotes++
+		sc.MaxPoints += sv.Weight
+		sc.Points += sv.Points
+		if sv.Correct {
+			sc.CorrectVotes++
+		}
+	}
+	var out []store.UserScore
+	for _, sc := range byUser {
+		out = append(out, *sc)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		a, b := out[i], out[j]
+		if a.Points != b.Points {
+			return a.Points > b.Points
+		}
+		if a.CorrectVotes != b.CorrectVotes {
+			return a.CorrectVotes > b.CorrectVotes
+		}
+		if a.TotalVotes != b.TotalVotes {
+			return a.TotalVotes > b.TotalVotes
+		}
+		return a.UserID < b.UserID
+	})
+	return out
+}
+
+// ListPage returns votes most recent first, one page at a time.
+func (s *VoteStore) ListPage(ctx context.Context, f store.VoteFilter, p store.Page) ([]models.Vote, string, error) {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+	var rows []voteRow
+	for _, r := range s.db.votes {
+		if f.UserID != "" && r.v.UserID != f.UserID {
+			continue
+		}
+		if (f.CeremonyID != "" && !s.db.inCeremony(r.v, f.CeremonyID)) || (f.CategoryID != "" && r.v.CategoryID != f.CategoryID) {
+			continue
+		}
+		rows = append(rows, r)
+	}
+	sort.Slice(rows, func(i, j int) bool { return rows[i].seq > rows[j].seq })
+	rows, next, err := page(rows, p,
+		func(r voteRow) seqKey { return seqKey{Seq: r.seq} },
+		func(r voteRow, k seqKey) bool { return r.seq < k.Seq })
+	if err != nil {
+		return nil, "", err
+	}
+	out := make([]models.Vote, 0, len(rows))
+	for _, r := range rows {
+		out = append(out, r.v)
+	}
+	return out, next, nil
+}
+
+type scoreKey struct {
+	Points       int    `json:"p"`
+	CorrectVotes int    `json:"c"`
+	TotalVotes   int    `json:"t"`
+	UserID       string `json:"u"`
+}
+
+// ScoresPage returns the ceremony's scores in GetAllScores order, one page at a time.
+func (s *VoteStore) ScoresPage(ctx context.Context, f store.ScoreFilter, p store.Page) ([]store.UserScore, string, error) {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+	var rows []store.UserScore
+	for _, sc := range s.db.scores(f.CeremonyID, func(string) bool { return true }) {
+		if f.NicknamePrefix == "" || hasPrefixFold(sc.Nickname, f.NicknamePrefix) {
+			rows = append(rows, sc)
+		}
+	}
+	return page(rows, p,
+		func(sc store.UserScore) scoreKey {
+			return scoreKey{Points: sc.Points, CorrectVotes: sc.CorrectVotes, TotalVotes: sc.TotalVotes, UserID: sc.UserID}
+		},
+		func(sc store.UserScore, k scoreKey) bool {
+			if sc.Points != k.Points {
+				return sc.Points < k.Points
+			}
+			if sc.CorrectVotes != k.CorrectVotes {
+				return sc.CorrectVotes < k.CorrectVotes
+			}
+			if sc.TotalVotes != k.TotalVotes {
+				return sc.TotalVotes < k.TotalVotes
+			}
+			return sc.UserID > k.UserID
+		})
+}
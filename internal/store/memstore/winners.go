@@ -0,0 +1,78 @@
+package memstore
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/google/uuid"
+
+	"votacao/models"
+)
+
+// WinnerStore implements store.WinnerStore in memory.
+type WinnerStore struct{ db *DB }
+
+func NewWinner(db *DB) *WinnerStore { return &WinnerStore{db: db} }
+
+func (s *WinnerStore) Insert(ctx context.Context, w *models.Winner) (string, error) {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+	if _, ok := s.db.nominees[w.NominatedID]; !ok {
+		return "", fmt.Errorf("insert winner: nominee %q: %w", w.NominatedID, ErrForeignKeyViolation)
+	}
+	if s.db.isWinner(w.NominatedID) {
+		return "", fmt.Errorf("insert winner: nominee %q: %w", w.NominatedID, ErrUniqueViolation)
+	}
+	id := uuid.NewString()
+	s.db.winners[id] = winnerRow{w: models.Winner{ID: id, NominatedID: w.NominatedID}, seq: s.db.next()}
+	return id, nil
+}
+
+func (s *WinnerStore) Get(ctx context.Context, id string) (*models.Winner, error) {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+	r, ok := s.db.winners[id]
+	if !ok {
+		return nil, nil
+	}
+	return &r.w, nil
+}
+
+func (s *WinnerStore) GetByNominated(ctx context.Context, nominatedID string) (*models.Winner, error) {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+	for _, r := range s.db.winners {
+		if r.w.NominatedID == nominatedID {
+			w := r.w
+			return &w, nil
+		}
+	}
+	return nil, nil
+}
+
+// List returns the winners whose category belongs to the ceremony.
+func (s *WinnerStore) List(ctx context.Context, ceremonyID string) ([]models.Winner, error) {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+	var rows []winnerRow
+	for _, r := range s.db.winners {
+		n, ok := s.db.nominees[r.w.NominatedID]
+		if ok && s.db.categories[n.n.CategoryID].CeremonyID == ceremonyID {
+			rows = append(rows, r)
+		}
+	}
+	sort.Slice(rows, func(i, j int) bool { return rows[i].seq < rows[j].seq })
+	var out []models.Winner
+	for _, r := range rows {
+		out = append(out, r.w)
+	}
+	return out, nil
+}
+
+func (s *WinnerStore) Delete(ctx context.Context, id string) error {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+	delete(s.db.winners, id)
+	return nil
+}
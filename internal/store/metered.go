@@ -0,0 +1,397 @@
+package store
+
+import (
+	"context"
+	"time"
+
+	"votacao/internal/metrics"
+	"votacao/models"
+)
+
+// queryDuration is the time spent in each store method, which for the SQL
+// stores is the time of its queries.
+var queryDuration = metrics.Default.NewHistogramVec("votacao_store_duration_seconds",
+	"Time spent in store methods, by method.", metrics.DefBuckets, "method")
+
+func observe(method string, start time.Time) {
+	queryDuration.Observe(time.Since(start).Seconds(), method)
+}
+
+// The Metered* constructors wrap a store so that every method call is timed
+// in votacao_store_duration_seconds{method="<Interface>.<Method>"}.
+
+type meteredMovie struct{ next MovieStore }
+
+func MeteredMovie(s MovieStore) MovieStore { return meteredMovie{s} }
+
+func (st meteredMovie) Insert(ctx context.Context, m *models.Movie) (string, error) {
+	defer observe("MovieStore.Insert", time.Now())
+	return st.next.Insert(ctx, m)
+}
+
+func (st meteredMovie) Get(ctx context.Context, id string) (*models.Movie, error) {
+	defer observe("MovieStore.Get", time.Now())
+	return st.next.Get(ctx, id)
+}
+
+func (st meteredMovie) GetMany(ctx context.Context, ids []string) ([]models.Movie, error) {
+	defer observe("MovieStore.GetMany", time.Now())
+	return st.next.GetMany(ctx, ids)
+}
+
+func (st meteredMovie) List(ctx context.Context) ([]models.Movie, error) {
+	defer observe("MovieStore.List", time.Now())
+	return st.next.List(ctx)
+}
+
+func (st meteredMovie) ListPage(ctx context.Context, f MovieFilter, p Page) ([]models.Movie, string, error) {
+	defer observe("MovieStore.ListPage", time.Now())
+	return st.next.ListPage(ctx, f, p)
+}
+
+func (st meteredMovie) InsertMany(ctx context.Context, ms []models.Movie) ([]string, error) {
+	defer observe("MovieStore.InsertMany", time.Now())
+	return st.next.InsertMany(ctx, ms)
+}
+
+func (st meteredMovie) Update(ctx context.Context, m *models.Movie) error {
+	defer observe("MovieStore.Update", time.Now())
+	return st.next.Update(ctx, m)
+}
+
+func (st meteredMovie) Delete(ctx context.Context, id string, force bool) error {
+	defer observe("MovieStore.Delete", time.Now())
+	return st.next.Delete(ctx, id, force)
+}
+
+type meteredCeremony struct{ next CeremonyStore }
+
+func MeteredCeremony(s CeremonyStore) CeremonyStore { return meteredCeremony{s} }
+
+func (st meteredCeremony) Insert(ctx context.Context, c *models.Ceremony) (string, error) {
+	defer observe("CeremonyStore.Insert", time.Now())
+	return st.next.Insert(ctx, c)
+}
+
+func (st meteredCeremony) Get(ctx context.Context, id string) (*models.Ceremony, error) {
+	defer observe("CeremonyStore.Get", time.Now())
+	return st.next.Get(ctx, id)
+}
+
+func (st meteredCeremony) GetActive(ctx context.Context) (*models.Ceremony, error) {
+	defer observe("CeremonyStore.GetActive", time.Now())
+	return st.next.GetActive(ctx)
+}
+
+func (st meteredCeremony) List(ctx context.Context) ([]models.Ceremony, error) {
+	defer observe("CeremonyStore.List", time.Now())
+	return st.next.List(ctx)
+}
+
+func (st meteredCeremony) SetActive(ctx context.Context, id string) error {
+	defer observe("CeremonyStore.SetActive", time.Now())
+	return st.next.SetActive(ctx, id)
+}
+
+type meteredCategory struct{ next CategoryStore }
+
+func MeteredCategory(s CategoryStore) CategoryStore { return meteredCategory{s} }
+
+func (st meteredCategory) Insert(ctx context.Context, c *models.Category) (string, error) {
+	defer observe("CategoryStore.Insert", time.Now())
+	return st.next.Insert(ctx, c)
+}
+
+func (st meteredCategory) Get(ctx context.Context, id string) (*models.Category, error) {
+	defer observe("CategoryStore.Get", time.Now())
+	return st.next.Get(ctx, id)
+}
+
+func (st meteredCategory) List(ctx context.Context, ceremonyID string) ([]models.Category, error) {
+	defer observe("CategoryStore.List", time.Now())
+	return st.next.List(ctx, ceremonyID)
+}
+
+func (st meteredCategory) ListPage(ctx context.Context, f CategoryFilter, p Page) ([]models.Category, string, error) {
+	defer observe("CategoryStore.ListPage", time.Now())
+	return st.next.ListPage(ctx, f, p)
+}
+
+func (st meteredCategory) InsertMany(ctx context.Context, cs []models.Category) ([]string, error) {
+	defer observe("CategoryStore.InsertMany", time.Now())
+	return st.next.InsertMany(ctx, cs)
+}
+
+func (st meteredCategory) Update(ctx context.Context, c *models.Category) error {
+	defer observe("CategoryStore.Update", time.Now())
+	return st.next.Update(ctx, c)
+}
+
+func (st meteredCategory) SetPoints(ctx context.Context, ceremonyID string, points map[string]int) error {
+	defer observe("CategoryStore.SetPoints", time.Now())
+	return st.next.SetPoints(ctx, ceremonyID, points)
+}
+
+func (st meteredCategory) SetLock(ctx context.Context, id string, locked bool, locksAt *time.Time) error {
+	defer observe("CategoryStore.SetLock", time.Now())
+	return st.next.SetLock(ctx, id, locked, locksAt)
+}
+
+func (st meteredCategory) Delete(ctx context.Context, id string, force bool) error {
+	defer observe("CategoryStore.Delete", time.Now())
+	return st.next.Delete(ctx, id, force)
+}
+
+type meteredNominated struct{ next NominatedStore }
+
+func MeteredNominated(s NominatedStore) NominatedStore { return meteredNominated{s} }
+
+func (st meteredNominated) Insert(ctx context.Context, n *models.Nominated) (string, error) {
+	defer observe("NominatedStore.Insert", time.Now())
+	return st.next.Insert(ctx, n)
+}
+
+func (st meteredNominated) InsertMany(ctx context.Context, ns []models.Nominated) ([]string, error) {
+	defer observe("NominatedStore.InsertMany", time.Now())
+	return st.next.InsertMany(ctx, ns)
+}
+
+func (st meteredNominated) Get(ctx context.Context, id string) (*models.Nominated, error) {
+	defer observe("NominatedStore.Get", time.Now())
+	return st.next.Get(ctx, id)
+}
+
+func (st meteredNominated) List(ctx context.Context, ceremonyID string) ([]models.Nominated, error) {
+	defer observe("NominatedStore.List", time.Now())
+	return st.next.List(ctx, ceremonyID)
+}
+
+func (st meteredNominated) ListPage(ctx context.Context, f NominatedFilter, p Page) ([]models.Nominated, string, error) {
+	defer observe("NominatedStore.ListPage", time.Now())
+	return st.next.ListPage(ctx, f, p)
+}
+
+func (st meteredNominated) ListByCategory(ctx context.Context, categoryID string) ([]models.Nominated, error) {
+	defer observe("NominatedStore.ListByCategory", time.Now())
+	return st.next.ListByCategory(ctx, categoryID)
+}
+
+func (st meteredNominated) Update(ctx context.Context, n *models.Nominated) error {
+	defer observe("NominatedStore.Update", time.Now())
+	return st.next.Update(ctx, n)
+}
+
+func (st meteredNominated) Delete(ctx context.Context, id string, force bool) error {
+	defer observe("NominatedStore.Delete", time.Now())
+	return st.next.Delete(ctx, id, force)
+}
+
+type meteredUser struct{ next UserStore }
+
+func MeteredUser(s UserStore) UserStore { return meteredUser{s} }
+
+func (st meteredUser) Insert(ctx context.Context, u *models.User) (string, error) {
+	defer observe("UserStore.Insert", time.Now())
+	return st.next.Insert(ctx, u)
+}
+
+func (st meteredUser) GetByID(ctx context.Context, id string) (*models.User, error) {
+	defer observe("UserStore.GetByID", time.Now())
+	return st.next.GetByID(ctx, id)
+}
+
+func (st meteredUser) GetByEmail(ctx context.Context, email string) (*models.User, error) {
+	defer observe("UserStore.GetByEmail", time.Now())
+	return st.next.GetByEmail(ctx, email)
+}
+
+func (st meteredUser) List(ctx context.Context) ([]models.User, error) {
+	defer observe("UserStore.List", time.Now())
+	return st.next.List(ctx)
+}
+
+func (st meteredUser) ListPage(ctx context.Context, f UserFilter, p Page) ([]models.User, string, error) {
+	defer observe("UserStore.ListPage", time.Now())
+	return st.next.ListPage(ctx, f, p)
+}
+
+func (st meteredUser) SetRole(ctx context.Context, id, role string) error {
+	defer observe("UserStore.SetRole", time.Now())
+	return st.next.SetRole(ctx, id, role)
+}
+
+func (st meteredUser) PromoteFirstAdmin(ctx context.Context, id string) (bool, error) {
+	defer observe("UserStore.PromoteFirstAdmin", time.Now())
+	return st.next.PromoteFirstAdmin(ctx, id)
+}
+
+type meteredVote struct{ next VoteStore }
+
+func MeteredVote(s VoteStore) VoteStore { return meteredVote{s} }
+
+func (st meteredVote) Insert(ctx context.Context, v *models.Vote) (int64, string, error) {
+	defer observe("VoteStore.Insert", time.Now())
+	return st.next.Insert(ctx, v)
+}
+
+func (st meteredVote) InsertBallot(ctx context.Context, votes []models.Vote) ([]string, error) {
+	defer observe("VoteStore.InsertBallot", time.Now())
+	return st.next.InsertBallot(ctx, votes)
+}
+
+func (st meteredVote) ListEvents(ctx context.Context, userID, ceremonyID string) ([]models.VoteEvent, error) {
+	defer observe("VoteStore.ListEvents", time.Now())
+	return st.next.ListEvents(ctx, userID, ceremonyID)
+}
+
+func (st meteredVote) Get(ctx context.Context, id int64) (*models.Vote, error) {
+	defer observe("VoteStore.Get", time.Now())
+	return st.next.Get(ctx, id)
+}
+
+func (st meteredVote) ListByUser(ctx context.Context, userID, ceremonyID string) ([]models.Vote, error) {
+	defer observe("VoteStore.ListByUser", time.Now())
+	return st.next.ListByUser(ctx, userID, ceremonyID)
+}
+
+func (st meteredVote) ListPage(ctx context.Context, f VoteFilter, p Page) ([]models.Vote, string, error) {
+	defer observe("VoteStore.ListPage", time.Now())
+	return st.next.ListPage(ctx, f, p)
+}
+
+func (st meteredVote) GetUserScore(ctx context.Context, userID, ceremonyID string) (int, int, error) {
+	defer observe("VoteStore.GetUserScore", time.Now())
+	return st.next.GetUserScore(ctx, userID, ceremonyID)
+}
+
+func (st meteredVote) ScoredVotes(ctx context.Context, userID, ceremonyID string) ([]ScoredVote, error) {
+	defer observe("VoteStore.ScoredVotes", time.Now())
+	return st.next.ScoredVotes(ctx, userID, ceremonyID)
+}
+
+func (st meteredVote) GetAllScores(ctx context.Context, ceremonyID string) ([]UserScore, error) {
+	defer observe("VoteStore.GetAllScores", time.Now())
+	return st.next.GetAllScores(ctx, ceremonyID)
+}
+
+func (st meteredVote) ScoresPage(ctx context.Context, f ScoreFilter, p Page) ([]UserScore, string, error) {
+	defer observe("VoteStore.ScoresPage", time.Now())
+	return st.next.ScoresPage(ctx, f, p)
+}
+
+func (st meteredVote) GetLeagueScores(ctx context.Context, leagueID, ceremonyID string) ([]UserScore, error) {
+	defer observe("VoteStore.GetLeagueScores", time.Now())
+	return st.next.GetLeagueScores(ctx, leagueID, ceremonyID)
+}
+
+type meteredWinner struct{ next WinnerStore }
+
+func MeteredWinner(s WinnerStore) WinnerStore { return meteredWinner{s} }
+
+func (st meteredWinner) Insert(ctx context.Context, w *models.Winner) (string, error) {
+	defer observe("WinnerStore.Insert", time.Now())
+	return st.next.Insert(ctx, w)
+}
+
+func (st meteredWinner) Get(ctx context.Context, id string) (*models.Winner, error) {
+	defer observe("WinnerStore.Get", time.Now())
+	return st.next.Get(ctx, id)
+}
+
+func (st meteredWinner) GetByNominated(ctx context.Context, nominatedID string) (*models.Winner, error) {
+	defer observe("WinnerStore.GetByNominated", time.Now())
+	return st.next.GetByNominated(ctx, nominatedID)
+}
+
+func (st meteredWinner) List(ctx context.Context, ceremonyID string) ([]models.Winner, error) {
+	defer observe("WinnerStore.List", time.Now())
+	return st.next.List(ctx, ceremonyID)
+}
+
+func (st meteredWinner) Delete(ctx context.Context, id string) error {
+	defer observe("WinnerStore.Delete", time.Now())
+	return st.next.Delete(ctx, id)
+}
+
+type meteredLeague struct{ next LeagueStore }
+
+func MeteredLeague(s LeagueStore) LeagueStore { return meteredLeague{s} }
+
+func (st meteredLeague) Insert(ctx context.Context, l *models.League) (string, error) {
+	defer observe("LeagueStore.Insert", time.Now())
+	return st.next.Insert(ctx, l)
+}
+
+func (st meteredLeague) Get(ctx context.Context, id string) (*models.League, error) {
+	defer observe("LeagueStore.Get", time.Now())
+	return st.next.Get(ctx, id)
+}
+
+func (st meteredLeague) GetByInviteCode(ctx context.Context, code string) (*models.League, error) {
+	defer observe("LeagueStore.GetByInviteCode", time.Now())
+	return st.next.GetByInviteCode(ctx, code)
+}
+
+func (st meteredLeague) ListByUser(ctx context.Context, userID string) ([]models.League, error) {
+	defer observe("LeagueStore.ListByUser", time.Now())
+	return st.next.ListByUser(ctx, userID)
+}
+
+func (st meteredLeague) AddMember(ctx context.Context, leagueID, userID string) error {
+	defer observe("LeagueStore.AddMember", time.Now())
+	return st.next.AddMember(ctx, leagueID, userID)
+}
+
+func (st meteredLeague) RemoveMember(ctx context.Context, leagueID, userID string) error {
+	defer observe("LeagueStore.RemoveMember", time.Now())
+	return st.next.RemoveMember(ctx, leagueID, userID)
+}
+
+func (st meteredLeague) IsMember(ctx context.Context, leagueID, userID string) (bool, error) {
+	defer observe("LeagueStore.IsMember", time.Now())
+	return st.next.IsMember(ctx, leagueID, userID)
+}
+
+type meteredSession struct{ next SessionStore }
+
+func MeteredSession(s SessionStore) SessionStore { return meteredSession{s} }
+
+func (st meteredSession) Insert(ctx context.Context, s *models.Session) (string, error) {
+	defer observe("SessionStore.Insert", time.Now())
+	return st.next.Insert(ctx, s)
+}
+
+func (st meteredSession) Get(ctx context.Context, id string) (*models.Session, error) {
+	defer observe("SessionStore.Get", time.Now())
+	return st.next.Get(ctx, id)
+}
+
+func (st meteredSession) GetByTokenHash(ctx context.Context, hash string) (*models.Session, error) {
+	defer observe("SessionStore.GetByTokenHash", time.Now())
+	return st.next.GetByTokenHash(ctx, hash)
+}
+
+func (st meteredSession) Rotate(ctx context.Context, id, oldHash, newHash string, expiresAt time.Time) error {
+	defer observe("SessionStore.Rotate", time.Now())
+	return st.next.Rotate(ctx, id, oldHash, newHash, expiresAt)
+}
+
+func (st meteredSession) Touch(ctx context.Context, id string, at time.Time) error {
+	defer observe("SessionStore.Touch", time.Now())
+	return st.next.Touch(ctx, id, at)
+}
+
+func (st meteredSession) ListByUser(ctx context.Context, userID string) ([]models.Session, error) {
+	defer observe("SessionStore.ListByUser", time.Now())
+	return st.next.ListByUser(ctx, userID)
+}
+
+func (st meteredSession) Revoke(ctx context.Context, id string) error {
+	defer observe("SessionStore.Revoke", time.Now())
+	return st.next.Revoke(ctx, id)
+}
+
+func (st meteredSession) RevokeAllByUser(ctx context.Context, userID string) (int, error) {
+	defer observe("SessionStore.RevokeAllByUser", time.Now())
+	return st.next.RevokeAllByUser(ctx, userID)
+}
@@ -0,0 +1,128 @@
+package store
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Page selects one page of a list: up to Limit rows after Cursor, the
+// NextCursor returned with the previous page ("" for the first page).
+type Page struct {
+	Limit  int
+	Cursor string
+}
+
+// Page sizes: DefaultPageLimit when Limit is unset, never more than MaxPageLimit.
+const (
+	DefaultPageLimit = 100
+	MaxPageLimit     = 500
+)
+
+// ErrInvalidCursor is returned by the ListPage methods for a cursor they did not issue.
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// Size returns the number of rows to return for the page.
+func (p Page) Size() int {
+	switch {
+	case p.Limit <= 0:
+		return DefaultPageLimit
+	case p.Limit > MaxPageLimit:
+		return MaxPageLimit
+	}
+	return p.Limit
+}
+
+// EncodeCursor turns the sort key of the last row of a page into an opaque cursor.
+func EncodeCursor(key interface{}) string {
+	b, _ := json.Marshal(key)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// DecodeCursor reads a cursor made by EncodeCursor into key.
+func DecodeCursor(cursor string, key interface{}) error {
+	b, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidCursor, err)
+	}
+	if err := json.Unmarshal(b, key); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidCursor, err)
+	}
+	return nil
+}
+
+// Filters of the ListPage methods; empty fields do not filter.
+type (
+	MovieFilter struct {
+		TitlePrefix string // case-insensitive
+	}
+	CategoryFilter struct {
+		CeremonyID string
+	}
+	NominatedFilter struct {
+		CeremonyID string
+		CategoryID string
+		MovieID    string
+	}
+	UserFilter struct {
+		NicknamePrefix string // case-insensitive
+	}
+	VoteFilter struct {
+		UserID     string
+		CeremonyID string
+		CategoryID string
+	}
+	ScoreFilter struct {
+		CeremonyID     string
+		NicknamePrefix string // case-insensitive
+	}
+)
+
+// conds builds the WHERE clause of a ListPage query; "?" in a condition is
+// replaced by the next $n placeholder.
+type conds struct {
+	where []string
+	args  []interface{}
+}
+
+func (c *conds) add(cond string, args ...interface{}) {
+	for _, a := range args {
+		c.args = append(c.args, a)
+		cond = strings.Replace(cond, "?", fmt.Sprintf("$%d", len(c.args)), 1)
+	}
+	c.where = append(c.where, cond)
+}
+
+// arg adds an argument without a condition and returns its placeholder.
+func (c *conds) arg(a interface{}) string {
+	c.args = append(c.args, a)
+	return fmt.Sprintf("$%d", len(c.args))
+}
+
+func (c *conds) String() string {
+	if len(c.where) == 0 {
+		return ""
+	}
+	return "WHERE " + strings.Join(c.where, " AND ")
+}
+
+// likePrefix returns a LIKE pattern matching strings that start with s.
+func likePrefix(s string) string {
+	r := strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+	return r.Replace(s) + "%"
+}
+
+// nextPage trims rows fetched with LIMIT size+1 to size and returns the
+// cursor of the following page ("" when rows was the last page).
+func nextPage[T any](rows []T, size int, key func(T) interface{}) ([]T, string) {
+	if rows == nil {
+		rows = make([]T, 0)
+	}
+	if len(rows) <= size {
+		return rows, ""
+	}
+	rows = rows[:size]
+	return rows, EncodeCursor(key(rows[size-1]))
+}
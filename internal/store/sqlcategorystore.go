@@ -1,8 +1,10 @@
 package store
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
+	"time"
 
 	"votacao/models"
 )
@@ -14,33 +16,38 @@ type SQLCategoryStore struct {
 
 func NewSQLCategory(db *sql.DB) *SQLCategoryStore { return &SQLCategoryStore{db: db} }
 
-func (s *SQLCategoryStore) Insert(c *models.Category) (string, error) {
+// DefaultCategoryPoints is the weight the API gives categories created
+// without one. The stores save Points as given; 0 is a valid weight.
+const DefaultCategoryPoints = 1
+
+func (s *SQLCategoryStore) Insert(ctx context.Context, c *models.Category) (string, error) {
 	var id string
-	err := s.db.QueryRow("INSERT INTO categories (name, sequence_order) VALUES ($1, $2) RETURNING id", c.Name, c.SequenceOrder).Scan(&id)
+	err := s.db.QueryRowContext(ctx, "INSERT INTO categories (ceremony_id, name, sequence_order, points) VALUES ($1, $2, $3, $4) RETURNING id", c.CeremonyID, c.Name, c.SequenceOrder, c.Points).Scan(&id)
 	if err != nil {
 		return "", fmt.Errorf("insert category: %w", err)
 	}
 	return id, nil
 }
 
-func (s *SQLCategoryStore) InsertMany(cs []models.Category) ([]string, error) {
+func (s *SQLCategoryStore) InsertMany(ctx context.Context, cs []models.Category) ([]string, error) {
 	if len(cs) == 0 {
 		return []string{}, nil
 	}
-	tx, err := s.db.Begin()
+	tx, err := s.db.BeginTx(ctx, nil)
 	if err != nil {
 		return nil, fmt.Errorf("begin tx: %w", err)
 	}
-	stmt, err := tx.Prepare("INSERT INTO categories (name, sequence_order) VALUES ($1, $2) RETURNING id")
+	stmt, err := tx.PrepareContext(ctx, "INSERT INTO categories (ceremony_id, name, sequence_order, points) VALUES ($1, $2, $3, $4) RETURNING id")
 	if err != nil {
 		tx.Rollback()
 		return nil, fmt.Errorf("prepare: %w", err)
 	}
 	defer stmt.Close()
 	ids := make([]string, 0, len(cs))
-	for _, c := range cs {
+	for i := range cs {
+		c := &cs[i]
 		var id string
-		if err := stmt.QueryRow(c.Name, c.SequenceOrder).Scan(&id); err != nil {
+		if err := stmt.QueryRowContext(ctx, c.CeremonyID, c.Name, c.SequenceOrder, c.Points).Scan(&id); err != nil {
 			tx.Rollback()
 			return nil, fmt.Errorf("insert categories: %w", err)
 		}
@@ -52,20 +59,24 @@ func (s *SQLCategoryStore) InsertMany(cs []models.Category) ([]string, error) {
 	return ids, nil
 }
 
-func (s *SQLCategoryStore) Get(id string) (*models.Category, error) {
+func (s *SQLCategoryStore) Get(ctx context.Context, id string) (*models.Category, error) {
 	var c models.Category
-	row := s.db.QueryRow("SELECT id, name, COALESCE(sequence_order, 0) FROM categories WHERE id=$1", id)
-	if err := row.Scan(&c.ID, &c.Name, &c.SequenceOrder); err != nil {
+	var locksAt sql.NullTime
+	row := s.db.QueryRowContext(ctx, "SELECT id, ceremony_id, name, COALESCE(sequence_order, 0), points, locks_at, locked FROM categories WHERE id=$1", id)
+	if err := row.Scan(&c.ID, &c.CeremonyID, &c.Name, &c.SequenceOrder, &c.Points, &locksAt, &c.Locked); err != nil {
 		if err == sql.ErrNoRows {
 			return nil, nil
 		}
 		return nil, fmt.Errorf("get category: %w", err)
 	}
+	if locksAt.Valid {
+		c.LocksAt = &locksAt.Time
+	}
 	return &c, nil
 }
 
-func (s *SQLCategoryStore) List() ([]models.Category, error) {
-	rows, err := s.db.Query("SELECT id, name, COALESCE(sequence_order, 0) FROM categories ORDER BY sequence_order ASC, id DESC LIMIT 100")
+func (s *SQLCategoryStore) List(ctx context.Context, ceremonyID string) ([]models.Category, error) {
+	rows, err := s.db.QueryContext(ctx, "SELECT id, ceremony_id, name, COALESCE(sequence_order, 0), points, locks_at, locked FROM categories WHERE ceremony_id=$1 ORDER BY sequence_order ASC, id DESC LIMIT 100", ceremonyID)
 	if err != nil {
 		return nil, fmt.Errorf("list categories: %w", err)
 	}
@@ -73,10 +84,131 @@ func (s *SQLCategoryStore) List() ([]models.Category, error) {
 	var out []models.Category
 	for rows.Next() {
 		var c models.Category
-		if err := rows.Scan(&c.ID, &c.Name, &c.SequenceOrder); err != nil {
+		var locksAt sql.NullTime
+		if err := rows.Scan(&c.ID, &c.CeremonyID, &c.Name, &c.SequenceOrder, &c.Points, &locksAt, &c.Locked); err != nil {
 			return nil, fmt.Errorf("scan category: %w", err)
 		}
+		if locksAt.Valid {
+			c.LocksAt = &locksAt.Time
+		}
 		out = append(out, c)
 	}
 	return out, nil
 }
+
+// Update changes a category's name, sequence order and points.
+// It returns sql.ErrNoRows if the category does not exist.
+func (s *SQLCategoryStore) Update(ctx context.Context, c *models.Category) error {
+	res, err := s.db.ExecContext(ctx, "UPDATE categories SET name=$1, sequence_order=$2, points=$3 WHERE id=$4", c.Name, c.SequenceOrder, c.Points, c.ID)
+	if err != nil {
+		return fmt.Errorf("update category: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return fmt.Errorf("update category: %w", sql.ErrNoRows)
+	}
+	return nil
+}
+
+// SetPoints updates the points of several categories (category id -> points)
+// of ceremonyID in one transaction, so a new weighting is applied all at once.
+// A category of another ceremony fails it with sql.ErrNoRows.
+func (s *SQLCategoryStore) SetPoints(ctx context.Context, ceremonyID string, points map[string]int) error {
+	if len(points) == 0 {
+		return nil
+	}
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	stmt, err := tx.PrepareContext(ctx, "UPDATE categories SET points=$1 WHERE id=$2 AND ceremony_id=$3")
+	if err != nil {
+		tx.Rollback()
+		return fmt.Errorf("prepare: %w", err)
+	}
+	defer stmt.Close()
+	for id, p := range points {
+		res, err := stmt.ExecContext(ctx, p, id, ceremonyID)
+		if err != nil {
+			tx.Rollback()
+			return fmt.Errorf("set points: %w", err)
+		}
+		if n, _ := res.RowsAffected(); n == 0 {
+			tx.Rollback()
+			return fmt.Errorf("set points for category %s: %w", id, sql.ErrNoRows)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit: %w", err)
+	}
+	return nil
+}
+
+// SetLock sets the locked flag and the scheduled lock time (nil clears it).
+// It returns sql.ErrNoRows if the category does not exist.
+func (s *SQLCategoryStore) SetLock(ctx context.Context, id string, locked bool, locksAt *time.Time) error {
+	res, err := s.db.ExecContext(ctx, "UPDATE categories SET locked=$1, locks_at=$2 WHERE id=$3", locked, locksAt, id)
+	if err != nil {
+		return fmt.Errorf("set category lock: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return fmt.Errorf("set category lock: %w", sql.ErrNoRows)
+	}
+	return nil
+}
+
+// Delete removes the category; its nominees, votes and winners go with it
+// through ON DELETE CASCADE, so that requires force when any exist.
+func (s *SQLCategoryStore) Delete(ctx context.Context, id string, force bool) error {
+	return deleteUnlessInUse(ctx, s.db, "category",
+		"SELECT id FROM categories WHERE id=$1 FOR UPDATE",
+		`SELECT
+  (SELECT count(*) FROM votes WHERE category_id = $1),
+  (SELECT count(*) FROM winners w INNER JOIN nominees n ON n.id = w.nominated_id WHERE n.category_id = $1)`,
+		"DELETE FROM categories WHERE id=$1", id, force)
+}
+
+type categoryCursor struct {
+	SequenceOrder int    `json:"seq"`
+	ID            string `json:"id"`
+}
+
+// ListPage returns categories by sequence order, like List, one page at a time.
+func (s *SQLCategoryStore) ListPage(ctx context.Context, f CategoryFilter, p Page) ([]models.Category, string, error) {
+	var c conds
+	if f.CeremonyID != "" {
+		c.add("ceremony_id = ?", f.CeremonyID)
+	}
+	if p.Cursor != "" {
+		var key categoryCursor
+		if err := DecodeCursor(p.Cursor, &key); err != nil {
+			return nil, "", err
+		}
+		c.add("(COALESCE(sequence_order, 0) > ? OR (COALESCE(sequence_order, 0) = ? AND id < ?))", key.SequenceOrder, key.SequenceOrder, key.ID)
+	}
+	limit := c.arg(p.Size() + 1)
+	rows, err := s.db.QueryContext(ctx, "SELECT id, ceremony_id, name, COALESCE(sequence_order, 0), points, locks_at, locked FROM categories "+c.String()+
+		" ORDER BY COALESCE(sequence_order, 0) ASC, id DESC LIMIT "+limit, c.args...)
+	if err != nil {
+		return nil, "", fmt.Errorf("list categories: %w", err)
+	}
+	defer rows.Close()
+	var out []models.Category
+	for rows.Next() {
+		var cat models.Category
+		var locksAt sql.NullTime
+		if err := rows.Scan(&cat.ID, &cat.CeremonyID, &cat.Name, &cat.SequenceOrder, &cat.Points, &locksAt, &cat.Locked); err != nil {
+			return nil, "", fmt.Errorf("scan category: %w", err)
+		}
+		if locksAt.Valid {
+			cat.LocksAt = &locksAt.Time
+		}
+		out = append(out, cat)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, "", fmt.Errorf("list categories: %w", err)
+	}
+	out, next := nextPage(out, p.Size(), func(cat models.Category) interface{} {
+		return categoryCursor{SequenceOrder: cat.SequenceOrder, ID: cat.ID}
+	})
+	return out, next, nil
+}
@@ -0,0 +1,96 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"votacao/models"
+)
+
+// SQLCeremonyStore implements CeremonyStore using a Postgres DB.
+type SQLCeremonyStore struct {
+	db *sql.DB
+}
+
+func NewSQLCeremony(db *sql.DB) *SQLCeremonyStore { return &SQLCeremonyStore{db: db} }
+
+func (s *SQLCeremonyStore) Insert(ctx context.Context, c *models.Ceremony) (string, error) {
+	if c.Status == "" {
+		c.Status = models.CeremonyUpcoming
+	}
+	var id string
+	err := s.db.QueryRowContext(ctx, "INSERT INTO ceremonies (year, name, deadline, status) VALUES ($1, $2, $3, $4) RETURNING id",
+		c.Year, c.Name, c.Deadline, c.Status).Scan(&id)
+	if err != nil {
+		return "", fmt.Errorf("insert ceremony: %w", err)
+	}
+	return id, nil
+}
+
+func (s *SQLCeremonyStore) Get(ctx context.Context, id string) (*models.Ceremony, error) {
+	var c models.Ceremony
+	row := s.db.QueryRowContext(ctx, "SELECT id, year, name, deadline, status FROM ceremonies WHERE id=$1", id)
+	if err := row.Scan(&c.ID, &c.Year, &c.Name, &c.Deadline, &c.Status); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("get ceremony: %w", err)
+	}
+	return &c, nil
+}
+
+func (s *SQLCeremonyStore) GetActive(ctx context.Context) (*models.Ceremony, error) {
+	var c models.Ceremony
+	row := s.db.QueryRowContext(ctx, "SELECT id, year, name, deadline, status FROM ceremonies WHERE status=$1", models.CeremonyActive)
+	if err := row.Scan(&c.ID, &c.Year, &c.Name, &c.Deadline, &c.Status); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("get active ceremony: %w", err)
+	}
+	return &c, nil
+}
+
+func (s *SQLCeremonyStore) List(ctx context.Context) ([]models.Ceremony, error) {
+	rows, err := s.db.QueryContext(ctx, "SELECT id, year, name, deadline, status FROM ceremonies ORDER BY year DESC")
+	if err != nil {
+		return nil, fmt.Errorf("list ceremonies: %w", err)
+	}
+	defer rows.Close()
+	out := make([]models.Ceremony, 0)
+	for rows.Next() {
+		var c models.Ceremony
+		if err := rows.Scan(&c.ID, &c.Year, &c.Name, &c.Deadline, &c.Status); err != nil {
+			return nil, fmt.Errorf("scan ceremony: %w", err)
+		}
+		out = append(out, c)
+	}
+	return out, rows.Err()
+}
+
+// SetActive archives the currently active ceremony and activates the given one
+// in a single transaction.
+func (s *SQLCeremonyStore) SetActive(ctx context.Context, id string) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, "UPDATE ceremonies SET status=$1 WHERE status=$2 AND id<>$3", models.CeremonyArchived, models.CeremonyActive, id); err != nil {
+		tx.Rollback()
+		return fmt.Errorf("archive active ceremony: %w", err)
+	}
+	res, err := tx.ExecContext(ctx, "UPDATE ceremonies SET status=$1 WHERE id=$2", models.CeremonyActive, id)
+	if err != nil {
+		tx.Rollback()
+		return fmt.Errorf("activate ceremony: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		tx.Rollback()
+		return fmt.Errorf("activate ceremony: %w", sql.ErrNoRows)
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,135 @@
+package store
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/base32"
+	"fmt"
+	"strings"
+	"time"
+
+	"votacao/models"
+)
+
+// SQLLeagueStore implements LeagueStore using a Postgres DB.
+type SQLLeagueStore struct {
+	db *sql.DB
+}
+
+func NewSQLLeague(db *sql.DB) *SQLLeagueStore { return &SQLLeagueStore{db: db} }
+
+// newInviteCode returns a short random code that is easy to share.
+func newInviteCode() (string, error) {
+	b := make([]byte, 5)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return strings.ToUpper(base32.StdEncoding.EncodeToString(b)), nil
+}
+
+// Insert creates the league and adds its owner as the first member in one
+// transaction. An invite code is generated when none is set.
+func (s *SQLLeagueStore) Insert(ctx context.Context, l *models.League) (string, error) {
+	if l.InviteCode == "" {
+		code, err := newInviteCode()
+		if err != nil {
+			return "", fmt.Errorf("generate invite code: %w", err)
+		}
+		l.InviteCode = code
+	}
+	if l.CreatedAt.IsZero() {
+		l.CreatedAt = time.Now()
+	}
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return "", fmt.Errorf("begin tx: %w", err)
+	}
+	var id string
+	err = tx.QueryRowContext(ctx, "INSERT INTO leagues (name, owner_id, invite_code, created_at) VALUES ($1, $2, $3, $4) RETURNING id",
+		l.Name, l.OwnerID, l.InviteCode, l.CreatedAt).Scan(&id)
+	if err != nil {
+		tx.Rollback()
+		return "", fmt.Errorf("insert league: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, "INSERT INTO league_members (league_id, user_id) VALUES ($1, $2)", id, l.OwnerID); err != nil {
+		tx.Rollback()
+		return "", fmt.Errorf("insert league owner: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return "", fmt.Errorf("commit: %w", err)
+	}
+	return id, nil
+}
+
+func (s *SQLLeagueStore) Get(ctx context.Context, id string) (*models.League, error) {
+	var l models.League
+	row := s.db.QueryRowContext(ctx, "SELECT id, name, owner_id, invite_code, created_at FROM leagues WHERE id=$1", id)
+	if err := row.Scan(&l.ID, &l.Name, &l.OwnerID, &l.InviteCode, &l.CreatedAt); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("get league: %w", err)
+	}
+	return &l, nil
+}
+
+func (s *SQLLeagueStore) GetByInviteCode(ctx context.Context, code string) (*models.League, error) {
+	var l models.League
+	row := s.db.QueryRowContext(ctx, "SELECT id, name, owner_id, invite_code, created_at FROM leagues WHERE invite_code=$1", code)
+	if err := row.Scan(&l.ID, &l.Name, &l.OwnerID, &l.InviteCode, &l.CreatedAt); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("get league by invite code: %w", err)
+	}
+	return &l, nil
+}
+
+// ListByUser returns the leagues the user is a member of.
+func (s *SQLLeagueStore) ListByUser(ctx context.Context, userID string) ([]models.League, error) {
+	rows, err := s.db.QueryContext(ctx, `SELECT l.id, l.name, l.owner_id, l.invite_code, l.created_at
+FROM leagues l
+INNER JOIN league_members lm ON lm.league_id = l.id
+WHERE lm.user_id = $1
+ORDER BY l.name ASC`, userID)
+	if err != nil {
+		return nil, fmt.Errorf("list leagues: %w", err)
+	}
+	defer rows.Close()
+	out := make([]models.League, 0)
+	for rows.Next() {
+		var l models.League
+		if err := rows.Scan(&l.ID, &l.Name, &l.OwnerID, &l.InviteCode, &l.CreatedAt); err != nil {
+			return nil, fmt.Errorf("scan league: %w", err)
+		}
+		out = append(out, l)
+	}
+	return out, rows.Err()
+}
+
+// AddMember adds the user to the league; joining twice is a no-op.
+func (s *SQLLeagueStore) AddMember(ctx context.Context, leagueID, userID string) error {
+	_, err := s.db.ExecContext(ctx, "INSERT INTO league_members (league_id, user_id) VALUES ($1, $2) ON CONFLICT DO NOTHING", leagueID, userID)
+	if err != nil {
+		return fmt.Errorf("add league member: %w", err)
+	}
+	return nil
+}
+
+func (s *SQLLeagueStore) RemoveMember(ctx context.Context, leagueID, userID string) error {
+	_, err := s.db.ExecContext(ctx, "DELETE FROM league_members WHERE league_id=$1 AND user_id=$2", leagueID, userID)
+	if err != nil {
+		return fmt.Errorf("remove league member: %w", err)
+	}
+	return nil
+}
+
+func (s *SQLLeagueStore) IsMember(ctx context.Context, leagueID, userID string) (bool, error) {
+	var exists bool
+	err := s.db.QueryRowContext(ctx, "SELECT EXISTS(SELECT 1 FROM league_members WHERE league_id=$1 AND user_id=$2)", leagueID, userID).Scan(&exists)
+	if err != nil {
+		return false, fmt.Errorf("check league member: %w", err)
+	}
+	return exists, nil
+}
@@ -1,9 +1,11 @@
 package store
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
-	"log"
+	"log/slog"
+	"time"
 
 	"votacao/models"
 )
@@ -13,7 +15,7 @@ type SQLNominatedStore struct{ db *sql.DB }
 
 func NewSQLNominated(db *sql.DB) *SQLNominatedStore { return &SQLNominatedStore{db: db} }
 
-func (s *SQLNominatedStore) Insert(n *models.Nominated) (string, error) {
+func (s *SQLNominatedStore) Insert(ctx context.Context, n *models.Nominated) (string, error) {
 	var id string
 	var name interface{} = n.Name
 	if n.Name == "" {
@@ -21,35 +23,35 @@ func (s *SQLNominatedStore) Insert(n *models.Nominated) (string, error) {
 	}
 	// If UrlImage is empty, omit it from the INSERT so DB default applies.
 	if n.UrlImage == "" {
-		err := s.db.QueryRow("INSERT INTO nominees (movie_id, category_id, nominee_name) VALUES ($1,$2,$3) RETURNING id", n.MovieID, n.CategoryID, name).Scan(&id)
+		err := s.db.QueryRowContext(ctx, "INSERT INTO nominees (movie_id, category_id, nominee_name) VALUES ($1,$2,$3) RETURNING id", n.MovieID, n.CategoryID, name).Scan(&id)
 		if err != nil {
 			return "", fmt.Errorf("insert nominated: %w", err)
 		}
 		return id, nil
 	}
 	// url provided -> include it in INSERT
-	err := s.db.QueryRow("INSERT INTO nominees (movie_id, category_id, nominee_name, url_image) VALUES ($1,$2,$3,$4) RETURNING id", n.MovieID, n.CategoryID, name, n.UrlImage).Scan(&id)
+	err := s.db.QueryRowContext(ctx, "INSERT INTO nominees (movie_id, category_id, nominee_name, url_image) VALUES ($1,$2,$3,$4) RETURNING id", n.MovieID, n.CategoryID, name, n.UrlImage).Scan(&id)
 	if err != nil {
 		return "", fmt.Errorf("insert nominated: %w", err)
 	}
 	return id, nil
 }
 
-func (s *SQLNominatedStore) InsertMany(ns []models.Nominated) ([]string, error) {
+func (s *SQLNominatedStore) InsertMany(ctx context.Context, ns []models.Nominated) ([]string, error) {
 	if len(ns) == 0 {
 		return []string{}, nil
 	}
-	tx, err := s.db.Begin()
+	tx, err := s.db.BeginTx(ctx, nil)
 	if err != nil {
 		return nil, fmt.Errorf("begin tx: %w", err)
 	}
-	stmtWithUrl, err := tx.Prepare("INSERT INTO nominees (movie_id, category_id, nominee_name, url_image) VALUES ($1,$2,$3,$4) RETURNING id")
+	stmtWithUrl, err := tx.PrepareContext(ctx, "INSERT INTO nominees (movie_id, category_id, nominee_name, url_image) VALUES ($1,$2,$3,$4) RETURNING id")
 	if err != nil {
 		tx.Rollback()
 		return nil, fmt.Errorf("prepare: %w", err)
 	}
 	defer stmtWithUrl.Close()
-	stmtNoUrl, err := tx.Prepare("INSERT INTO nominees (movie_id, category_id, nominee_name) VALUES ($1,$2,$3) RETURNING id")
+	stmtNoUrl, err := tx.PrepareContext(ctx, "INSERT INTO nominees (movie_id, category_id, nominee_name) VALUES ($1,$2,$3) RETURNING id")
 	if err != nil {
 		tx.Rollback()
 		return nil, fmt.Errorf("prepare no-url: %w", err)
@@ -63,12 +65,12 @@ func (s *SQLNominatedStore) InsertMany(ns []models.Nominated) ([]string, error)
 			name = nil
 		}
 		if n.UrlImage == "" {
-			if err := stmtNoUrl.QueryRow(n.MovieID, n.CategoryID, name).Scan(&id); err != nil {
+			if err := stmtNoUrl.QueryRowContext(ctx, n.MovieID, n.CategoryID, name).Scan(&id); err != nil {
 				tx.Rollback()
 				return nil, fmt.Errorf("insert many nominated: %w", err)
 			}
 		} else {
-			if err := stmtWithUrl.QueryRow(n.MovieID, n.CategoryID, name, n.UrlImage).Scan(&id); err != nil {
+			if err := stmtWithUrl.QueryRowContext(ctx, n.MovieID, n.CategoryID, name, n.UrlImage).Scan(&id); err != nil {
 				tx.Rollback()
 				return nil, fmt.Errorf("insert many nominated: %w", err)
 			}
@@ -81,9 +83,9 @@ func (s *SQLNominatedStore) InsertMany(ns []models.Nominated) ([]string, error)
 	return ids, nil
 }
 
-func (s *SQLNominatedStore) Get(id string) (*models.Nominated, error) {
+func (s *SQLNominatedStore) Get(ctx context.Context, id string) (*models.Nominated, error) {
 	var n models.Nominated
-	row := s.db.QueryRow("SELECT id, movie_id, category_id, nominee_name, url_image FROM nominees WHERE id=$1", id)
+	row := s.db.QueryRowContext(ctx, "SELECT id, movie_id, category_id, nominee_name, url_image FROM nominees WHERE id=$1", id)
 	var name sql.NullString
 	var url sql.NullString
 	if err := row.Scan(&n.ID, &n.MovieID, &n.CategoryID, &name, &url); err != nil {
@@ -105,8 +107,14 @@ func (s *SQLNominatedStore) Get(id string) (*models.Nominated, error) {
 	return &n, nil
 }
 
-func (s *SQLNominatedStore) List() ([]models.Nominated, error) {
-	rows, err := s.db.Query("SELECT id, movie_id, category_id, nominee_name, url_image FROM nominees ORDER BY created_at DESC LIMIT 100")
+// List returns the nominations whose category belongs to the given ceremony.
+func (s *SQLNominatedStore) List(ctx context.Context, ceremonyID string) ([]models.Nominated, error) {
+	rows, err := s.db.QueryContext(ctx, `SELECT n.id, n.movie_id, n.category_id, n.nominee_name, n.url_image
+FROM nominees n
+INNER JOIN categories c ON n.category_id = c.id
+WHERE c.ceremony_id = $1
+ORDER BY n.created_at DESC
+LIMIT 100`, ceremonyID)
 	if err != nil {
 		return nil, fmt.Errorf("list nominated: %w", err)
 	}
@@ -131,22 +139,20 @@ func (s *SQLNominatedStore) List() ([]models.Nominated, error) {
 		}
 		out = append(out, n)
 	}
-	log.Printf("sqlnominatedstore: List() complete, scanned %d rows", len(out))
+	slog.DebugContext(ctx, "sqlnominatedstore: List", "rows", len(out))
 	return out, nil
 }
 
 // ListByCategory returns nominated rows filtered by category_id using a DB-level WHERE clause.
-func (s *SQLNominatedStore) ListByCategory(categoryID string) ([]models.Nominated, error) {
-	log.Printf("sqlnominatedstore: ListByCategory(category=%s) start", categoryID)
+func (s *SQLNominatedStore) ListByCategory(ctx context.Context, categoryID string) ([]models.Nominated, error) {
 	qry := `SELECT id, movie_id, category_id, nominee_name, url_image
 FROM nominees
 WHERE category_id = $1
 ORDER BY created_at DESC
 LIMIT 100`
-	rows, err := s.db.Query(qry, categoryID)
+	rows, err := s.db.QueryContext(ctx, qry, categoryID)
 	if err != nil {
-		log.Printf("sqlnominatedstore: ListByCategory query error: %v", err)
-		return nil, err
+		return nil, fmt.Errorf("list nominees by category: %w", err)
 	}
 	defer rows.Close()
 
@@ -157,8 +163,7 @@ LIMIT 100`
 		var name sql.NullString
 		var url sql.NullString
 		if err := rows.Scan(&n.ID, &n.MovieID, &n.CategoryID, &name, &url); err != nil {
-			log.Printf("sqlnominatedstore: ListByCategory scan error at row %d: %v", i, err)
-			return nil, err
+			return nil, fmt.Errorf("scan nominee row %d: %w", i, err)
 		}
 		if name.Valid {
 			n.Name = name.String
@@ -170,16 +175,102 @@ LIMIT 100`
 		} else {
 			n.UrlImage = ""
 		}
-		log.Printf("sqlnominatedstore: ListByCategory scanned row %d: id=%s movie_id=%s category_id=%s", i, n.ID, n.MovieID, n.CategoryID)
 		out = append(out, n)
 		i++
 	}
 
 	if err := rows.Err(); err != nil {
-		log.Printf("sqlnominatedstore: ListByCategory rows.Err: %v", err)
-		return nil, err
+		return nil, fmt.Errorf("list nominees by category: %w", err)
 	}
 
-	log.Printf("sqlnominatedstore: ListByCategory complete, scanned %d rows", i)
+	slog.DebugContext(ctx, "sqlnominatedstore: ListByCategory", "category_id", categoryID, "rows", i)
 	return out, nil
 }
+
+// Update changes the nomination's movie, name and image URL. An empty name is
+// stored as NULL, like Insert.
+func (s *SQLNominatedStore) Update(ctx context.Context, n *models.Nominated) error {
+	var name interface{} = n.Name
+	if n.Name == "" {
+		name = nil
+	}
+	res, err := s.db.ExecContext(ctx, "UPDATE nominees SET movie_id=$1, nominee_name=$2, url_image=$3 WHERE id=$4", n.MovieID, name, n.UrlImage, n.ID)
+	if err != nil {
+		return fmt.Errorf("update nominated: %w", err)
+	}
+	if c, _ := res.RowsAffected(); c == 0 {
+		return fmt.Errorf("update nominated: %w", sql.ErrNoRows)
+	}
+	return nil
+}
+
+// Delete removes the nomination; its votes and winner go with it through
+// ON DELETE CASCADE, so that requires force when any exist.
+func (s *SQLNominatedStore) Delete(ctx context.Context, id string, force bool) error {
+	return deleteUnlessInUse(ctx, s.db, "nominated",
+		"SELECT id FROM nominees WHERE id=$1 FOR UPDATE",
+		`SELECT
+  (SELECT count(*) FROM votes WHERE nominated_id = $1),
+  (SELECT count(*) FROM winners WHERE nominated_id = $1)`,
+		"DELETE FROM nominees WHERE id=$1", id, force)
+}
+
+type nominatedCursor struct {
+	CreatedAt time.Time `json:"created_at"`
+	ID        string    `json:"id"`
+}
+
+// ListPage returns nominations newest first, one page at a time.
+func (s *SQLNominatedStore) ListPage(ctx context.Context, f NominatedFilter, p Page) ([]models.Nominated, string, error) {
+	var c conds
+	if f.CeremonyID != "" {
+		c.add("c.ceremony_id = ?", f.CeremonyID)
+	}
+	if f.CategoryID != "" {
+		c.add("n.category_id = ?", f.CategoryID)
+	}
+	if f.MovieID != "" {
+		c.add("n.movie_id = ?", f.MovieID)
+	}
+	if p.Cursor != "" {
+		var key nominatedCursor
+		if err := DecodeCursor(p.Cursor, &key); err != nil {
+			return nil, "", err
+		}
+		c.add("(n.created_at, n.id) < (?, ?)", key.CreatedAt, key.ID)
+	}
+	limit := c.arg(p.Size() + 1)
+	rows, err := s.db.QueryContext(ctx, `SELECT n.id, n.movie_id, n.category_id, n.nominee_name, n.url_image, n.created_at
+FROM nominees n
+INNER JOIN categories c ON n.category_id = c.id
+`+c.String()+`
+ORDER BY n.created_at DESC, n.id DESC
+LIMIT `+limit, c.args...)
+	if err != nil {
+		return nil, "", fmt.Errorf("list nominated: %w", err)
+	}
+	defer rows.Close()
+	type row struct {
+		n         models.Nominated
+		createdAt time.Time
+	}
+	var out []row
+	for rows.Next() {
+		var r row
+		var name, url sql.NullString
+		if err := rows.Scan(&r.n.ID, &r.n.MovieID, &r.n.CategoryID, &name, &url, &r.createdAt); err != nil {
+			return nil, "", fmt.Errorf("scan nominated: %w", err)
+		}
+		r.n.Name, r.n.UrlImage = name.String, url.String
+		out = append(out, r)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, "", fmt.Errorf("list nominated: %w", err)
+	}
+	page, next := nextPage(out, p.Size(), func(r row) interface{} { return nominatedCursor{CreatedAt: r.createdAt, ID: r.n.ID} })
+	ns := make([]models.Nominated, 0, len(page))
+	for _, r := range page {
+		ns = append(ns, r.n)
+	}
+	return ns, next, nil
+}
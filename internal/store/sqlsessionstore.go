@@ -0,0 +1,141 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"votacao/models"
+)
+
+// SQLSessionStore implements SessionStore using a Postgres DB.
+type SQLSessionStore struct {
+	db *sql.DB
+}
+
+func NewSQLSession(db *sql.DB) *SQLSessionStore { return &SQLSessionStore{db: db} }
+
+const sessionColumns = "id, user_id, token_hash, previous_token_hash, rotated_at, user_agent, ip, created_at, last_used_at, expires_at, revoked_at"
+
+func scanSession(row interface{ Scan(...interface{}) error }) (*models.Session, error) {
+	var s models.Session
+	var prev sql.NullString
+	var rotated, revoked sql.NullTime
+	if err := row.Scan(&s.ID, &s.UserID, &s.TokenHash, &prev, &rotated, &s.UserAgent, &s.IP, &s.CreatedAt, &s.LastUsedAt, &s.ExpiresAt, &revoked); err != nil {
+		return nil, err
+	}
+	s.PreviousTokenHash = prev.String
+	if rotated.Valid {
+		s.RotatedAt = rotated.Time
+	}
+	if revoked.Valid {
+		s.RevokedAt = &revoked.Time
+	}
+	return &s, nil
+}
+
+func (s *SQLSessionStore) Insert(ctx context.Context, sess *models.Session) (string, error) {
+	now := time.Now()
+	if sess.CreatedAt.IsZero() {
+		sess.CreatedAt = now
+	}
+	if sess.LastUsedAt.IsZero() {
+		sess.LastUsedAt = sess.CreatedAt
+	}
+	var id string
+	err := s.db.QueryRowContext(ctx, `INSERT INTO sessions (user_id, token_hash, user_agent, ip, created_at, last_used_at, expires_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING id`,
+		sess.UserID, sess.TokenHash, sess.UserAgent, sess.IP, sess.CreatedAt, sess.LastUsedAt, sess.ExpiresAt).Scan(&id)
+	if err != nil {
+		return "", fmt.Errorf("insert session: %w", err)
+	}
+	return id, nil
+}
+
+func (s *SQLSessionStore) Get(ctx context.Context, id string) (*models.Session, error) {
+	sess, err := scanSession(s.db.QueryRowContext(ctx, "SELECT "+sessionColumns+" FROM sessions WHERE id=$1", id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("get session: %w", err)
+	}
+	return sess, nil
+}
+
+func (s *SQLSessionStore) GetByTokenHash(ctx context.Context, hash string) (*models.Session, error) {
+	sess, err := scanSession(s.db.QueryRowContext(ctx, "SELECT "+sessionColumns+" FROM sessions WHERE token_hash=$1 OR previous_token_hash=$1 LIMIT 1", hash))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("get session by token: %w", err)
+	}
+	return sess, nil
+}
+
+// Rotate swaps the refresh token hash only if oldHash is still current, so two
+// concurrent refreshes with the same token cannot both rotate.
+func (s *SQLSessionStore) Rotate(ctx context.Context, id, oldHash, newHash string, expiresAt time.Time) error {
+	res, err := s.db.ExecContext(ctx, `UPDATE sessions
+SET previous_token_hash = token_hash, token_hash = $3, rotated_at = now(), last_used_at = now(), expires_at = $4
+WHERE id = $1 AND token_hash = $2 AND revoked_at IS NULL`, id, oldHash, newHash, expiresAt)
+	if err != nil {
+		return fmt.Errorf("rotate session: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return fmt.Errorf("rotate session: %w", sql.ErrNoRows)
+	}
+	return nil
+}
+
+func (s *SQLSessionStore) Touch(ctx context.Context, id string, at time.Time) error {
+	if _, err := s.db.ExecContext(ctx, "UPDATE sessions SET last_used_at=$2 WHERE id=$1", id, at); err != nil {
+		return fmt.Errorf("touch session: %w", err)
+	}
+	return nil
+}
+
+// ListByUser returns the user's sessions that are neither revoked nor expired.
+func (s *SQLSessionStore) ListByUser(ctx context.Context, userID string) ([]models.Session, error) {
+	rows, err := s.db.QueryContext(ctx, "SELECT "+sessionColumns+` FROM sessions
+WHERE user_id=$1 AND revoked_at IS NULL AND expires_at > now()
+ORDER BY last_used_at DESC`, userID)
+	if err != nil {
+		return nil, fmt.Errorf("list sessions: %w", err)
+	}
+	defer rows.Close()
+	out := make([]models.Session, 0)
+	for rows.Next() {
+		sess, err := scanSession(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan session: %w", err)
+		}
+		out = append(out, *sess)
+	}
+	return out, rows.Err()
+}
+
+func (s *SQLSessionStore) Revoke(ctx context.Context, id string) error {
+	res, err := s.db.ExecContext(ctx, "UPDATE sessions SET revoked_at = COALESCE(revoked_at, now()) WHERE id=$1", id)
+	if err != nil {
+		return fmt.Errorf("revoke session: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return fmt.Errorf("revoke session: %w", sql.ErrNoRows)
+	}
+	return nil
+}
+
+func (s *SQLSessionStore) RevokeAllByUser(ctx context.Context, userID string) (int, error) {
+	res, err := s.db.ExecContext(ctx, "UPDATE sessions SET revoked_at = now() WHERE user_id=$1 AND revoked_at IS NULL", userID)
+	if err != nil {
+		return 0, fmt.Errorf("revoke sessions: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("revoke sessions: %w", err)
+	}
+	return int(n), nil
+}
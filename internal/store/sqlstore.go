@@ -1,9 +1,12 @@
 package store
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 
+	"github.com/lib/pq"
+
 	"votacao/models"
 )
 
@@ -13,9 +16,9 @@ type SQLStore struct {
 
 func NewSQL(db *sql.DB) *SQLStore { return &SQLStore{db: db} }
 
-func (s *SQLStore) Insert(m *models.Movie) (string, error) {
+func (s *SQLStore) Insert(ctx context.Context, m *models.Movie) (string, error) {
 	var id string
-	err := s.db.QueryRow("INSERT INTO movies (title) VALUES ($1) RETURNING id", m.Title).Scan(&id)
+	err := s.db.QueryRowContext(ctx, "INSERT INTO movies (title) VALUES ($1) RETURNING id", m.Title).Scan(&id)
 	if err != nil {
 		return "", fmt.Errorf("insert: %w", err)
 	}
@@ -23,16 +26,16 @@ func (s *SQLStore) Insert(m *models.Movie) (string, error) {
 }
 
 // InsertMany inserts multiple movies in a transaction and returns their IDs in order.
-func (s *SQLStore) InsertMany(ms []models.Movie) ([]string, error) {
+func (s *SQLStore) InsertMany(ctx context.Context, ms []models.Movie) ([]string, error) {
 	if len(ms) == 0 {
 		return []string{}, nil
 	}
-	tx, err := s.db.Begin()
+	tx, err := s.db.BeginTx(ctx, nil)
 	if err != nil {
 		return nil, fmt.Errorf("begin tx: %w", err)
 	}
 	// prepare a statement for repeated inserts to improve performance
-	stmt, err := tx.Prepare("INSERT INTO movies (title) VALUES ($1) RETURNING id")
+	stmt, err := tx.PrepareContext(ctx, "INSERT INTO movies (title) VALUES ($1) RETURNING id")
 	if err != nil {
 		tx.Rollback()
 		return nil, fmt.Errorf("prepare: %w", err)
@@ -41,7 +44,7 @@ func (s *SQLStore) InsertMany(ms []models.Movie) ([]string, error) {
 	ids := make([]string, 0, len(ms))
 	for _, m := range ms {
 		var id string
-		if err := stmt.QueryRow(m.Title).Scan(&id); err != nil {
+		if err := stmt.QueryRowContext(ctx, m.Title).Scan(&id); err != nil {
 			tx.Rollback()
 			return nil, fmt.Errorf("insert many: %w", err)
 		}
@@ -53,9 +56,9 @@ func (s *SQLStore) InsertMany(ms []models.Movie) ([]string, error) {
 	return ids, nil
 }
 
-func (s *SQLStore) Get(id string) (*models.Movie, error) {
+func (s *SQLStore) Get(ctx context.Context, id string) (*models.Movie, error) {
 	var m models.Movie
-	row := s.db.QueryRow("SELECT id, title FROM movies WHERE id=$1", id)
+	row := s.db.QueryRowContext(ctx, "SELECT id, title FROM movies WHERE id=$1", id)
 	if err := row.Scan(&m.ID, &m.Title); err != nil {
 		if err == sql.ErrNoRows {
 			return nil, nil
@@ -66,9 +69,9 @@ func (s *SQLStore) Get(id string) (*models.Movie, error) {
 }
 
 // GetByTitle looks up a movie by title.
-func (s *SQLStore) GetByTitle(title string) (*models.Movie, error) {
+func (s *SQLStore) GetByTitle(ctx context.Context, title string) (*models.Movie, error) {
 	var m models.Movie
-	row := s.db.QueryRow("SELECT id, title FROM movies WHERE title=$1", title)
+	row := s.db.QueryRowContext(ctx, "SELECT id, title FROM movies WHERE title=$1", title)
 	if err := row.Scan(&m.ID, &m.Title); err != nil {
 		if err == sql.ErrNoRows {
 			return nil, nil
@@ -78,8 +81,29 @@ func (s *SQLStore) GetByTitle(title string) (*models.Movie, error) {
 	return &m, nil
 }
 
-func (s *SQLStore) List() ([]models.Movie, error) {
-	rows, err := s.db.Query("SELECT id, title FROM movies ORDER BY id DESC LIMIT 100")
+// GetMany returns the movies with the given ids, ordered by id descending.
+func (s *SQLStore) GetMany(ctx context.Context, ids []string) ([]models.Movie, error) {
+	out := make([]models.Movie, 0, len(ids))
+	if len(ids) == 0 {
+		return out, nil
+	}
+	rows, err := s.db.QueryContext(ctx, "SELECT id, title FROM movies WHERE id = ANY($1::uuid[]) ORDER BY id DESC", pq.Array(ids))
+	if err != nil {
+		return nil, fmt.Errorf("get many: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var m models.Movie
+		if err := rows.Scan(&m.ID, &m.Title); err != nil {
+			return nil, fmt.Errorf("scan: %w", err)
+		}
+		out = append(out, m)
+	}
+	return out, rows.Err()
+}
+
+func (s *SQLStore) List(ctx context.Context) ([]models.Movie, error) {
+	rows, err := s.db.QueryContext(ctx, "SELECT id, title FROM movies ORDER BY id DESC LIMIT 100")
 	if err != nil {
 		return nil, fmt.Errorf("list: %w", err)
 	}
@@ -94,3 +118,64 @@ func (s *SQLStore) List() ([]models.Movie, error) {
 	}
 	return out, nil
 }
+
+// Update changes a movie's title. It returns sql.ErrNoRows if the movie does not exist.
+func (s *SQLStore) Update(ctx context.Context, m *models.Movie) error {
+	res, err := s.db.ExecContext(ctx, "UPDATE movies SET title=$1 WHERE id=$2", m.Title, m.ID)
+	if err != nil {
+		return fmt.Errorf("update movie: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return fmt.Errorf("update movie: %w", sql.ErrNoRows)
+	}
+	return nil
+}
+
+// Delete removes the movie; its nominees, their votes and winners go with it
+// through ON DELETE CASCADE, so that requires force when any exist.
+func (s *SQLStore) Delete(ctx context.Context, id string, force bool) error {
+	return deleteUnlessInUse(ctx, s.db, "movie",
+		"SELECT id FROM movies WHERE id=$1 FOR UPDATE",
+		`SELECT
+  (SELECT count(*) FROM votes v INNER JOIN nominees n ON n.id = v.nominated_id WHERE n.movie_id = $1),
+  (SELECT count(*) FROM winners w INNER JOIN nominees n ON n.id = w.nominated_id WHERE n.movie_id = $1)`,
+		"DELETE FROM movies WHERE id=$1", id, force)
+}
+
+type movieCursor struct {
+	ID string `json:"id"`
+}
+
+// ListPage returns movies by id descending, like List, one page at a time.
+func (s *SQLStore) ListPage(ctx context.Context, f MovieFilter, p Page) ([]models.Movie, string, error) {
+	var c conds
+	if f.TitlePrefix != "" {
+		c.add(`lower(title) LIKE lower(?)`, likePrefix(f.TitlePrefix))
+	}
+	if p.Cursor != "" {
+		var key movieCursor
+		if err := DecodeCursor(p.Cursor, &key); err != nil {
+			return nil, "", err
+		}
+		c.add("id < ?", key.ID)
+	}
+	limit := c.arg(p.Size() + 1)
+	rows, err := s.db.QueryContext(ctx, "SELECT id, title FROM movies "+c.String()+" ORDER BY id DESC LIMIT "+limit, c.args...)
+	if err != nil {
+		return nil, "", fmt.Errorf("list movies: %w", err)
+	}
+	defer rows.Close()
+	var out []models.Movie
+	for rows.Next() {
+		var m models.Movie
+		if err := rows.Scan(&m.ID, &m.Title); err != nil {
+			return nil, "", fmt.Errorf("scan movie: %w", err)
+		}
+		out = append(out, m)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, "", fmt.Errorf("list movies: %w", err)
+	}
+	out, next := nextPage(out, p.Size(), func(m models.Movie) interface{} { return movieCursor{ID: m.ID} })
+	return out, next, nil
+}
@@ -0,0 +1,42 @@
+package store_test
+
+import (
+	"os"
+	"testing"
+
+	"votacao/internal/db"
+	"votacao/internal/store"
+	"votacao/internal/store/storetest"
+)
+
+// TestConformance runs the shared store suite against Postgres. It needs a
+// disposable database in TEST_DATABASE_URL: every subtest truncates all tables.
+func TestConformance(t *testing.T) {
+	dsn := os.Getenv("TEST_DATABASE_URL")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_URL not set")
+	}
+	database, err := db.Open(dsn, db.Pool{})
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	defer database.Close()
+
+	storetest.Run(t, func(t *testing.T) storetest.Stores {
+		if _, err := database.Exec(`TRUNCATE sessions, vote_events, votes, winners, league_members, leagues,
+nominees, categories, movies, users, ceremonies RESTART IDENTITY CASCADE`); err != nil {
+			t.Fatalf("truncate: %v", err)
+		}
+		return storetest.Stores{
+			Movies:     store.NewSQL(database),
+			Ceremonies: store.NewSQLCeremony(database),
+			Categories: store.NewSQLCategory(database),
+			Nominated:  store.NewSQLNominated(database),
+			Users:      store.NewSQLUser(database),
+			Votes:      store.NewSQLVote(database),
+			Winners:    store.NewSQLWinnerStore(database),
+			Leagues:    store.NewSQLLeague(database),
+			Sessions:   store.NewSQLSession(database),
+		}
+	})
+}
@@ -1,6 +1,7 @@
 package store
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"time"
@@ -14,7 +15,7 @@ type SQLUserStore struct{ db *sql.DB }
 
 func NewSQLUser(db *sql.DB) *SQLUserStore { return &SQLUserStore{db: db} }
 
-func (s *SQLUserStore) Insert(u *models.User) (string, error) {
+func (s *SQLUserStore) Insert(ctx context.Context, u *models.User) (string, error) {
 	if u.ID == "" {
 		u.ID = uuid.NewString()
 	}
@@ -25,7 +26,7 @@ func (s *SQLUserStore) Insert(u *models.User) (string, error) {
 	if u.Role == "" {
 		u.Role = "user"
 	}
-	_, err := s.db.Exec("INSERT INTO users (id, nickname, bio, email, password_hash, role, created_at) VALUES ($1,$2,$3,$4,$5,$6,$7)",
+	_, err := s.db.ExecContext(ctx, "INSERT INTO users (id, nickname, bio, email, password_hash, role, created_at) VALUES ($1,$2,$3,$4,$5,$6,$7)",
 		u.ID, u.Nickname, u.Bio, u.Email, u.PasswordHash, u.Role, u.CreatedAt)
 	if err != nil {
 		return "", fmt.Errorf("insert user: %w", err)
@@ -33,11 +34,11 @@ func (s *SQLUserStore) Insert(u *models.User) (string, error) {
 	return u.ID, nil
 }
 
-func (s *SQLUserStore) GetByID(id string) (*models.User, error) {
+func (s *SQLUserStore) GetByID(ctx context.Context, id string) (*models.User, error) {
 	var u models.User
 	var bio sql.NullString
 	var role sql.NullString
-	row := s.db.QueryRow("SELECT id, nickname, bio, email, password_hash, role, created_at FROM users WHERE id=$1", id)
+	row := s.db.QueryRowContext(ctx, "SELECT id, nickname, bio, email, password_hash, role, created_at FROM users WHERE id=$1", id)
 	if err := row.Scan(&u.ID, &u.Nickname, &bio, &u.Email, &u.PasswordHash, &role, &u.CreatedAt); err != nil {
 		if err == sql.ErrNoRows {
 			return nil, nil
@@ -55,11 +56,11 @@ func (s *SQLUserStore) GetByID(id string) (*models.User, error) {
 	return &u, nil
 }
 
-func (s *SQLUserStore) GetByEmail(email string) (*models.User, error) {
+func (s *SQLUserStore) GetByEmail(ctx context.Context, email string) (*models.User, error) {
 	var u models.User
 	var bio sql.NullString
 	var role sql.NullString
-	row := s.db.QueryRow("SELECT id, nickname, bio, email, password_hash, role, created_at FROM users WHERE email=$1", email)
+	row := s.db.QueryRowContext(ctx, "SELECT id, nickname, bio, email, password_hash, role, created_at FROM users WHERE email=$1", email)
 	if err := row.Scan(&u.ID, &u.Nickname, &bio, &u.Email, &u.PasswordHash, &role, &u.CreatedAt); err != nil {
 		if err == sql.ErrNoRows {
 			return nil, nil
@@ -77,8 +78,8 @@ func (s *SQLUserStore) GetByEmail(email string) (*models.User, error) {
 	return &u, nil
 }
 
-func (s *SQLUserStore) List() ([]models.User, error) {
-	rows, err := s.db.Query("SELECT id, nickname, bio, email, password_hash, role, created_at FROM users ORDER BY created_at DESC LIMIT 100")
+func (s *SQLUserStore) List(ctx context.Context) ([]models.User, error) {
+	rows, err := s.db.QueryContext(ctx, "SELECT id, nickname, bio, email, password_hash, role, created_at FROM users ORDER BY created_at DESC LIMIT 100")
 	if err != nil {
 		return nil, fmt.Errorf("list users: %w", err)
 	}
@@ -103,3 +104,76 @@ func (s *SQLUserStore) List() ([]models.User, error) {
 	}
 	return out, nil
 }
+
+func (s *SQLUserStore) SetRole(ctx context.Context, id, role string) error {
+	res, err := s.db.ExecContext(ctx, "UPDATE users SET role=$1 WHERE id=$2", role, id)
+	if err != nil {
+		return fmt.Errorf("set role: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return fmt.Errorf("set role: %w", sql.ErrNoRows)
+	}
+	return nil
+}
+
+// PromoteFirstAdmin promotes the user only while no user has the admin role.
+func (s *SQLUserStore) PromoteFirstAdmin(ctx context.Context, id string) (bool, error) {
+	res, err := s.db.ExecContext(ctx, `UPDATE users SET role='admin'
+WHERE id=$1 AND NOT EXISTS (SELECT 1 FROM users WHERE role='admin')`, id)
+	if err != nil {
+		return false, fmt.Errorf("promote first admin: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("promote first admin: %w", err)
+	}
+	return n > 0, nil
+}
+
+type userCursor struct {
+	CreatedAt time.Time `json:"created_at"`
+	ID        string    `json:"id"`
+}
+
+// ListPage returns users newest first, one page at a time.
+func (s *SQLUserStore) ListPage(ctx context.Context, f UserFilter, p Page) ([]models.User, string, error) {
+	var c conds
+	if f.NicknamePrefix != "" {
+		c.add(`lower(nickname) LIKE lower(?)`, likePrefix(f.NicknamePrefix))
+	}
+	if p.Cursor != "" {
+		var key userCursor
+		if err := DecodeCursor(p.Cursor, &key); err != nil {
+			return nil, "", err
+		}
+		c.add("(created_at, id) < (?, ?)", key.CreatedAt, key.ID)
+	}
+	limit := c.arg(p.Size() + 1)
+	rows, err := s.db.QueryContext(ctx, "SELECT id, nickname, bio, email, password_hash, role, created_at FROM users "+c.String()+
+		" ORDER BY created_at DESC, id DESC LIMIT "+limit, c.args...)
+	if err != nil {
+		return nil, "", fmt.Errorf("list users: %w", err)
+	}
+	defer rows.Close()
+	var out []models.User
+	for rows.Next() {
+		var u models.User
+		var bio, role sql.NullString
+		if err := rows.Scan(&u.ID, &u.Nickname, &bio, &u.Email, &u.PasswordHash, &role, &u.CreatedAt); err != nil {
+			return nil, "", fmt.Errorf("scan user: %w", err)
+		}
+		if bio.Valid {
+			u.Bio = &bio.String
+		}
+		u.Role = "user"
+		if role.Valid {
+			u.Role = role.String
+		}
+		out = append(out, u)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, "", fmt.Errorf("list users: %w", err)
+	}
+	out, next := nextPage(out, p.Size(), func(u models.User) interface{} { return userCursor{CreatedAt: u.CreatedAt, ID: u.ID} })
+	return out, next, nil
+}
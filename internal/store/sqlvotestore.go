@@ -1,12 +1,13 @@
 package store
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
+	"log/slog"
+	"time"
 
 	"votacao/models"
-
-	"github.com/lib/pq"
 )
 
 type SQLVoteStore struct{ db *sql.DB }
@@ -16,28 +17,114 @@ func NewSQLVote(db *sql.DB) *SQLVoteStore { return &SQLVoteStore{db: db} }
 // Insert will create or update a vote for a user in a category. If the user
 // already voted in the same category, the nominated_id will be updated to the
 // new value and the existing vote id is returned. This allows changing votes.
-func (s *SQLVoteStore) Insert(v *models.Vote) (int64, bool, error) {
-	var id int64
-	// Try to insert first
-	err := s.db.QueryRow("INSERT INTO votes (user_id, nominated_id, category_id, created_at) VALUES ($1,$2,$3, now()) RETURNING id", v.UserID, v.NominatedID, v.CategoryID).Scan(&id)
-	if err == nil {
-		return id, true, nil
-	}
-	// If unique constraint violation, update the existing vote (change nominated)
-	if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23505" {
-		// Update the existing vote for this user/category and return its id
-		err2 := s.db.QueryRow("UPDATE votes SET nominated_id=$1, created_at=now() WHERE user_id=$2 AND category_id=$3 RETURNING id", v.NominatedID, v.UserID, v.CategoryID).Scan(&id)
-		if err2 != nil {
-			return 0, false, fmt.Errorf("update vote after conflict: %w", err2)
+// Every new or changed pick is also appended to vote_events in the same
+// transaction, so the previous pick is never lost.
+func (s *SQLVoteStore) Insert(ctx context.Context, v *models.Vote) (int64, string, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, "", fmt.Errorf("begin tx: %w", err)
+	}
+	action, err := upsertVote(ctx, tx, v)
+	if err != nil {
+		tx.Rollback()
+		return 0, "", err
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, "", fmt.Errorf("commit: %w", err)
+	}
+	return v.ID, action, nil
+}
+
+// InsertBallot upserts the votes like Insert, all in one transaction.
+func (s *SQLVoteStore) InsertBallot(ctx context.Context, votes []models.Vote) ([]string, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("begin tx: %w", err)
+	}
+	actions := make([]string, len(votes))
+	for i := range votes {
+		if actions[i], err = upsertVote(ctx, tx, &votes[i]); err != nil {
+			tx.Rollback()
+			return nil, err
 		}
-		return id, false, nil
 	}
-	return 0, false, fmt.Errorf("insert vote: %w", err)
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("commit: %w", err)
+	}
+	return actions, nil
 }
 
-func (s *SQLVoteStore) Get(id int64) (*models.Vote, error) {
+// upsertVote saves v in tx, sets its ID and records the vote event. It
+// returns models.VoteCreated, models.VoteChanged or VoteUnchanged.
+func upsertVote(ctx context.Context, tx *sql.Tx, v *models.Vote) (string, error) {
+	var created bool
+	var prev sql.NullString
+	// prev is read from the snapshot taken before the upsert; (xmax = 0) is
+	// true only for freshly inserted rows.
+	err := tx.QueryRowContext(ctx, `
+		WITH prev AS (
+			SELECT nominated_id FROM votes WHERE user_id = $1 AND category_id = $3
+		)
+		INSERT INTO votes (user_id, nominated_id, category_id, created_at)
+		VALUES ($1, $2, $3, now())
+		ON CONFLICT (user_id, category_id)
+		DO UPDATE SET nominated_id = EXCLUDED.nominated_id, created_at = now()
+		RETURNING id, (xmax = 0), (SELECT nominated_id FROM prev)
+	`, v.UserID, v.NominatedID, v.CategoryID).Scan(&v.ID, &created, &prev)
+	if err != nil {
+		return "", fmt.Errorf("upsert vote: %w", err)
+	}
+
+	action := models.VoteChanged
+	if created {
+		action = models.VoteCreated
+	} else if prev.Valid && prev.String == v.NominatedID {
+		// re-submitting the same pick is not a change
+		action = VoteUnchanged
+	}
+	if action != VoteUnchanged {
+		var prevID interface{}
+		if !created && prev.Valid {
+			prevID = prev.String
+		}
+		if _, err := tx.ExecContext(ctx, `INSERT INTO vote_events (vote_id, user_id, category_id, nominated_id, previous_nominated_id, action)
+VALUES ($1, $2, $3, $4, $5, $6)`, v.ID, v.UserID, v.CategoryID, v.NominatedID, prevID, action); err != nil {
+			return "", fmt.Errorf("insert vote event: %w", err)
+		}
+	}
+	slog.DebugContext(ctx, "sqlvotestore: vote stored", "vote_id", v.ID, "user_id", v.UserID, "action", action, "previous_nominated_id", prev.String)
+	return action, nil
+}
+
+// ListEvents returns the user's vote history in the given ceremony, oldest first.
+func (s *SQLVoteStore) ListEvents(ctx context.Context, userID, ceremonyID string) ([]models.VoteEvent, error) {
+	rows, err := s.db.QueryContext(ctx, `SELECT e.id, e.vote_id, e.user_id, e.category_id, e.nominated_id, e.previous_nominated_id, e.action, e.created_at
+FROM vote_events e
+INNER JOIN categories c ON e.category_id = c.id
+WHERE e.user_id = $1 AND c.ceremony_id = $2
+ORDER BY e.created_at ASC, e.id ASC`, userID, ceremonyID)
+	if err != nil {
+		return nil, fmt.Errorf("list vote events: %w", err)
+	}
+	defer rows.Close()
+	out := make([]models.VoteEvent, 0)
+	for rows.Next() {
+		var e models.VoteEvent
+		var prev sql.NullString
+		if err := rows.Scan(&e.ID, &e.VoteID, &e.UserID, &e.CategoryID, &e.NominatedID, &prev, &e.Action, &e.CreatedAt); err != nil {
+			return nil, fmt.Errorf("scan vote event: %w", err)
+		}
+		if prev.Valid {
+			e.PreviousNominatedID = prev.String
+		}
+		out = append(out, e)
+	}
+	return out, rows.Err()
+}
+
+func (s *SQLVoteStore) Get(ctx context.Context, id int64) (*models.Vote, error) {
 	var v models.Vote
-	row := s.db.QueryRow("SELECT id, user_id, nominated_id, category_id, created_at FROM votes WHERE id=$1", id)
+	row := s.db.QueryRowContext(ctx, "SELECT id, user_id, nominated_id, category_id, created_at FROM votes WHERE id=$1", id)
 	if err := row.Scan(&v.ID, &v.UserID, &v.NominatedID, &v.CategoryID, &v.CreatedAt); err != nil {
 		if err == sql.ErrNoRows {
 			return nil, nil
@@ -47,8 +134,14 @@ func (s *SQLVoteStore) Get(id int64) (*models.Vote, error) {
 	return &v, nil
 }
 
-func (s *SQLVoteStore) ListByUser(userID string) ([]models.Vote, error) {
-	rows, err := s.db.Query("SELECT id, user_id, nominated_id, category_id, created_at FROM votes WHERE user_id=$1 ORDER BY created_at DESC LIMIT 100", userID)
+// ListByUser returns the user's votes in categories of the given ceremony.
+func (s *SQLVoteStore) ListByUser(ctx context.Context, userID, ceremonyID string) ([]models.Vote, error) {
+	rows, err := s.db.QueryContext(ctx, `SELECT v.id, v.user_id, v.nominated_id, v.category_id, v.created_at
+FROM votes v
+INNER JOIN categories c ON v.category_id = c.id
+WHERE v.user_id = $1 AND c.ceremony_id = $2
+ORDER BY v.created_at DESC
+LIMIT 100`, userID, ceremonyID)
 	if err != nil {
 		return nil, fmt.Errorf("list votes: %w", err)
 	}
@@ -64,83 +157,90 @@ func (s *SQLVoteStore) ListByUser(userID string) ([]models.Vote, error) {
 	return out, nil
 }
 
-// GetUserScore returns (points, max_points, error) for a user by comparing with winners table.
-// Points: Best Picture=3, Actor/Actress in Leading Role=2, others=1
-func (s *SQLVoteStore) GetUserScore(userID string) (int, int, error) {
-	var points, maxPoints int
-
-	// Calculate max points (sum of points for all categories the user voted in)
-	err := s.db.QueryRow(`
-		SELECT COALESCE(SUM(
-			CASE 
-				WHEN c.name = 'Best Picture' THEN 3
-				WHEN c.name IN ('Actor in a Leading Role', 'Actress in a Leading Role') THEN 2
-				ELSE 1
-			END
-		), 0)
+// scoredVotesQuery is the scoring rule: one row per vote in a ceremony ($1)
+// with its category's weight (categories.points), whether the pick won and
+// the points it earned. Scores and scorecards are all read from it.
+const scoredVotesQuery = `
+		SELECT
+			v.id,
+			v.user_id,
+			v.category_id,
+			v.nominated_id,
+			c.points AS weight,
+			w.id IS NOT NULL AS correct,
+			CASE WHEN w.id IS NOT NULL THEN c.points ELSE 0 END AS points
 		FROM votes v
 		INNER JOIN categories c ON v.category_id = c.id
-		WHERE v.user_id = $1
-	`, userID).Scan(&maxPoints)
-	if err != nil {
-		return 0, 0, fmt.Errorf("calc max points: %w", err)
-	}
-
-	// Calculate earned points (correct votes with weighted points)
-	err = s.db.QueryRow(`
-		SELECT COALESCE(SUM(
-			CASE 
-				WHEN c.name = 'Best Picture' THEN 3
-				WHEN c.name IN ('Actor in a Leading Role', 'Actress in a Leading Role') THEN 2
-				ELSE 1
-			END
-		), 0)
-		FROM votes v
-		INNER JOIN winners w ON v.nominated_id = w.nominated_id
-		INNER JOIN categories c ON v.category_id = c.id
-		WHERE v.user_id = $1
-	`, userID).Scan(&points)
+		LEFT JOIN winners w ON v.nominated_id = w.nominated_id
+		WHERE c.ceremony_id = $1
+	`
+
+// GetUserScore returns (points, max_points, error) for a user by comparing with winners table,
+// counting only the categories of the given ceremony.
+// Each correct pick is worth its category's points (categories.points).
+func (s *SQLVoteStore) GetUserScore(ctx context.Context, userID, ceremonyID string) (int, int, error) {
+	var points, maxPoints int
+	err := s.db.QueryRowContext(ctx, `SELECT COALESCE(SUM(sv.points), 0), COALESCE(SUM(sv.weight), 0)
+FROM (`+scoredVotesQuery+`) sv
+WHERE sv.user_id = $2`, ceremonyID, userID).Scan(&points, &maxPoints)
 	if err != nil {
 		return 0, 0, fmt.Errorf("calc points: %w", err)
 	}
-
 	return points, maxPoints, nil
 }
 
-// GetAllScores returns scores for all users who have voted, ordered by points descending.
-// Points: Best Picture=3, Actor/Actress in Leading Role=2, others=1
-func (s *SQLVoteStore) GetAllScores() ([]UserScore, error) {
-	rows, err := s.db.Query(`
-		SELECT 
+// ScoredVotes returns the user's votes in the ceremony with their weight and
+// earned points.
+func (s *SQLVoteStore) ScoredVotes(ctx context.Context, userID, ceremonyID string) ([]ScoredVote, error) {
+	rows, err := s.db.QueryContext(ctx, `SELECT sv.category_id, sv.nominated_id, sv.weight, sv.correct, sv.points
+FROM (`+scoredVotesQuery+`) sv
+WHERE sv.user_id = $2`, ceremonyID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("scored votes: %w", err)
+	}
+	defer rows.Close()
+	out := make([]ScoredVote, 0)
+	for rows.Next() {
+		var sv ScoredVote
+		if err := rows.Scan(&sv.CategoryID, &sv.NominatedID, &sv.Weight, &sv.Correct, &sv.Points); err != nil {
+			return nil, fmt.Errorf("scan scored vote: %w", err)
+		}
+		out = append(out, sv)
+	}
+	return out, rows.Err()
+}
+
+// scoresQuery ranks users by weighted points within a ceremony ($1). The %s
+// placeholder takes an extra JOIN used to restrict the set of users.
+const scoresQuery = `
+		SELECT
 			u.id,
 			u.nickname,
-			COUNT(v.id) AS total_votes,
-			COUNT(w.id) AS correct_votes,
-			COALESCE(SUM(
-				CASE 
-					WHEN w.id IS NOT NULL THEN
-						CASE 
-							WHEN c.name = 'Best Picture' THEN 3
-							WHEN c.name IN ('Actor in a Leading Role', 'Actress in a Leading Role') THEN 2
-							ELSE 1
-						END
-					ELSE 0
-				END
-			), 0) AS points,
-			COALESCE(SUM(
-				CASE 
-					WHEN c.name = 'Best Picture' THEN 3
-					WHEN c.name IN ('Actor in a Leading Role', 'Actress in a Leading Role') THEN 2
-					ELSE 1
-				END
-			), 0) AS max_points
+			COUNT(sv.id) AS total_votes,
+			COUNT(*) FILTER (WHERE sv.correct) AS correct_votes,
+			COALESCE(SUM(sv.points), 0) AS points,
+			COALESCE(SUM(sv.weight), 0) AS max_points
 		FROM users u
-		INNER JOIN votes v ON u.id = v.user_id
-		INNER JOIN categories c ON v.category_id = c.id
-		LEFT JOIN winners w ON v.nominated_id = w.nominated_id
+		%s
+		INNER JOIN (` + scoredVotesQuery + `) sv ON sv.user_id = u.id
 		GROUP BY u.id, u.nickname
-		ORDER BY points DESC, correct_votes DESC, total_votes DESC
-	`)
+		ORDER BY points DESC, correct_votes DESC, total_votes DESC, u.id
+	`
+
+// GetAllScores returns scores for all users who have voted in the given ceremony,
+// ordered by points descending.
+// Each correct pick is worth its category's points (categories.points).
+func (s *SQLVoteStore) GetAllScores(ctx context.Context, ceremonyID string) ([]UserScore, error) {
+	return s.queryScores(ctx, fmt.Sprintf(scoresQuery, ""), ceremonyID)
+}
+
+// GetLeagueScores is GetAllScores restricted to the members of a league.
+func (s *SQLVoteStore) GetLeagueScores(ctx context.Context, leagueID, ceremonyID string) ([]UserScore, error) {
+	return s.queryScores(ctx, fmt.Sprintf(scoresQuery, "INNER JOIN league_members lm ON lm.user_id = u.id AND lm.league_id = $2"), ceremonyID, leagueID)
+}
+
+func (s *SQLVoteStore) queryScores(ctx context.Context, query string, args ...interface{}) ([]UserScore, error) {
+	rows, err := s.db.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("get all scores: %w", err)
 	}
@@ -156,3 +256,89 @@ func (s *SQLVoteStore) GetAllScores() ([]UserScore, error) {
 	}
 	return scores, rows.Err()
 }
+
+type voteCursor struct {
+	CreatedAt time.Time `json:"created_at"`
+	ID        int64     `json:"id"`
+}
+
+// ListPage returns votes most recent first, one page at a time.
+func (s *SQLVoteStore) ListPage(ctx context.Context, f VoteFilter, p Page) ([]models.Vote, string, error) {
+	var c conds
+	if f.UserID != "" {
+		c.add("v.user_id = ?", f.UserID)
+	}
+	if f.CeremonyID != "" {
+		c.add("c.ceremony_id = ?", f.CeremonyID)
+	}
+	if f.CategoryID != "" {
+		c.add("v.category_id = ?", f.CategoryID)
+	}
+	if p.Cursor != "" {
+		var key voteCursor
+		if err := DecodeCursor(p.Cursor, &key); err != nil {
+			return nil, "", err
+		}
+		c.add("(v.created_at, v.id) < (?, ?)", key.CreatedAt, key.ID)
+	}
+	limit := c.arg(p.Size() + 1)
+	rows, err := s.db.QueryContext(ctx, `SELECT v.id, v.user_id, v.nominated_id, v.category_id, v.created_at
+FROM votes v
+INNER JOIN categories c ON v.category_id = c.id
+`+c.String()+`
+ORDER BY v.created_at DESC, v.id DESC
+LIMIT `+limit, c.args...)
+	if err != nil {
+		return nil, "", fmt.Errorf("list votes: %w", err)
+	}
+	defer rows.Close()
+	var out []models.Vote
+	for rows.Next() {
+		var v models.Vote
+		if err := rows.Scan(&v.ID, &v.UserID, &v.NominatedID, &v.CategoryID, &v.CreatedAt); err != nil {
+			return nil, "", fmt.Errorf("scan vote: %w", err)
+		}
+		out = append(out, v)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, "", fmt.Errorf("list votes: %w", err)
+	}
+	out, next := nextPage(out, p.Size(), func(v models.Vote) interface{} { return voteCursor{CreatedAt: v.CreatedAt, ID: v.ID} })
+	return out, next, nil
+}
+
+type scoreCursor struct {
+	Points       int    `json:"p"`
+	CorrectVotes int    `json:"c"`
+	TotalVotes   int    `json:"t"`
+	UserID       string `json:"u"`
+}
+
+// ScoresPage returns the ceremony's scores in GetAllScores order, one page at a time.
+func (s *SQLVoteStore) ScoresPage(ctx context.Context, f ScoreFilter, p Page) ([]UserScore, string, error) {
+	var c conds
+	c.arg(f.CeremonyID) // $1 of scoresQuery
+	if f.NicknamePrefix != "" {
+		c.add(`lower(s.nickname) LIKE lower(?)`, likePrefix(f.NicknamePrefix))
+	}
+	if p.Cursor != "" {
+		var key scoreCursor
+		if err := DecodeCursor(p.Cursor, &key); err != nil {
+			return nil, "", err
+		}
+		// descending on the counts, ascending on the user id
+		c.add("(-s.points, -s.correct_votes, -s.total_votes, s.id) > (?::bigint, ?::bigint, ?::bigint, ?::uuid)",
+			-key.Points, -key.CorrectVotes, -key.TotalVotes, key.UserID)
+	}
+	limit := c.arg(p.Size() + 1)
+	query := "SELECT s.id, s.nickname, s.total_votes, s.correct_votes, s.points, s.max_points FROM (" + fmt.Sprintf(scoresQuery, "") + ") s " +
+		c.String() + " ORDER BY s.points DESC, s.correct_votes DESC, s.total_votes DESC, s.id LIMIT " + limit
+	scores, err := s.queryScores(ctx, query, c.args...)
+	if err != nil {
+		return nil, "", err
+	}
+	scores, next := nextPage(scores, p.Size(), func(sc UserScore) interface{} {
+		return scoreCursor{Points: sc.Points, CorrectVotes: sc.CorrectVotes, TotalVotes: sc.TotalVotes, UserID: sc.UserID}
+	})
+	return scores, next, nil
+}
@@ -1,6 +1,7 @@
 package store
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 
@@ -17,9 +18,9 @@ func NewSQLWinnerStore(db *sql.DB) *SQLWinnerStore {
 	return &SQLWinnerStore{db: db}
 }
 
-func (s *SQLWinnerStore) Insert(w *models.Winner) (string, error) {
+func (s *SQLWinnerStore) Insert(ctx context.Context, w *models.Winner) (string, error) {
 	var id string
-	err := s.db.QueryRow(
+	err := s.db.QueryRowContext(ctx,
 		"INSERT INTO winners (nominated_id) VALUES ($1) RETURNING id",
 		w.NominatedID,
 	).Scan(&id)
@@ -29,9 +30,9 @@ func (s *SQLWinnerStore) Insert(w *models.Winner) (string, error) {
 	return id, nil
 }
 
-func (s *SQLWinnerStore) Get(id string) (*models.Winner, error) {
+func (s *SQLWinnerStore) Get(ctx context.Context, id string) (*models.Winner, error) {
 	var w models.Winner
-	row := s.db.QueryRow("SELECT id, nominated_id FROM winners WHERE id=$1", id)
+	row := s.db.QueryRowContext(ctx, "SELECT id, nominated_id FROM winners WHERE id=$1", id)
 	if err := row.Scan(&w.ID, &w.NominatedID); err != nil {
 		if err == sql.ErrNoRows {
 			return nil, nil
@@ -41,9 +42,9 @@ func (s *SQLWinnerStore) Get(id string) (*models.Winner, error) {
 	return &w, nil
 }
 
-func (s *SQLWinnerStore) GetByNominated(nominatedID string) (*models.Winner, error) {
+func (s *SQLWinnerStore) GetByNominated(ctx context.Context, nominatedID string) (*models.Winner, error) {
 	var w models.Winner
-	row := s.db.QueryRow("SELECT id, nominated_id FROM winners WHERE nominated_id=$1", nominatedID)
+	row := s.db.QueryRowContext(ctx, "SELECT id, nominated_id FROM winners WHERE nominated_id=$1", nominatedID)
 	if err := row.Scan(&w.ID, &w.NominatedID); err != nil {
 		if err == sql.ErrNoRows {
 			return nil, nil
@@ -53,8 +54,13 @@ func (s *SQLWinnerStore) GetByNominated(nominatedID string) (*models.Winner, err
 	return &w, nil
 }
 
-func (s *SQLWinnerStore) List() ([]models.Winner, error) {
-	rows, err := s.db.Query("SELECT id, nominated_id FROM winners")
+// List returns the winners whose category belongs to the given ceremony.
+func (s *SQLWinnerStore) List(ctx context.Context, ceremonyID string) ([]models.Winner, error) {
+	rows, err := s.db.QueryContext(ctx, `SELECT w.id, w.nominated_id
+FROM winners w
+INNER JOIN nominees n ON w.nominated_id = n.id
+INNER JOIN categories c ON n.category_id = c.id
+WHERE c.ceremony_id = $1`, ceremonyID)
 	if err != nil {
 		return nil, fmt.Errorf("list winners: %w", err)
 	}
@@ -71,8 +77,8 @@ func (s *SQLWinnerStore) List() ([]models.Winner, error) {
 	return winners, rows.Err()
 }
 
-func (s *SQLWinnerStore) Delete(id string) error {
-	_, err := s.db.Exec("DELETE FROM winners WHERE id=$1", id)
+func (s *SQLWinnerStore) Delete(ctx context.Context, id string) error {
+	_, err := s.db.ExecContext(ctx, "DELETE FROM winners WHERE id=$1", id)
 	if err != nil {
 		return fmt.Errorf("delete winner: %w", err)
 	}
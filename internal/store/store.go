@@ -1,26 +1,72 @@
 package store
 
-import "votacao/models"
+import (
+	"context"
+	"time"
+
+	"votacao/models"
+)
 
 // MovieStore defines storage operations for movies.
 type MovieStore interface {
-	Insert(m *models.Movie) (string, error)
-	Get(id string) (*models.Movie, error)
-	List() ([]models.Movie, error)
+	Insert(ctx context.Context, m *models.Movie) (string, error)
+	Get(ctx context.Context, id string) (*models.Movie, error)
+	// GetMany returns the movies with the given ids; unknown ids are skipped.
+	GetMany(ctx context.Context, ids []string) ([]models.Movie, error)
+	List(ctx context.Context) ([]models.Movie, error)
+	// ListPage returns a page of movies in List order and the next page's cursor
+	// ("" on the last page). It returns ErrInvalidCursor for a bad cursor.
+	ListPage(ctx context.Context, f MovieFilter, p Page) ([]models.Movie, string, error)
 	// InsertMany inserts multiple movies and returns their assigned IDs in the same order.
-	InsertMany(ms []models.Movie) ([]string, error)
+	InsertMany(ctx context.Context, ms []models.Movie) ([]string, error)
+	// Update changes a movie's title. It returns sql.ErrNoRows if the movie does not exist.
+	Update(ctx context.Context, m *models.Movie) error
+	// Delete removes a movie and its nominees. It returns sql.ErrNoRows if the
+	// movie does not exist and an *InUseError if votes or winners reference its
+	// nominees, unless force is set (they are then deleted too).
+	Delete(ctx context.Context, id string, force bool) error
+}
+
+// CeremonyStore defines storage operations for ceremonies (one per awards edition).
+type CeremonyStore interface {
+	// Insert inserts a ceremony and returns its assigned ID.
+	Insert(ctx context.Context, c *models.Ceremony) (string, error)
+	// Get returns a ceremony by id or nil if not found.
+	Get(ctx context.Context, id string) (*models.Ceremony, error)
+	// GetActive returns the active ceremony or nil if none is active.
+	GetActive(ctx context.Context) (*models.Ceremony, error)
+	// List returns all ceremonies, newest first.
+	List(ctx context.Context) ([]models.Ceremony, error)
+	// SetActive makes the given ceremony the active one and archives the previous one.
+	SetActive(ctx context.Context, id string) error
 }
 
 // CategoryStore defines storage operations for categories.
 type CategoryStore interface {
 	// Insert inserts a single category and returns its assigned ID.
-	Insert(c *models.Category) (string, error)
+	Insert(ctx context.Context, c *models.Category) (string, error)
 	// Get returns a category by id or nil if not found.
-	Get(id string) (*models.Category, error)
-	// List returns categories (up to 100 by default).
-	List() ([]models.Category, error)
+	Get(ctx context.Context, id string) (*models.Category, error)
+	// List returns the categories of a ceremony (up to 100 by default).
+	List(ctx context.Context, ceremonyID string) ([]models.Category, error)
+	// ListPage returns a page of categories in List order and the next page's cursor.
+	ListPage(ctx context.Context, f CategoryFilter, p Page) ([]models.Category, string, error)
 	// InsertMany inserts multiple categories and returns their assigned IDs in the same order.
-	InsertMany(cs []models.Category) ([]string, error)
+	InsertMany(ctx context.Context, cs []models.Category) ([]string, error)
+	// Update changes a category's name, sequence order and points.
+	// It returns sql.ErrNoRows if the category does not exist.
+	Update(ctx context.Context, c *models.Category) error
+	// SetPoints applies category id -> points weights of one ceremony
+	// atomically. It returns sql.ErrNoRows if a category does not exist or
+	// belongs to another ceremony.
+	SetPoints(ctx context.Context, ceremonyID string, points map[string]int) error
+	// SetLock sets the category's locked flag and scheduled lock time (nil clears it).
+	// It returns sql.ErrNoRows if the category does not exist.
+	SetLock(ctx context.Context, id string, locked bool, locksAt *time.Time) error
+	// Delete removes a category and its nominees. It returns sql.ErrNoRows if
+	// the category does not exist and an *InUseError if votes or winners
+	// reference it, unless force is set (they are then deleted too).
+	Delete(ctx context.Context, id string, force bool) error
 }
 
 // MovieCategoryStore aggregates movie and category operations for convenience.
@@ -34,44 +80,91 @@ type CategoryStore interface {
 // NominatedStore defines storage operations for nominations linking movies and categories.
 type NominatedStore interface {
 	// Insert inserts a single nomination and returns its assigned ID.
-	Insert(n *models.Nominated) (string, error)
+	Insert(ctx context.Context, n *models.Nominated) (string, error)
 	// InsertMany inserts multiple nominations and returns their assigned IDs in the same order.
-	InsertMany(ns []models.Nominated) ([]string, error)
+	InsertMany(ctx context.Context, ns []models.Nominated) ([]string, error)
 	// Get returns a nomination by id or nil if not found.
-	Get(id string) (*models.Nominated, error)
-	// List returns nominations (up to 100 by default).
-	List() ([]models.Nominated, error)
+	Get(ctx context.Context, id string) (*models.Nominated, error)
+	// List returns the nominations of a ceremony (up to 100 by default).
+	List(ctx context.Context, ceremonyID string) ([]models.Nominated, error)
+	// ListPage returns a page of nominations, newest first, and the next page's cursor.
+	ListPage(ctx context.Context, f NominatedFilter, p Page) ([]models.Nominated, string, error)
 	// ListByCategory returns nominations for a given category id (up to 100 by default).
-	ListByCategory(categoryID string) ([]models.Nominated, error)
+	ListByCategory(ctx context.Context, categoryID string) ([]models.Nominated, error)
+	// Update changes a nomination's movie, name and image URL; the category is
+	// fixed because votes are keyed by it. It returns sql.ErrNoRows if the
+	// nomination does not exist.
+	Update(ctx context.Context, n *models.Nominated) error
+	// Delete removes a nomination. It returns sql.ErrNoRows if it does not exist
+	// and an *InUseError if votes or winners reference it, unless force is set
+	// (they are then deleted too).
+	Delete(ctx context.Context, id string, force bool) error
 }
 
 // UserStore defines storage operations for application users.
 type UserStore interface {
 	// Insert inserts a new user and returns the assigned UUID string.
-	Insert(u *models.User) (string, error)
+	Insert(ctx context.Context, u *models.User) (string, error)
 	// GetByID returns a user by UUID or nil if not found.
-	GetByID(id string) (*models.User, error)
+	GetByID(ctx context.Context, id string) (*models.User, error)
 	// GetByEmail returns a user by email or nil if not found.
-	GetByEmail(email string) (*models.User, error)
+	GetByEmail(ctx context.Context, email string) (*models.User, error)
 	// List returns users (up to 100 by default).
-	List() ([]models.User, error)
+	List(ctx context.Context) ([]models.User, error)
+	// ListPage returns a page of users, newest first, and the next page's cursor.
+	ListPage(ctx context.Context, f UserFilter, p Page) ([]models.User, string, error)
+	// SetRole changes a user's role. It returns sql.ErrNoRows if the user does not exist.
+	SetRole(ctx context.Context, id, role string) error
+	// PromoteFirstAdmin gives the user the admin role only if no admin exists yet.
+	// It reports whether the user was promoted.
+	PromoteFirstAdmin(ctx context.Context, id string) (bool, error)
 }
 
 // VoteStore defines storage operations for votes.
 type VoteStore interface {
-	// Insert inserts or updates a vote and returns its assigned ID and a
-	// boolean indicating whether a new row was created (true) or an
-	// existing vote was updated (false).
-	Insert(v *models.Vote) (int64, bool, error)
+	// Insert inserts or updates a vote and returns its assigned ID and what
+	// happened to it: models.VoteCreated, models.VoteChanged or VoteUnchanged
+	// when the same pick is submitted again. New and changed picks are
+	// recorded in the vote history.
+	Insert(ctx context.Context, v *models.Vote) (int64, string, error)
+	// InsertBallot inserts or updates several votes in one transaction:
+	// either all are saved or none. It sets each vote's ID and returns, in
+	// the same order, models.VoteCreated, models.VoteChanged or VoteUnchanged
+	// for each. History is recorded as by Insert.
+	InsertBallot(ctx context.Context, votes []models.Vote) ([]string, error)
+	// ListEvents returns a user's vote history within a ceremony, oldest first.
+	ListEvents(ctx context.Context, userID, ceremonyID string) ([]models.VoteEvent, error)
 	// Get returns a vote by id or nil if not found.
-	Get(id int64) (*models.Vote, error)
-	// ListByUser returns votes for a given user UUID.
-	ListByUser(userID string) ([]models.Vote, error)
-	// GetUserScore returns the points and max points for a user (matching winners).
-	// Points: Best Picture=3, Actor/Actress in Leading Role=2, others=1
-	GetUserScore(userID string) (int, int, error)
-	// GetAllScores returns scores for all users who voted.
-	GetAllScores() ([]UserScore, error)
+	Get(ctx context.Context, id int64) (*models.Vote, error)
+	// ListByUser returns votes for a given user UUID within a ceremony.
+	ListByUser(ctx context.Context, userID, ceremonyID string) ([]models.Vote, error)
+	// ListPage returns a page of votes, most recent first, and the next page's cursor.
+	ListPage(ctx context.Context, f VoteFilter, p Page) ([]models.Vote, string, error)
+	// GetUserScore returns the points and max points for a user (matching winners)
+	// within a ceremony. Each category is weighted by its points column.
+	GetUserScore(ctx context.Context, userID, ceremonyID string) (int, int, error)
+	// ScoredVotes returns a user's votes in a ceremony, each scored as by
+	// GetUserScore.
+	ScoredVotes(ctx context.Context, userID, ceremonyID string) ([]ScoredVote, error)
+	// GetAllScores returns scores for all users who voted in a ceremony.
+	GetAllScores(ctx context.Context, ceremonyID string) ([]UserScore, error)
+	// ScoresPage returns a page of GetAllScores (same order) and the next page's cursor.
+	ScoresPage(ctx context.Context, f ScoreFilter, p Page) ([]UserScore, string, error)
+	// GetLeagueScores returns scores in a ceremony for the members of a league only.
+	GetLeagueScores(ctx context.Context, leagueID, ceremonyID string) ([]UserScore, error)
+}
+
+// VoteUnchanged is InsertBallot's result for a pick that was already saved.
+const VoteUnchanged = "unchanged"
+
+// ScoredVote is a vote with its category's weight and the points it earned:
+// the weight when the pick won, 0 otherwise.
+type ScoredVote struct {
+	CategoryID  string
+	NominatedID string
+	Weight      int
+	Correct     bool
+	Points      int
 }
 
 // UserScore represents a user's voting score with weighted points.
@@ -87,13 +180,55 @@ type UserScore struct {
 // WinnerStore defines storage operations for winners.
 type WinnerStore interface {
 	// Insert inserts a winner and returns its assigned ID.
-	Insert(w *models.Winner) (string, error)
+	Insert(ctx context.Context, w *models.Winner) (string, error)
 	// Get returns a winner by id or nil if not found.
-	Get(id string) (*models.Winner, error)
+	Get(ctx context.Context, id string) (*models.Winner, error)
 	// GetByNominated returns a winner by nominated_id or nil if not found.
-	GetByNominated(nominatedID string) (*models.Winner, error)
-	// List returns all winners.
-	List() ([]models.Winner, error)
+	GetByNominated(ctx context.Context, nominatedID string) (*models.Winner, error)
+	// List returns all winners of a ceremony.
+	List(ctx context.Context, ceremonyID string) ([]models.Winner, error)
 	// Delete removes a winner by id.
-	Delete(id string) error
+	Delete(ctx context.Context, id string) error
+}
+
+// LeagueStore defines storage operations for private leagues and their members.
+type LeagueStore interface {
+	// Insert creates a league (generating an invite code if empty), adds the
+	// owner as a member and returns the league ID.
+	Insert(ctx context.Context, l *models.League) (string, error)
+	// Get returns a league by id or nil if not found.
+	Get(ctx context.Context, id string) (*models.League, error)
+	// GetByInviteCode returns a league by invite code or nil if not found.
+	GetByInviteCode(ctx context.Context, code string) (*models.League, error)
+	// ListByUser returns the leagues a user belongs to.
+	ListByUser(ctx context.Context, userID string) ([]models.League, error)
+	// AddMember adds a user to a league; it is a no-op if already a member.
+	AddMember(ctx context.Context, leagueID, userID string) error
+	// RemoveMember removes a user from a league.
+	RemoveMember(ctx context.Context, leagueID, userID string) error
+	// IsMember reports whether the user belongs to the league.
+	IsMember(ctx context.Context, leagueID, userID string) (bool, error)
+}
+
+// SessionStore defines storage operations for login sessions (refresh tokens).
+type SessionStore interface {
+	// Insert creates a session and returns its ID.
+	Insert(ctx context.Context, s *models.Session) (string, error)
+	// Get returns a session by id or nil if not found.
+	Get(ctx context.Context, id string) (*models.Session, error)
+	// GetByTokenHash returns the session whose current or previous refresh
+	// token hash matches, or nil if none does.
+	GetByTokenHash(ctx context.Context, hash string) (*models.Session, error)
+	// Rotate replaces the refresh token hash if it still equals oldHash and
+	// extends the expiry. It returns sql.ErrNoRows if the session was rotated
+	// concurrently, revoked or does not exist.
+	Rotate(ctx context.Context, id, oldHash, newHash string, expiresAt time.Time) error
+	// Touch records that the session was used.
+	Touch(ctx context.Context, id string, at time.Time) error
+	// ListByUser returns the user's active sessions, most recently used first.
+	ListByUser(ctx context.Context, userID string) ([]models.Session, error)
+	// Revoke revokes a session. It returns sql.ErrNoRows if the session does not exist.
+	Revoke(ctx context.Context, id string) error
+	// RevokeAllByUser revokes every active session of the user and returns how many.
+	RevokeAllByUser(ctx context.Context, userID string) (int, error)
 }
@@ -0,0 +1,898 @@
+// Package storetest is the conformance suite shared by the store backends.
+// Every backend (Postgres, memstore) must pass Run, which keeps them
+// semantically identical.
+package storetest
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sort"
+	"testing"
+	"time"
+
+	"votacao/internal/store"
+	"votacao/models"
+)
+
+// Stores is one backend's set of stores, all sharing the same data.
+type Stores struct {
+	Movies     store.MovieStore
+	Ceremonies store.CeremonyStore
+	Categories store.CategoryStore
+	Nominated  store.NominatedStore
+	Users      store.UserStore
+	Votes      store.VoteStore
+	Winners    store.WinnerStore
+	Leagues    store.LeagueStore
+	Sessions   store.SessionStore
+}
+
+// Run runs the conformance suite. newStores must return stores over empty
+// tables; it is called once per subtest.
+func Run(t *testing.T, newStores func(t *testing.T) Stores) {
+	tests := []struct {
+		name string
+		fn   func(t *testing.T, s Stores)
+	}{
+		{"Movies", testMovies},
+		{"MoviesListLimit", testMoviesListLimit},
+		{"LargeBallot", testLargeBallot},
+		{"Ceremonies", testCeremonies},
+		{"Categories", testCategories},
+		{"CategoryPointsAndLocks", testCategoryPointsAndLocks},
+		{"Nominated", testNominated},
+		{"Updates", testUpdates},
+		{"SafeDeletes", testSafeDeletes},
+		{"Users", testUsers},
+		{"Pagination", testPagination},
+		{"VoteUpsertAndHistory", testVoteUpsertAndHistory},
+		{"Ballot", testBallot},
+		{"Scores", testScores},
+		{"Winners", testWinners},
+		{"Leagues", testLeagues},
+		{"Sessions", testSessions},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) { tc.fn(t, newStores(t)) })
+	}
+}
+
+// must unwraps a (value, error) result, failing the test on error:
+// must(s.Movies.Get(ctx, id))(t).
+func must[T any](v T, err error) func(t *testing.T) T {
+	return func(t *testing.T) T {
+		t.Helper()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return v
+	}
+}
+
+// must2 is must for ListPage results: must2(s.Users.ListPage(ctx, f, p))(t).
+func must2[T any](v T, next string, err error) func(t *testing.T) (T, string) {
+	return func(t *testing.T) (T, string) {
+		t.Helper()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return v, next
+	}
+}
+
+// fixture is a ceremony with two categories (points 1 and 3), two nominees in
+// each and two users.
+type fixture struct {
+	ceremony         string
+	catA, catB       string // points 1 and 3
+	nomA1, nomA2     string
+	nomB1, nomB2     string
+	alice, bob       string
+	movieX, movieY   string
+	otherCeremony    string
+	otherCat, otherN string
+}
+
+func newFixture(t *testing.T, s Stores) fixture {
+	ctx := context.Background()
+	t.Helper()
+	var f fixture
+	f.ceremony = must(s.Ceremonies.Insert(ctx, &models.Ceremony{Year: 2100, Name: "Test 2100", Deadline: time.Now().Add(time.Hour), Status: models.CeremonyActive}))(t)
+	f.otherCeremony = must(s.Ceremonies.Insert(ctx, &models.Ceremony{Year: 2099, Name: "Test 2099", Deadline: time.Now().Add(-time.Hour)}))(t)
+	f.catA = must(s.Categories.Insert(ctx, &models.Category{CeremonyID: f.ceremony, Name: "Cat A", SequenceOrder: 1, Points: 1}))(t)
+	f.catB = must(s.Categories.Insert(ctx, &models.Category{CeremonyID: f.ceremony, Name: "Cat B", SequenceOrder: 2, Points: 3}))(t)
+	f.otherCat = must(s.Categories.Insert(ctx, &models.Category{CeremonyID: f.otherCeremony, Name: "Cat A"}))(t)
+	f.movieX = must(s.Movies.Insert(ctx, &models.Movie{Title: "Movie X"}))(t)
+	f.movieY = must(s.Movies.Insert(ctx, &models.Movie{Title: "Movie Y"}))(t)
+	f.nomA1 = must(s.Nominated.Insert(ctx, &models.Nominated{MovieID: f.movieX, CategoryID: f.catA, Name: "A1"}))(t)
+	f.nomA2 = must(s.Nominated.Insert(ctx, &models.Nominated{MovieID: f.movieY, CategoryID: f.catA, Name: "A2"}))(t)
+	f.nomB1 = must(s.Nominated.Insert(ctx, &models.Nominated{MovieID: f.movieX, CategoryID: f.catB, Name: "B1"}))(t)
+	f.nomB2 = must(s.Nominated.Insert(ctx, &models.Nominated{MovieID: f.movieY, CategoryID: f.catB, Name: "B2"}))(t)
+	f.otherN = must(s.Nominated.Insert(ctx, &models.Nominated{MovieID: f.movieX, CategoryID: f.otherCat, Name: "Old"}))(t)
+	f.alice = must(s.Users.Insert(ctx, &models.User{Nickname: "alice", Email: "alice@example.com", PasswordHash: "x"}))(t)
+	f.bob = must(s.Users.Insert(ctx, &models.User{Nickname: "bob", Email: "bob@example.com", PasswordHash: "x"}))(t)
+	return f
+}
+
+func testMovies(t *testing.T, s Stores) {
+	ctx := context.Background()
+	id := must(s.Movies.Insert(ctx, &models.Movie{Title: "Inception"}))(t)
+	m := must(s.Movies.Get(ctx, id))(t)
+	if m == nil || m.ID != id || m.Title != "Inception" {
+		t.Fatalf("Get = %+v", m)
+	}
+	if m := must(s.Movies.Get(ctx, "00000000-0000-0000-0000-000000000000"))(t); m != nil {
+		t.Fatalf("Get(missing) = %+v, want nil", m)
+	}
+	if _, err := s.Movies.Insert(ctx, &models.Movie{Title: "Inception"}); err == nil {
+		t.Fatal("duplicate title: expected error")
+	}
+	ids := must(s.Movies.InsertMany(ctx, []models.Movie{{Title: "A"}, {Title: "B"}}))(t)
+	if len(ids) != 2 || must(s.Movies.Get(ctx, ids[1]))(t).Title != "B" {
+		t.Fatalf("InsertMany ids = %v", ids)
+	}
+	// a failing batch inserts nothing
+	if _, err := s.Movies.InsertMany(ctx, []models.Movie{{Title: "C"}, {Title: "A"}}); err == nil {
+		t.Fatal("InsertMany with duplicate: expected error")
+	}
+	if got := len(must(s.Movies.List(ctx))(t)); got != 3 {
+		t.Fatalf("List len = %d, want 3", got)
+	}
+}
+
+func testMoviesListLimit(t *testing.T, s Stores) {
+	ctx := context.Background()
+	ms := make([]models.Movie, 101)
+	for i := range ms {
+		ms[i].Title = fmt.Sprintf("Movie %03d", i)
+	}
+	must(s.Movies.InsertMany(ctx, ms))(t)
+	list := must(s.Movies.List(ctx))(t)
+	if len(list) != 100 {
+		t.Fatalf("List len = %d, want 100", len(list))
+	}
+	for i := 1; i < len(list); i++ {
+		if list[i-1].ID < list[i].ID {
+			t.Fatalf("List not ordered by id desc at %d", i)
+		}
+	}
+}
+
+// testLargeBallot checks that a ceremony with more nominees than the List cap
+// can still be read in full through ListPage and GetMany.
+func testLargeBallot(t *testing.T, s Stores) {
+	ctx := context.Background()
+	f := newFixture(t, s)
+	ms := make([]models.Movie, 120)
+	for i := range ms {
+		ms[i].Title = fmt.Sprintf("Ballot movie %03d", i)
+	}
+	movieIDs := must(s.Movies.InsertMany(ctx, ms))(t)
+	ns := make([]models.Nominated, len(movieIDs))
+	for i, id := range movieIDs {
+		ns[i] = models.Nominated{MovieID: id, CategoryID: f.catA, Name: fmt.Sprintf("Nominee %03d", i)}
+	}
+	must(s.Nominated.InsertMany(ctx, ns))(t)
+
+	if got := len(must(s.Nominated.List(ctx, f.ceremony))(t)); got != 100 {
+		t.Fatalf("List len = %d, want 100", got)
+	}
+	seen := make(map[string]bool)
+	p := store.Page{Limit: 50}
+	for {
+		noms, next := must2(s.Nominated.ListPage(ctx, store.NominatedFilter{CeremonyID: f.ceremony}, p))(t)
+		for _, n := range noms {
+			seen[n.ID] = true
+		}
+		if next == "" {
+			break
+		}
+		p.Cursor = next
+	}
+	if len(seen) != 124 {
+		t.Fatalf("ListPage found %d nominees, want 124", len(seen))
+	}
+
+	want := append(movieIDs, f.movieX, "00000000-0000-0000-0000-000000000000")
+	got := must(s.Movies.GetMany(ctx, want))(t)
+	if len(got) != 121 {
+		t.Fatalf("GetMany len = %d, want 121", len(got))
+	}
+	for i := 1; i < len(got); i++ {
+		if got[i-1].ID < got[i].ID {
+			t.Fatalf("GetMany not ordered by id desc at %d", i)
+		}
+	}
+	if got := must(s.Movies.GetMany(ctx, nil))(t); len(got) != 0 {
+		t.Fatalf("GetMany(nil) = %v", got)
+	}
+}
+
+func testCeremonies(t *testing.T, s Stores) {
+	ctx := context.Background()
+	if c := must(s.Ceremonies.GetActive(ctx))(t); c != nil {
+		t.Fatalf("GetActive on empty store = %+v", c)
+	}
+	a := must(s.Ceremonies.Insert(ctx, &models.Ceremony{Year: 2100, Name: "A", Deadline: time.Now()}))(t)
+	b := must(s.Ceremonies.Insert(ctx, &models.Ceremony{Year: 2101, Name: "B", Deadline: time.Now()}))(t)
+	if c := must(s.Ceremonies.Get(ctx, a))(t); c.Status != models.CeremonyUpcoming {
+		t.Fatalf("default status = %q", c.Status)
+	}
+	if _, err := s.Ceremonies.Insert(ctx, &models.Ceremony{Year: 2100, Name: "dup", Deadline: time.Now()}); err == nil {
+		t.Fatal("duplicate year: expected error")
+	}
+	if err := s.Ceremonies.SetActive(ctx, a); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Ceremonies.SetActive(ctx, b); err != nil {
+		t.Fatal(err)
+	}
+	if c := must(s.Ceremonies.GetActive(ctx))(t); c == nil || c.ID != b {
+		t.Fatalf("GetActive = %+v, want %s", c, b)
+	}
+	if c := must(s.Ceremonies.Get(ctx, a))(t); c.Status != models.CeremonyArchived {
+		t.Fatalf("previous active status = %q, want archived", c.Status)
+	}
+	if err := s.Ceremonies.SetActive(ctx, "00000000-0000-0000-0000-000000000000"); !errors.Is(err, sql.ErrNoRows) {
+		t.Fatalf("SetActive(missing) = %v, want sql.ErrNoRows", err)
+	}
+	list := must(s.Ceremonies.List(ctx))(t)
+	if len(list) != 2 || list[0].Year != 2101 {
+		t.Fatalf("List = %+v, want newest first", list)
+	}
+}
+
+func testCategories(t *testing.T, s Stores) {
+	ctx := context.Background()
+	f := newFixture(t, s)
+	c := must(s.Categories.Get(ctx, f.catA))(t)
+	if c == nil || c.Points != 1 || c.CeremonyID != f.ceremony || c.Locked || c.LocksAt != nil {
+		t.Fatalf("Get = %+v", c)
+	}
+	// points are stored as given: 0 is a valid weight, negative ones are refused
+	if c := must(s.Categories.Get(ctx, f.otherCat))(t); c.Points != 0 {
+		t.Fatalf("Insert without points: Points = %d, want 0", c.Points)
+	}
+	if _, err := s.Categories.Insert(ctx, &models.Category{CeremonyID: f.ceremony, Name: "Negative", Points: -1}); store.Violation(err) != store.ErrCheckViolation {
+		t.Fatalf("negative points: err = %v, want a check violation", err)
+	}
+	list := must(s.Categories.List(ctx, f.ceremony))(t)
+	if len(list) != 2 || list[0].ID != f.catA || list[1].ID != f.catB {
+		t.Fatalf("List = %+v, want [Cat A, Cat B]", list)
+	}
+	if _, err := s.Categories.Insert(ctx, &models.Category{CeremonyID: f.ceremony, Name: "Cat A"}); err == nil {
+		t.Fatal("duplicate name in ceremony: expected error")
+	}
+	ids := must(s.Categories.InsertMany(ctx, []models.Category{{CeremonyID: f.ceremony, Name: "Cat C", SequenceOrder: 0}}))(t)
+	if list := must(s.Categories.List(ctx, f.ceremony))(t); len(list) != 3 || list[0].ID != ids[0] {
+		t.Fatalf("List after InsertMany = %+v, want Cat C first", list)
+	}
+
+	if err := s.Categories.Update(ctx, &models.Category{ID: f.catA, Name: "Renamed", SequenceOrder: 9, Points: 5}); err != nil {
+		t.Fatal(err)
+	}
+	if c := must(s.Categories.Get(ctx, f.catA))(t); c.Name != "Renamed" || c.SequenceOrder != 9 || c.Points != 5 {
+		t.Fatalf("after Update = %+v", c)
+	}
+	if err := s.Categories.Update(ctx, &models.Category{ID: "00000000-0000-0000-0000-000000000000", Name: "x"}); !errors.Is(err, sql.ErrNoRows) {
+		t.Fatalf("Update(missing) = %v, want sql.ErrNoRows", err)
+	}
+}
+
+func testCategoryPointsAndLocks(t *testing.T, s Stores) {
+	ctx := context.Background()
+	f := newFixture(t, s)
+	if err := s.Categories.SetPoints(ctx, f.ceremony, map[string]int{f.catA: 4, f.catB: 7}); err != nil {
+		t.Fatal(err)
+	}
+	if a, b := must(s.Categories.Get(ctx, f.catA))(t), must(s.Categories.Get(ctx, f.catB))(t); a.Points != 4 || b.Points != 7 {
+		t.Fatalf("points = %d, %d", a.Points, b.Points)
+	}
+	// all or nothing
+	err := s.Categories.SetPoints(ctx, f.ceremony, map[string]int{f.catA: 1, "00000000-0000-0000-0000-000000000000": 2})
+	if !errors.Is(err, sql.ErrNoRows) {
+		t.Fatalf("SetPoints(missing) = %v, want sql.ErrNoRows", err)
+	}
+	// a category of another ceremony is not reweighted
+	err = s.Categories.SetPoints(ctx, f.ceremony, map[string]int{f.catA: 1, f.otherCat: 2})
+	if !errors.Is(err, sql.ErrNoRows) {
+		t.Fatalf("SetPoints(other ceremony) = %v, want sql.ErrNoRows", err)
+	}
+	if o := must(s.Categories.Get(ctx, f.otherCat))(t); o.Points != 0 {
+		t.Fatalf("SetPoints changed another ceremony's category to %d", o.Points)
+	}
+	if a := must(s.Categories.Get(ctx, f.catA))(t); a.Points != 4 {
+		t.Fatalf("failed SetPoints changed points to %d", a.Points)
+	}
+	if err := s.Categories.SetPoints(ctx, f.ceremony, map[string]int{f.catA: -1}); err == nil {
+		t.Fatal("negative points: expected error")
+	}
+
+	at := time.Date(2100, 3, 1, 20, 0, 0, 0, time.UTC)
+	if err := s.Categories.SetLock(ctx, f.catA, true, &at); err != nil {
+		t.Fatal(err)
+	}
+	c := must(s.Categories.Get(ctx, f.catA))(t)
+	if !c.Locked || c.LocksAt == nil || !c.LocksAt.Equal(at) {
+		t.Fatalf("after SetLock = %+v", c)
+	}
+	if err := s.Categories.SetLock(ctx, f.catA, false, nil); err != nil {
+		t.Fatal(err)
+	}
+	if c := must(s.Categories.Get(ctx, f.catA))(t); c.Locked || c.LocksAt != nil {
+		t.Fatalf("after unlock = %+v", c)
+	}
+	if err := s.Categories.SetLock(ctx, "00000000-0000-0000-0000-000000000000", true, nil); !errors.Is(err, sql.ErrNoRows) {
+		t.Fatalf("SetLock(missing) = %v, want sql.ErrNoRows", err)
+	}
+}
+
+func testNominated(t *testing.T, s Stores) {
+	ctx := context.Background()
+	f := newFixture(t, s)
+	n := must(s.Nominated.Get(ctx, f.nomA1))(t)
+	if n == nil || n.Name != "A1" || n.MovieID != f.movieX || n.CategoryID != f.catA || n.UrlImage == "" {
+		t.Fatalf("Get = %+v (url_image should get the column default)", n)
+	}
+	id := must(s.Nominated.Insert(ctx, &models.Nominated{MovieID: f.movieY, CategoryID: f.catB, UrlImage: "http://img"}))(t)
+	if n := must(s.Nominated.Get(ctx, id))(t); n.Name != "" || n.UrlImage != "http://img" {
+		t.Fatalf("Get = %+v", n)
+	}
+	if _, err := s.Nominated.Insert(ctx, &models.Nominated{MovieID: f.movieX, CategoryID: f.catA, Name: "A1"}); err == nil {
+		t.Fatal("duplicate nominee: expected error")
+	}
+	if _, err := s.Nominated.Insert(ctx, &models.Nominated{MovieID: f.movieX, CategoryID: "00000000-0000-0000-0000-000000000000", Name: "x"}); err == nil {
+		t.Fatal("unknown category: expected error")
+	}
+
+	list := must(s.Nominated.List(ctx, f.ceremony))(t)
+	if len(list) != 5 || list[0].ID != id {
+		t.Fatalf("List len = %d (want 5, newest first)", len(list))
+	}
+	for _, n := range list {
+		if n.ID == f.otherN {
+			t.Fatal("List leaked a nominee of another ceremony")
+		}
+	}
+	byCat := must(s.Nominated.ListByCategory(ctx, f.catA))(t)
+	if len(byCat) != 2 || byCat[0].ID != f.nomA2 {
+		t.Fatalf("ListByCategory = %+v, want [A2, A1]", byCat)
+	}
+	if empty := must(s.Nominated.ListByCategory(ctx, f.otherCeremony))(t); empty == nil || len(empty) != 0 {
+		t.Fatalf("ListByCategory(no rows) = %#v, want empty non-nil", empty)
+	}
+}
+
+func testUpdates(t *testing.T, s Stores) {
+	ctx := context.Background()
+	f := newFixture(t, s)
+	if err := s.Movies.Update(ctx, &models.Movie{ID: f.movieX, Title: "Movie X (2100)"}); err != nil {
+		t.Fatal(err)
+	}
+	if m := must(s.Movies.Get(ctx, f.movieX))(t); m.Title != "Movie X (2100)" {
+		t.Fatalf("Movies.Get after Update = %+v", m)
+	}
+	if err := s.Movies.Update(ctx, &models.Movie{ID: f.movieY, Title: "Movie X (2100)"}); err == nil {
+		t.Fatal("Movies.Update to a duplicate title: expected error")
+	}
+	if err := s.Movies.Update(ctx, &models.Movie{ID: "00000000-0000-0000-0000-000000000000", Title: "x"}); !errors.Is(err, sql.ErrNoRows) {
+		t.Fatalf("Movies.Update(missing) = %v, want sql.ErrNoRows", err)
+	}
+
+	if err := s.Nominated.Update(ctx, &models.Nominated{ID: f.nomA1, MovieID: f.movieY, Name: "A1 fixed", UrlImage: "http://img"}); err != nil {
+		t.Fatal(err)
+	}
+	n := must(s.Nominated.Get(ctx, f.nomA1))(t)
+	if n.MovieID != f.movieY || n.Name != "A1 fixed" || n.UrlImage != "http://img" || n.CategoryID != f.catA {
+		t.Fatalf("Nominated.Get after Update = %+v", n)
+	}
+	if err := s.Nominated.Update(ctx, &models.Nominated{ID: f.nomA2, MovieID: f.movieY, Name: "A1 fixed", UrlImage: "http://img"}); err == nil {
+		t.Fatal("Nominated.Update to a duplicate nominee: expected error")
+	}
+	if err := s.Nominated.Update(ctx, &models.Nominated{ID: "00000000-0000-0000-0000-000000000000", MovieID: f.movieY, UrlImage: "http://img"}); !errors.Is(err, sql.ErrNoRows) {
+		t.Fatalf("Nominated.Update(missing) = %v, want sql.ErrNoRows", err)
+	}
+}
+
+func testSafeDeletes(t *testing.T, s Stores) {
+	ctx := context.Background()
+	f := newFixture(t, s)
+	if _, _, err := s.Votes.Insert(ctx, &models.Vote{UserID: f.alice, NominatedID: f.nomA1, CategoryID: f.catA}); err != nil {
+		t.Fatal(err)
+	}
+	must(s.Winners.Insert(ctx, &models.Winner{NominatedID: f.nomB1}))(t)
+	inUse := func(err error, votes, winners int) {
+		t.Helper()
+		var in *store.InUseError
+		if !errors.As(err, &in) || !errors.Is(err, store.ErrInUse) || in.Votes != votes || in.Winners != winners {
+			t.Fatalf("err = %v, want in use by %d vote(s) and %d winner(s)", err, votes, winners)
+		}
+	}
+
+	// unreferenced rows go without force
+	if err := s.Nominated.Delete(ctx, f.nomA2, false); err != nil {
+		t.Fatal(err)
+	}
+	if n := must(s.Nominated.Get(ctx, f.nomA2))(t); n != nil {
+		t.Fatalf("Get after Delete = %+v", n)
+	}
+	if err := s.Nominated.Delete(ctx, f.nomA2, false); !errors.Is(err, sql.ErrNoRows) {
+		t.Fatalf("Delete(missing) = %v, want sql.ErrNoRows", err)
+	}
+
+	inUse(s.Nominated.Delete(ctx, f.nomA1, false), 1, 0)
+	inUse(s.Categories.Delete(ctx, f.catB, false), 0, 1)
+	// movie X has nominees A1 (voted), B1 (won) and Old (other ceremony)
+	inUse(s.Movies.Delete(ctx, f.movieX, false), 1, 1)
+	if n := must(s.Nominated.Get(ctx, f.nomA1))(t); n == nil {
+		t.Fatal("refused Delete removed the nominee")
+	}
+
+	// force cascades to nominees, votes and winners
+	if err := s.Movies.Delete(ctx, f.movieX, true); err != nil {
+		t.Fatal(err)
+	}
+	for _, id := range []string{f.nomA1, f.nomB1, f.otherN} {
+		if n := must(s.Nominated.Get(ctx, id))(t); n != nil {
+			t.Fatalf("nominee %s survived its movie", id)
+		}
+	}
+	if votes := must(s.Votes.ListByUser(ctx, f.alice, f.ceremony))(t); len(votes) != 0 {
+		t.Fatalf("votes after forced delete = %+v", votes)
+	}
+	if winners := must(s.Winners.List(ctx, f.ceremony))(t); len(winners) != 0 {
+		t.Fatalf("winners after forced delete = %+v", winners)
+	}
+	// the remaining nominee (B2) is unreferenced, so the category goes without force
+	if err := s.Categories.Delete(ctx, f.catB, false); err != nil {
+		t.Fatal(err)
+	}
+	if c := must(s.Categories.Get(ctx, f.catB))(t); c != nil {
+		t.Fatalf("Categories.Get after Delete = %+v", c)
+	}
+	if n := must(s.Nominated.Get(ctx, f.nomB2))(t); n != nil {
+		t.Fatal("nominee survived its category")
+	}
+}
+
+// walk reads every page of a ListPage call with the given limit.
+func walk[T any](t *testing.T, limit int, list func(p store.Page) ([]T, string, error)) []T {
+	t.Helper()
+	var all []T
+	cursor := ""
+	for i := 0; ; i++ {
+		items, next, err := list(store.Page{Limit: limit, Cursor: cursor})
+		if err != nil {
+			t.Fatalf("page %d: %v", i, err)
+		}
+		if len(items) > limit {
+			t.Fatalf("page %d has %d items, limit %d", i, len(items), limit)
+		}
+		all = append(all, items...)
+		if next == "" {
+			return all
+		}
+		if i > 100 {
+			t.Fatal("pagination does not terminate")
+		}
+		cursor = next
+	}
+}
+
+func testPagination(t *testing.T, s Stores) {
+	ctx := context.Background()
+	f := newFixture(t, s)
+	for _, nick := range []string{"Carol", "caroline", "dave"} {
+		must(s.Users.Insert(ctx, &models.User{Nickname: nick, Email: nick + "@example.com", PasswordHash: "x"}))(t)
+	}
+	for _, v := range []models.Vote{
+		{UserID: f.alice, NominatedID: f.nomA1, CategoryID: f.catA},
+		{UserID: f.alice, NominatedID: f.nomB2, CategoryID: f.catB},
+		{UserID: f.bob, NominatedID: f.nomA2, CategoryID: f.catA},
+	} {
+		if _, _, err := s.Votes.Insert(ctx, &v); err != nil {
+			t.Fatal(err)
+		}
+	}
+	must(s.Winners.Insert(ctx, &models.Winner{NominatedID: f.nomA1}))(t)
+
+	users := walk(t, 2, func(p store.Page) ([]models.User, string, error) { return s.Users.ListPage(ctx, store.UserFilter{}, p) })
+	listed := must(s.Users.List(ctx))(t)
+	if len(users) != 5 || len(listed) != 5 {
+		t.Fatalf("users: paged %d, listed %d, want 5", len(users), len(listed))
+	}
+	for i := range users {
+		if users[i].ID != listed[i].ID {
+			t.Fatalf("users page order differs from List at %d", i)
+		}
+	}
+	if got := walk(t, 1, func(p store.Page) ([]models.User, string, error) {
+		return s.Users.ListPage(ctx, store.UserFilter{NicknamePrefix: "CAR"}, p)
+	}); len(got) != 2 {
+		t.Fatalf("nickname prefix: got %d users, want 2", len(got))
+	}
+
+	movies := walk(t, 1, func(p store.Page) ([]models.Movie, string, error) {
+		return s.Movies.ListPage(ctx, store.MovieFilter{}, p)
+	})
+	if len(movies) != 2 || movies[0].ID < movies[1].ID {
+		t.Fatalf("movies = %+v, want both by id desc", movies)
+	}
+	if got, _ := must2(s.Movies.ListPage(ctx, store.MovieFilter{TitlePrefix: "movie y"}, store.Page{}))(t); len(got) != 1 || got[0].ID != f.movieY {
+		t.Fatalf("title prefix = %+v", got)
+	}
+
+	cats := walk(t, 1, func(p store.Page) ([]models.Category, string, error) {
+		return s.Categories.ListPage(ctx, store.CategoryFilter{CeremonyID: f.ceremony}, p)
+	})
+	if len(cats) != 2 || cats[0].ID != f.catA || cats[1].ID != f.catB {
+		t.Fatalf("categories = %+v, want [A, B]", cats)
+	}
+
+	noms := walk(t, 2, func(p store.Page) ([]models.Nominated, string, error) {
+		return s.Nominated.ListPage(ctx, store.NominatedFilter{CeremonyID: f.ceremony}, p)
+	})
+	if len(noms) != 4 || noms[0].ID != f.nomB2 || noms[3].ID != f.nomA1 {
+		t.Fatalf("nominees = %+v, want 4 newest first", noms)
+	}
+	if got, _ := must2(s.Nominated.ListPage(ctx, store.NominatedFilter{CategoryID: f.catA, MovieID: f.movieX}, store.Page{}))(t); len(got) != 1 || got[0].ID != f.nomA1 {
+		t.Fatalf("category+movie filter = %+v", got)
+	}
+
+	votes := walk(t, 1, func(p store.Page) ([]models.Vote, string, error) {
+		return s.Votes.ListPage(ctx, store.VoteFilter{UserID: f.alice, CeremonyID: f.ceremony}, p)
+	})
+	if len(votes) != 2 || votes[0].NominatedID != f.nomB2 {
+		t.Fatalf("votes = %+v, want 2 most recent first", votes)
+	}
+	if got, _ := must2(s.Votes.ListPage(ctx, store.VoteFilter{UserID: f.alice, CategoryID: f.catA}, store.Page{}))(t); len(got) != 1 {
+		t.Fatalf("vote category filter = %+v", got)
+	}
+
+	scores := walk(t, 1, func(p store.Page) ([]store.UserScore, string, error) {
+		return s.Votes.ScoresPage(ctx, store.ScoreFilter{CeremonyID: f.ceremony}, p)
+	})
+	all := must(s.Votes.GetAllScores(ctx, f.ceremony))(t)
+	if len(scores) != 2 || len(all) != 2 || scores[0].UserID != all[0].UserID || scores[0].UserID != f.alice {
+		t.Fatalf("scores = %+v, want %+v", scores, all)
+	}
+	if got, _ := must2(s.Votes.ScoresPage(ctx, store.ScoreFilter{CeremonyID: f.ceremony, NicknamePrefix: "B"}, store.Page{}))(t); len(got) != 1 || got[0].UserID != f.bob {
+		t.Fatalf("scores nickname filter = %+v", got)
+	}
+
+	if _, _, err := s.Users.ListPage(ctx, store.UserFilter{}, store.Page{Cursor: "not a cursor"}); !errors.Is(err, store.ErrInvalidCursor) {
+		t.Fatalf("bad cursor: err = %v, want ErrInvalidCursor", err)
+	}
+}
+
+func testUsers(t *testing.T, s Stores) {
+	ctx := context.Background()
+	f := newFixture(t, s)
+	u := must(s.Users.GetByID(ctx, f.alice))(t)
+	if u == nil || u.Nickname != "alice" || u.Role != "user" || u.CreatedAt.IsZero() {
+		t.Fatalf("GetByID = %+v", u)
+	}
+	if u := must(s.Users.GetByEmail(ctx, "bob@example.com"))(t); u == nil || u.ID != f.bob {
+		t.Fatalf("GetByEmail = %+v", u)
+	}
+	if u := must(s.Users.GetByEmail(ctx, "nobody@example.com"))(t); u != nil {
+		t.Fatalf("GetByEmail(missing) = %+v", u)
+	}
+	if _, err := s.Users.Insert(ctx, &models.User{Nickname: "x", Email: "alice@example.com", PasswordHash: "x"}); err == nil {
+		t.Fatal("duplicate email: expected error")
+	}
+	if list := must(s.Users.List(ctx))(t); len(list) != 2 || list[0].ID != f.bob {
+		t.Fatalf("List = %+v, want newest first", list)
+	}
+
+	if ok := must(s.Users.PromoteFirstAdmin(ctx, f.alice))(t); !ok {
+		t.Fatal("first PromoteFirstAdmin should promote")
+	}
+	if ok := must(s.Users.PromoteFirstAdmin(ctx, f.bob))(t); ok {
+		t.Fatal("second PromoteFirstAdmin should not promote")
+	}
+	if err := s.Users.SetRole(ctx, f.bob, "admin"); err != nil {
+		t.Fatal(err)
+	}
+	if u := must(s.Users.GetByID(ctx, f.bob))(t); u.Role != "admin" {
+		t.Fatalf("role = %q", u.Role)
+	}
+	if err := s.Users.SetRole(ctx, "00000000-0000-0000-0000-000000000000", "admin"); !errors.Is(err, sql.ErrNoRows) {
+		t.Fatalf("SetRole(missing) = %v, want sql.ErrNoRows", err)
+	}
+}
+
+func testVoteUpsertAndHistory(t *testing.T, s Stores) {
+	ctx := context.Background()
+	f := newFixture(t, s)
+	vote := func(nom, cat string) (int64, string) {
+		t.Helper()
+		id, action, err := s.Votes.Insert(ctx, &models.Vote{UserID: f.alice, NominatedID: nom, CategoryID: cat})
+		if err != nil {
+			t.Fatalf("Insert: %v", err)
+		}
+		return id, action
+	}
+	id1, action := vote(f.nomA1, f.catA)
+	if action != models.VoteCreated {
+		t.Fatalf("first vote: action=%q, want %q", action, models.VoteCreated)
+	}
+	id2, action := vote(f.nomA2, f.catA)
+	if action != models.VoteChanged || id2 != id1 {
+		t.Fatalf("changed vote: id=%d action=%q, want id=%d action=%q", id2, action, id1, models.VoteChanged)
+	}
+	// same pick again: no history entry
+	if _, action := vote(f.nomA2, f.catA); action != store.VoteUnchanged {
+		t.Fatalf("repeated vote: action=%q, want %q", action, store.VoteUnchanged)
+	}
+	vote(f.nomB1, f.catB)
+	vote(f.otherN, f.otherCat)
+
+	v := must(s.Votes.Get(ctx, id1))(t)
+	if v == nil || v.NominatedID != f.nomA2 || v.UserID != f.alice {
+		t.Fatalf("Get = %+v", v)
+	}
+	if v := must(s.Votes.Get(ctx, -1))(t); v != nil {
+		t.Fatalf("Get(missing) = %+v", v)
+	}
+	votes := must(s.Votes.ListByUser(ctx, f.alice, f.ceremony))(t)
+	if len(votes) != 2 || votes[0].CategoryID != f.catB {
+		t.Fatalf("ListByUser = %+v, want 2 votes, most recent first", votes)
+	}
+
+	events := must(s.Votes.ListEvents(ctx, f.alice, f.ceremony))(t)
+	if len(events) != 3 {
+		t.Fatalf("ListEvents len = %d, want 3: %+v", len(events), events)
+	}
+	if e := events[0]; e.Action != models.VoteCreated || e.NominatedID != f.nomA1 || e.PreviousNominatedID != "" || e.VoteID != id1 {
+		t.Fatalf("events[0] = %+v", e)
+	}
+	if e := events[1]; e.Action != models.VoteChanged || e.NominatedID != f.nomA2 || e.PreviousNominatedID != f.nomA1 {
+		t.Fatalf("events[1] = %+v", e)
+	}
+	if e := events[2]; e.Action != models.VoteCreated || e.CategoryID != f.catB {
+		t.Fatalf("events[2] = %+v", e)
+	}
+	if _, _, err := s.Votes.Insert(ctx, &models.Vote{UserID: f.alice, NominatedID: "00000000-0000-0000-0000-000000000000", CategoryID: f.catA}); err == nil {
+		t.Fatal("unknown nominee: expected error")
+	}
+}
+
+func testBallot(t *testing.T, s Stores) {
+	ctx := context.Background()
+	f := newFixture(t, s)
+	for _, v := range []models.Vote{{UserID: f.alice, NominatedID: f.nomA1, CategoryID: f.catA}, {UserID: f.alice, NominatedID: f.nomB1, CategoryID: f.catB}} {
+		if _, _, err := s.Votes.Insert(ctx, &v); err != nil {
+			t.Fatalf("Insert: %v", err)
+		}
+	}
+
+	// one bad vote rolls the whole ballot back
+	bad := []models.Vote{
+		{UserID: f.alice, NominatedID: f.nomA2, CategoryID: f.catA},
+		{UserID: f.alice, NominatedID: "00000000-0000-0000-0000-000000000000", CategoryID: f.catB},
+	}
+	if _, err := s.Votes.InsertBallot(ctx, bad); err == nil {
+		t.Fatal("unknown nominee: expected error")
+	}
+	if v := must(s.Votes.ListByUser(ctx, f.alice, f.ceremony))(t); len(v) != 2 || v[0].NominatedID != f.nomB1 || v[1].NominatedID != f.nomA1 {
+		t.Fatalf("failed ballot changed votes: %+v", v)
+	}
+
+	ballot := []models.Vote{
+		{UserID: f.alice, NominatedID: f.nomA2, CategoryID: f.catA},
+		{UserID: f.alice, NominatedID: f.nomB1, CategoryID: f.catB},
+		{UserID: f.bob, NominatedID: f.nomA1, CategoryID: f.catA},
+	}
+	actions := must(s.Votes.InsertBallot(ctx, ballot))(t)
+	want := []string{models.VoteChanged, store.VoteUnchanged, models.VoteCreated}
+	if fmt.Sprint(actions) != fmt.Sprint(want) {
+		t.Fatalf("InsertBallot = %v, want %v", actions, want)
+	}
+	for _, b := range ballot {
+		if v := must(s.Votes.Get(ctx, b.ID))(t); v == nil || v.NominatedID != b.NominatedID || v.UserID != b.UserID {
+			t.Fatalf("vote %d = %+v, want %+v", b.ID, v, b)
+		}
+	}
+	// the unchanged pick adds no history
+	if events := must(s.Votes.ListEvents(ctx, f.alice, f.ceremony))(t); len(events) != 3 || events[2].Action != models.VoteChanged {
+		t.Fatalf("ListEvents = %+v", events)
+	}
+}
+
+func testScores(t *testing.T, s Stores) {
+	ctx := context.Background()
+	f := newFixture(t, s)
+	for _, v := range []models.Vote{
+		{UserID: f.alice, NominatedID: f.nomA1, CategoryID: f.catA},
+		{UserID: f.alice, NominatedID: f.nomB1, CategoryID: f.catB},
+		{UserID: f.bob, NominatedID: f.nomA1, CategoryID: f.catA},
+		{UserID: f.bob, NominatedID: f.nomB2, CategoryID: f.catB},
+		{UserID: f.bob, NominatedID: f.otherN, CategoryID: f.otherCat},
+	} {
+		v := v
+		if _, _, err := s.Votes.Insert(ctx, &v); err != nil {
+			t.Fatalf("Insert vote: %v", err)
+		}
+	}
+	if scores := must(s.Votes.GetAllScores(ctx, f.ceremony))(t); len(scores) != 2 || scores[0].Points != 0 {
+		t.Fatalf("scores before winners = %+v", scores)
+	}
+	must(s.Winners.Insert(ctx, &models.Winner{NominatedID: f.nomA1}))(t)
+	must(s.Winners.Insert(ctx, &models.Winner{NominatedID: f.nomB1}))(t)
+	must(s.Winners.Insert(ctx, &models.Winner{NominatedID: f.otherN}))(t)
+
+	points, maxPoints, err := s.Votes.GetUserScore(ctx, f.alice, f.ceremony)
+	if err != nil || points != 4 || maxPoints != 4 {
+		t.Fatalf("alice score = %d/%d (%v), want 4/4", points, maxPoints, err)
+	}
+	points, maxPoints, err = s.Votes.GetUserScore(ctx, f.bob, f.ceremony)
+	if err != nil || points != 1 || maxPoints != 4 {
+		t.Fatalf("bob score = %d/%d (%v), want 1/4", points, maxPoints, err)
+	}
+	scored := must(s.Votes.ScoredVotes(ctx, f.bob, f.ceremony))(t)
+	sort.Slice(scored, func(i, j int) bool { return scored[i].Weight < scored[j].Weight })
+	wantScored := []store.ScoredVote{
+		{CategoryID: f.catA, NominatedID: f.nomA1, Weight: 1, Correct: true, Points: 1},
+		{CategoryID: f.catB, NominatedID: f.nomB2, Weight: 3},
+	}
+	if len(scored) != 2 || scored[0] != wantScored[0] || scored[1] != wantScored[1] {
+		t.Fatalf("ScoredVotes = %+v, want %+v", scored, wantScored)
+	}
+
+	scores := must(s.Votes.GetAllScores(ctx, f.ceremony))(t)
+	want := []store.UserScore{
+		{UserID: f.alice, Nickname: "alice", CorrectVotes: 2, TotalVotes: 2, Points: 4, MaxPoints: 4},
+		{UserID: f.bob, Nickname: "bob", CorrectVotes: 1, TotalVotes: 2, Points: 1, MaxPoints: 4},
+	}
+	if len(scores) != 2 || scores[0] != want[0] || scores[1] != want[1] {
+		t.Fatalf("GetAllScores = %+v, want %+v", scores, want)
+	}
+	// changing the weights changes the ranking input immediately
+	if err := s.Categories.SetPoints(ctx, f.ceremony, map[string]int{f.catB: 10}); err != nil {
+		t.Fatal(err)
+	}
+	if scores := must(s.Votes.GetAllScores(ctx, f.ceremony))(t); scores[0].Points != 11 || scores[0].MaxPoints != 11 {
+		t.Fatalf("after SetPoints = %+v", scores[0])
+	}
+
+	league := must(s.Leagues.Insert(ctx, &models.League{Name: "Office", OwnerID: f.bob}))(t)
+	ls := must(s.Votes.GetLeagueScores(ctx, league, f.ceremony))(t)
+	if len(ls) != 1 || ls[0].UserID != f.bob {
+		t.Fatalf("GetLeagueScores = %+v, want only bob", ls)
+	}
+	if empty := must(s.Votes.GetAllScores(ctx, f.otherCeremony))(t); len(empty) != 1 || empty[0].UserID != f.bob {
+		t.Fatalf("scores of other ceremony = %+v", empty)
+	}
+}
+
+func testWinners(t *testing.T, s Stores) {
+	ctx := context.Background()
+	f := newFixture(t, s)
+	id := must(s.Winners.Insert(ctx, &models.Winner{NominatedID: f.nomA1}))(t)
+	other := must(s.Winners.Insert(ctx, &models.Winner{NominatedID: f.otherN}))(t)
+	if w := must(s.Winners.Get(ctx, id))(t); w == nil || w.NominatedID != f.nomA1 {
+		t.Fatalf("Get = %+v", w)
+	}
+	if w := must(s.Winners.GetByNominated(ctx, f.nomA1))(t); w == nil || w.ID != id {
+		t.Fatalf("GetByNominated = %+v", w)
+	}
+	if w := must(s.Winners.GetByNominated(ctx, f.nomA2))(t); w != nil {
+		t.Fatalf("GetByNominated(no winner) = %+v", w)
+	}
+	if _, err := s.Winners.Insert(ctx, &models.Winner{NominatedID: f.nomA1}); err == nil {
+		t.Fatal("duplicate winner: expected error")
+	}
+	if list := must(s.Winners.List(ctx, f.ceremony))(t); len(list) != 1 || list[0].ID != id {
+		t.Fatalf("List = %+v", list)
+	}
+	if err := s.Winners.Delete(ctx, id); err != nil {
+		t.Fatal(err)
+	}
+	if w := must(s.Winners.Get(ctx, id))(t); w != nil {
+		t.Fatalf("Get after Delete = %+v", w)
+	}
+	if list := must(s.Winners.List(ctx, f.ceremony))(t); len(list) != 0 {
+		t.Fatalf("List after Delete = %+v", list)
+	}
+	if list := must(s.Winners.List(ctx, f.otherCeremony))(t); len(list) != 1 || list[0].ID != other {
+		t.Fatalf("List(other ceremony) = %+v", list)
+	}
+}
+
+func testLeagues(t *testing.T, s Stores) {
+	ctx := context.Background()
+	f := newFixture(t, s)
+	l := &models.League{Name: "Office", OwnerID: f.alice}
+	id := must(s.Leagues.Insert(ctx, l))(t)
+	if l.InviteCode == "" {
+		t.Fatal("Insert should generate an invite code")
+	}
+	got := must(s.Leagues.GetByInviteCode(ctx, l.InviteCode))(t)
+	if got == nil || got.ID != id || got.OwnerID != f.alice {
+		t.Fatalf("GetByInviteCode = %+v", got)
+	}
+	if got := must(s.Leagues.GetByInviteCode(ctx, "NOPE"))(t); got != nil {
+		t.Fatalf("GetByInviteCode(missing) = %+v", got)
+	}
+	if !must(s.Leagues.IsMember(ctx, id, f.alice))(t) {
+		t.Fatal("owner should be a member")
+	}
+	if must(s.Leagues.IsMember(ctx, id, f.bob))(t) {
+		t.Fatal("bob should not be a member yet")
+	}
+	if err := s.Leagues.AddMember(ctx, id, f.bob); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Leagues.AddMember(ctx, id, f.bob); err != nil {
+		t.Fatalf("joining twice should be a no-op: %v", err)
+	}
+	must(s.Leagues.Insert(ctx, &models.League{Name: "Family", OwnerID: f.bob}))(t)
+	if list := must(s.Leagues.ListByUser(ctx, f.bob))(t); len(list) != 2 || list[0].Name != "Family" {
+		t.Fatalf("ListByUser = %+v, want [Family, Office]", list)
+	}
+	if err := s.Leagues.RemoveMember(ctx, id, f.bob); err != nil {
+		t.Fatal(err)
+	}
+	if must(s.Leagues.IsMember(ctx, id, f.bob))(t) {
+		t.Fatal("bob should have left")
+	}
+	if list := must(s.Leagues.ListByUser(ctx, f.bob))(t); len(list) != 1 {
+		t.Fatalf("ListByUser after leave = %+v", list)
+	}
+}
+
+func testSessions(t *testing.T, s Stores) {
+	ctx := context.Background()
+	f := newFixture(t, s)
+	exp := time.Now().Add(time.Hour)
+	id := must(s.Sessions.Insert(ctx, &models.Session{UserID: f.alice, TokenHash: "h1", UserAgent: "test", ExpiresAt: exp}))(t)
+	must(s.Sessions.Insert(ctx, &models.Session{UserID: f.alice, TokenHash: "other", ExpiresAt: exp}))(t)
+	sess := must(s.Sessions.Get(ctx, id))(t)
+	if sess == nil || sess.UserID != f.alice || sess.TokenHash != "h1" || sess.UserAgent != "test" || !sess.IsActive(time.Now()) {
+		t.Fatalf("Get = %+v", sess)
+	}
+	if _, err := s.Sessions.Insert(ctx, &models.Session{UserID: f.bob, TokenHash: "h1", ExpiresAt: exp}); err == nil {
+		t.Fatal("duplicate token hash: expected error")
+	}
+
+	if err := s.Sessions.Rotate(ctx, id, "h1", "h2", exp.Add(time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	// the old token no longer rotates, but still finds the session (reuse detection)
+	if err := s.Sessions.Rotate(ctx, id, "h1", "h3", exp); !errors.Is(err, sql.ErrNoRows) {
+		t.Fatalf("Rotate(stale) = %v, want sql.ErrNoRows", err)
+	}
+	for _, h := range []string{"h1", "h2"} {
+		if got := must(s.Sessions.GetByTokenHash(ctx, h))(t); got == nil || got.ID != id || got.TokenHash != "h2" || got.PreviousTokenHash != "h1" {
+			t.Fatalf("GetByTokenHash(%s) = %+v", h, got)
+		}
+	}
+	if got := must(s.Sessions.GetByTokenHash(ctx, "nope"))(t); got != nil {
+		t.Fatalf("GetByTokenHash(unknown) = %+v", got)
+	}
+
+	if list := must(s.Sessions.ListByUser(ctx, f.alice))(t); len(list) != 2 || list[0].ID != id {
+		t.Fatalf("ListByUser = %+v, want rotated session first", list)
+	}
+	if err := s.Sessions.Revoke(ctx, id); err != nil {
+		t.Fatal(err)
+	}
+	if got := must(s.Sessions.Get(ctx, id))(t); got.RevokedAt == nil || got.IsActive(time.Now()) {
+		t.Fatalf("after Revoke = %+v", got)
+	}
+	if err := s.Sessions.Rotate(ctx, id, "h2", "h4", exp); !errors.Is(err, sql.ErrNoRows) {
+		t.Fatalf("Rotate(revoked) = %v, want sql.ErrNoRows", err)
+	}
+	if err := s.Sessions.Revoke(ctx, "00000000-0000-0000-0000-000000000000"); !errors.Is(err, sql.ErrNoRows) {
+		t.Fatalf("Revoke(missing) = %v, want sql.ErrNoRows", err)
+	}
+	if n := must(s.Sessions.RevokeAllByUser(ctx, f.alice))(t); n != 1 {
+		t.Fatalf("RevokeAllByUser = %d, want 1", n)
+	}
+	if list := must(s.Sessions.ListByUser(ctx, f.alice))(t); len(list) != 0 {
+		t.Fatalf("ListByUser after revoke all = %+v", list)
+	}
+}
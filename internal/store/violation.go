@@ -0,0 +1,39 @@
+package store
+
+import (
+	"errors"
+
+	"github.com/lib/pq"
+)
+
+// Constraint violations, returned (wrapped) by the memory stores and matched
+// by Violation for both backends.
+var (
+	ErrUniqueViolation     = errors.New("unique constraint violation")
+	ErrForeignKeyViolation = errors.New("foreign key violation")
+	ErrCheckViolation      = errors.New("check constraint violation")
+)
+
+// Violation returns the Err*Violation an error stands for, whether it came from
+// Postgres or from the memory stores, or nil when it is not a constraint
+// violation. Callers use it to map errors without exposing driver messages.
+func Violation(err error) error {
+	var pe *pq.Error
+	if errors.As(err, &pe) {
+		switch pe.Code {
+		case "23505":
+			return ErrUniqueViolation
+		case "23503":
+			return ErrForeignKeyViolation
+		case "23514":
+			return ErrCheckViolation
+		}
+		return nil
+	}
+	for _, v := range []error{ErrUniqueViolation, ErrForeignKeyViolation, ErrCheckViolation} {
+		if errors.Is(err, v) {
+			return v
+		}
+	}
+	return nil
+}
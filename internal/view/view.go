@@ -0,0 +1,100 @@
+// Package view renders the HTML pages. Every page file defines "title",
+// "content" and optionally "head", and is executed through the "layout"
+// template of layout.html with the partials of partials/ available.
+package view
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"io"
+	"io/fs"
+	"sync"
+)
+
+const (
+	layoutFile  = "layout.html"
+	partialGlob = "partials/*.html"
+)
+
+// Set is the parsed page templates. It is safe for concurrent use.
+type Set struct {
+	fsys   fs.FS
+	reload bool
+
+	mu    sync.RWMutex
+	pages map[string]*template.Template
+}
+
+// New parses every page in fsys once. With reload set the files are parsed
+// again on every Render, so template edits show without a restart; it is
+// meant for development with fsys pointing at the templates directory.
+func New(fsys fs.FS, reload bool) (*Set, error) {
+	s := &Set{fsys: fsys, reload: reload}
+	pages, err := parse(fsys)
+	if err != nil {
+		return nil, err
+	}
+	s.pages = pages
+	return s, nil
+}
+
+// Render executes page (a file name such as "profile_view.html") with data
+// and writes it to w. The page is rendered to a buffer first, so on error
+// nothing has been written.
+func (s *Set) Render(w io.Writer, page string, data interface{}) error {
+	if s.reload {
+		pages, err := parse(s.fsys)
+		if err != nil {
+			return err
+		}
+		s.mu.Lock()
+		s.pages = pages
+		s.mu.Unlock()
+	}
+	s.mu.RLock()
+	t, ok := s.pages[page]
+	s.mu.RUnlock()
+	if !ok {
+		return fmt.Errorf("view: no page %q", page)
+	}
+	var buf bytes.Buffer
+	if err := t.ExecuteTemplate(&buf, "layout", data); err != nil {
+		return fmt.Errorf("view: render %s: %w", page, err)
+	}
+	_, err := buf.WriteTo(w)
+	return err
+}
+
+// parse builds one template per page: a clone of the layout and partials
+// with the page parsed into it, since all pages define the same names.
+func parse(fsys fs.FS) (map[string]*template.Template, error) {
+	base, err := template.ParseFS(fsys, layoutFile, partialGlob)
+	if err != nil {
+		return nil, fmt.Errorf("view: parse layout: %w", err)
+	}
+	files, err := fs.Glob(fsys, "*.html")
+	if err != nil {
+		return nil, err
+	}
+	pages := make(map[string]*template.Template)
+	for _, f := range files {
+		if f == layoutFile {
+			continue
+		}
+		t, err := base.Clone()
+		if err != nil {
+			return nil, err
+		}
+		if _, err := t.ParseFS(fsys, f); err != nil {
+			return nil, fmt.Errorf("view: parse %s: %w", f, err)
+		}
+		for _, name := range []string{"title", "content"} {
+			if t.Lookup(name) == nil {
+				return nil, fmt.Errorf("view: %s does not define %q", f, name)
+			}
+		}
+		pages[f] = t
+	}
+	return pages, nil
+}
@@ -0,0 +1,57 @@
+package view
+
+import (
+	"bytes"
+	"testing"
+	"testing/fstest"
+
+	"votacao/templates"
+)
+
+func TestEmbeddedPagesParse(t *testing.T) {
+	s, err := New(templates.FS, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, page := range []string{"categories_view.html", "leaderboard_view.html", "login_form.html", "nominated_form.html",
+		"nominateds_view.html", "participants_view.html", "profile_view.html", "winners_view.html"} {
+		if s.pages[page] == nil {
+			t.Errorf("%s not parsed", page)
+		}
+	}
+}
+
+func TestReload(t *testing.T) {
+	fsys := fstest.MapFS{
+		"layout.html":     {Data: []byte(`{{ define "layout" }}<title>{{ template "title" . }}</title>{{ template "content" . }}{{ end }}`)},
+		"partials/x.html": {Data: []byte(`{{ define "x" }}x{{ end }}`)},
+		"page.html":       {Data: []byte(`{{ define "title" }}T{{ end }}{{ define "content" }}one {{ template "x" }}{{ end }}`)},
+	}
+	s, err := New(fsys, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err := s.Render(&buf, "page.html", nil); err != nil || buf.String() != "<title>T</title>one x" {
+		t.Fatalf("got %q, %v", buf.String(), err)
+	}
+	fsys["page.html"] = &fstest.MapFile{Data: []byte(`{{ define "title" }}T{{ end }}{{ define "content" }}two{{ end }}`)}
+	buf.Reset()
+	if err := s.Render(&buf, "page.html", nil); err != nil || buf.String() != "<title>T</title>two" {
+		t.Fatalf("edit not picked up: %q, %v", buf.String(), err)
+	}
+	if err := s.Render(&buf, "missing.html", nil); err == nil {
+		t.Fatal("unknown page rendered")
+	}
+}
+
+func TestPageMustDefineContent(t *testing.T) {
+	fsys := fstest.MapFS{
+		"layout.html":     {Data: []byte(`{{ define "layout" }}{{ template "content" . }}{{ end }}`)},
+		"partials/x.html": {Data: []byte(`{{ define "x" }}x{{ end }}`)},
+		"page.html":       {Data: []byte(`{{ define "title" }}T{{ end }}`)},
+	}
+	if _, err := New(fsys, false); err == nil {
+		t.Fatal("page without content accepted")
+	}
+}
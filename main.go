@@ -1,157 +1,164 @@
 package main
 
 import (
+	"context"
 	"fmt"
-	"html/template"
+	"io/fs"
 	"log"
+	"log/slog"
 	"net/http"
 	"os"
-	"path/filepath"
+	"os/signal"
+	"syscall"
+	"time"
 
 	_ "github.com/lib/pq"
 
+	"votacao/internal/config"
 	"votacao/internal/db"
 	"votacao/internal/handler"
+	"votacao/internal/logging"
+	"votacao/internal/metrics"
 	"votacao/internal/store"
+	"votacao/internal/store/memstore"
+	"votacao/internal/view"
+	"votacao/models"
+	"votacao/templates"
 )
 
 func main() {
-	// Prefer a full DATABASE_URL (postgresql://...) if provided. Otherwise
-	// build a lib/pq style DSN from individual PG_* environment variables.
-	dsn := envOr("DATABASE_URL", "")
-	if dsn == "" {
-		dsn = fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=disable",
-			envOr("PG_HOST", "localhost"),
-			envOr("PG_PORT", "5432"),
-			envOr("PG_USER", "postgres"),
-			envOr("PG_PASSWORD", "postgres"),
-			envOr("PG_DB", "moviesdb"),
-		)
-	}
+	// a failed server exits 1 once the other deferred calls (the DB pool) have run
+	var exitCode int
+	defer func() {
+		if exitCode != 0 {
+			os.Exit(exitCode)
+		}
+	}()
 
-	database, err := db.Open(dsn)
+	// settings come from the environment, optionally on top of a JSON file
+	// named by CONFIG_FILE; see internal/config for every key
+	cfg, err := config.Load(os.Getenv("CONFIG_FILE"))
 	if err != nil {
-		log.Fatalf("failed to open db: %v", err)
+		log.Fatal(err)
 	}
-	defer database.Close()
+	dsn := cfg.DB.DSN()
 
-	// wire store and handlers
-	s := store.NewSQL(database)
-	cs := store.NewSQLCategory(database)
-	ns := store.NewSQLNominated(database)
-	us := store.NewSQLUser(database)
-	vs := store.NewSQLVote(database)
-	ws := store.NewSQLWinnerStore(database)
-	// parse and cache nominated form template at startup
-	// try env var TEMPLATE_DIR, then relative "templates/", then absolute "/templates/"
-	var tpl *template.Template
-	var parseErr error
-	tryPaths := []string{}
-	if td := os.Getenv("TEMPLATE_DIR"); td != "" {
-		tryPaths = append(tryPaths, filepath.Join(td, "nominated_form.html"))
-	}
-	tryPaths = append(tryPaths, "templates/nominated_form.html", "/templates/nominated_form.html")
-	for _, p := range tryPaths {
-		if _, err := os.Stat(p); err == nil {
-			tpl, parseErr = template.ParseFiles(p)
-			if parseErr == nil {
-				break
-			}
-		}
+	// `votacao migrate up|down|status|redo` manages the schema and exits
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		os.Exit(runMigrate(dsn, os.Args[2:]))
 	}
-	if tpl == nil && parseErr != nil {
-		log.Fatalf("failed to parse template from paths %v: %v", tryPaths, parseErr)
-	}
-	jwtSecret := envOr("JWT_SECRET", "devsecret")
-	h := handler.New(s, cs, ns, us, vs, ws, tpl, jwtSecret)
 
-	http.HandleFunc("/add_movie", h.AddMovie)
-	http.HandleFunc("/add_movies", h.AddMovies)
-	http.HandleFunc("/add_category", h.AddCategory)
-	http.HandleFunc("/add_categories", h.AddCategories)
-	http.HandleFunc("/movies", func(w http.ResponseWriter, r *http.Request) {
-		// if query param id present, serve GetMovie, else ListMovies
-		if r.URL.Query().Get("id") != "" {
-			h.GetMovie(w, r)
-			return
+	// structured logs on stdout; the standard log package is routed through it too
+	logger, err := logging.New(os.Stdout, cfg.Log.Format, cfg.Log.Level)
+	if err != nil {
+		log.Fatal(err)
+	}
+	slog.SetDefault(logger)
+
+	// wire store and handlers; STORE=memory runs without Postgres (nothing is persisted)
+	var (
+		s    store.MovieStore
+		cs   store.CategoryStore
+		ns   store.NominatedStore
+		us   store.UserStore
+		vs   store.VoteStore
+		ws   store.WinnerStore
+		cers store.CeremonyStore
+		ls   store.LeagueStore
+		ss   store.SessionStore
+	)
+	switch cfg.Store {
+	case "memory":
+		mem := memstore.New()
+		s, cs, ns, us = memstore.NewMovie(mem), memstore.NewCategory(mem), memstore.NewNominated(mem), memstore.NewUser(mem)
+		vs, ws, cers, ls = memstore.NewVote(mem), memstore.NewWinner(mem), memstore.NewCeremony(mem), memstore.NewLeague(mem)
+		ss = memstore.NewSession(mem)
+		// start with an open ceremony so the pages work out of the box
+		year, deadline := time.Now().Year(), cfg.Voting.Deadline
+		if deadline.IsZero() {
+			deadline = time.Now().AddDate(0, 1, 0)
 		}
-		h.ListMovies(w, r)
-	})
-
-	http.HandleFunc("/categories", func(w http.ResponseWriter, r *http.Request) {
-		// if query param id present, serve GetCategory, else ListCategories
-		if r.URL.Query().Get("id") != "" {
-			h.GetCategory(w, r)
-			return
+		if _, err := cers.Insert(context.Background(), &models.Ceremony{Year: year, Name: fmt.Sprintf("Oscar %d", year), Deadline: deadline, Status: models.CeremonyActive}); err != nil {
+			fatal("seed memory store", err)
 		}
-		h.ListCategories(w, r)
-	})
-
-	// nomination form and create-from-form endpoints
-	http.HandleFunc("/nominated/new", h.ServeNominatedForm)
-	http.HandleFunc("/nominated/create", h.CreateNominatedFromForm)
-	http.HandleFunc("/login/new", h.ServeLoginForm)
-	http.HandleFunc("/categories/view", h.ServeCategoriesView)
-	http.HandleFunc("/profile", h.ServeProfileView)
-	http.HandleFunc("/users", h.ListUsers)
-	http.HandleFunc("/participants", h.ServeParticipantsView)
-	http.HandleFunc("/nominateds/view", h.ServeNominatedsView)
-	http.HandleFunc("/nominateds/by_category", h.ListNominatedsByCategory)
-	http.HandleFunc("/nominees_by_category", h.NomineesByCategory)
-	http.HandleFunc("/add_nominateds_names", h.AddNominatedsByNames)
-	// JSON API endpoints for nominations
-	http.HandleFunc("/add_nominated", h.AddNominated)
-	http.HandleFunc("/add_nominateds", h.AddNominateds)
-	http.HandleFunc("/nominateds", func(w http.ResponseWriter, r *http.Request) {
-		if r.URL.Query().Get("id") != "" {
-			h.GetNominated(w, r)
-			return
+		slog.Info("STORE=memory: using in-memory stores, data is lost on exit")
+	case "postgres":
+		database, err := db.Open(dsn, db.Pool{
+			MaxOpenConns:    cfg.DB.MaxOpenConns,
+			MaxIdleConns:    cfg.DB.MaxIdleConns,
+			ConnMaxLifetime: time.Duration(cfg.DB.ConnMaxLifetime),
+			ConnMaxIdleTime: time.Duration(cfg.DB.ConnMaxIdleTime),
+		})
+		if err != nil {
+			fatal("failed to open db", err)
 		}
-		h.ListNominateds(w, r)
-	})
-
-	// auth routes
-	http.HandleFunc("/register", h.Register)
-	http.HandleFunc("/login", h.Login)
-	http.HandleFunc("/logout", h.Logout)
-	http.HandleFunc("/me", h.RequireAuth(h.Me))
-
-	// voting routes (require auth)
-	http.HandleFunc("/add_vote", h.RequireAuth(h.AddVote))
-	http.HandleFunc("/votes", h.RequireAuth(h.ListVotes))
-
-	// score routes
-	http.HandleFunc("/score", h.RequireAuth(h.GetMyScore))
-	http.HandleFunc("/leaderboard", h.GetLeaderboard)
-	http.HandleFunc("/leaderboard/view", h.ServeLeaderboardView)
-
-	// winner routes (admin)
-	http.HandleFunc("/winners/view", h.ServeWinnersView)
-	http.HandleFunc("/add_winner", h.AddWinner)
-	http.HandleFunc("/delete_winner", h.DeleteWinner)
-	http.HandleFunc("/winners", h.ListWinners)
-
-	// deadline endpoint (public)
-	http.HandleFunc("/deadline", h.GetDeadline)
-
-	// health check
-	http.HandleFunc("/healthz", h.Healthz)
-
-	// root -> redirect to categories view
-	http.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
-		// redirect home to categories view
-		http.Redirect(w, r, "/categories/view", http.StatusSeeOther)
+		// closed after the server has drained, so in-flight requests keep their connections
+		defer database.Close()
+		s, cs, ns, us = store.NewSQL(database), store.NewSQLCategory(database), store.NewSQLNominated(database), store.NewSQLUser(database)
+		vs, ws, cers, ls = store.NewSQLVote(database), store.NewSQLWinnerStore(database), store.NewSQLCeremony(database), store.NewSQLLeague(database)
+		ss = store.NewSQLSession(database)
+		metrics.Default.RegisterDBStats(database)
+	}
+	// time every store call for /metrics
+	s, cs, ns, us = store.MeteredMovie(s), store.MeteredCategory(cs), store.MeteredNominated(ns), store.MeteredUser(us)
+	vs, ws, cers, ls = store.MeteredVote(vs), store.MeteredWinner(ws), store.MeteredCeremony(cers), store.MeteredLeague(ls)
+	ss = store.MeteredSession(ss)
+	// pages are parsed once from the embedded templates, unless a directory
+	// or reload mode (development) is configured
+	var tplFS fs.FS = templates.FS
+	if dir := cfg.Templates.Dir; dir != "" {
+		tplFS = os.DirFS(dir)
+	} else if cfg.Templates.Reload {
+		tplFS = os.DirFS("templates")
+	}
+	pages, err := view.New(tplFS, cfg.Templates.Reload)
+	if err != nil {
+		fatal("failed to parse templates", err)
+	}
+	h := handler.New(s, cs, ns, us, vs, ws, cers, ls, ss, pages, cfg.Secrets.JWT)
+	h.Configure(handler.Settings{
+		DBTimeout:      time.Duration(cfg.DB.QueryTimeout),
+		CookieSecure:   cfg.Cookies.Secure,
+		Location:       cfg.Voting.Location(),
+		BootstrapToken: cfg.Secrets.AdminBootstrapToken,
+		MetricsToken:   cfg.Metrics.Token,
 	})
+	if cfg.Env != config.Production && cfg.Secrets.JWT == config.DefaultJWTSecret {
+		slog.Warn("JWT_SECRET is the development default; set APP_ENV=production to refuse it")
+	}
 
-	addr := envOr("HTTP_ADDR", ":8080")
-	log.Printf("listening on %s", addr)
-	log.Fatal(http.ListenAndServe(addr, nil))
+	// routes (the /api/v1 API, the pages and the deprecated legacy aliases) are in handler.Routes
+	srv := &http.Server{
+		Addr:              cfg.HTTP.Addr,
+		Handler:           h.Routes(),
+		ReadTimeout:       time.Duration(cfg.HTTP.ReadTimeout),
+		ReadHeaderTimeout: time.Duration(cfg.HTTP.ReadHeaderTimeout),
+		WriteTimeout:      time.Duration(cfg.HTTP.WriteTimeout),
+		IdleTimeout:       time.Duration(cfg.HTTP.IdleTimeout),
+	}
+	// Shutdown does not wait for the event streams; end them so it need not time out
+	srv.RegisterOnShutdown(h.Close)
+
+	// SIGTERM (docker stop, a redeploy) and Ctrl-C drain in-flight requests
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	// METRICS_ADDR serves /metrics on a separate (e.g. loopback-only) listener
+	var metricsSrv *http.Server
+	if cfg.Metrics.Addr != "" {
+		mux := http.NewServeMux()
+		mux.HandleFunc("/metrics", h.ServeMetrics)
+		metricsSrv = &http.Server{Addr: cfg.Metrics.Addr, Handler: mux, ReadHeaderTimeout: 5 * time.Second}
+	}
+	if err := serve(ctx, srv, metricsSrv, cfg.HTTP); err != nil {
+		slog.Error("server", "err", err)
+		exitCode = 1
+		return
+	}
+	slog.Info("stopped")
 }
 
-func envOr(key, def string) string {
-	v := os.Getenv(key)
-	if v == "" {
-		return def
-	}
-	return v
+// fatal logs err and exits; like log.Fatal, deferred calls do not run.
+func fatal(msg string, err error, args ...any) {
+	slog.Error(msg, append([]any{"err", err}, args...)...)
+	os.Exit(1)
 }
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"votacao/internal/db"
+)
+
+const migrateUsage = "usage: votacao migrate up|down|status|redo"
+
+// runMigrate implements the migrate subcommand and returns the exit code.
+func runMigrate(dsn string, args []string) int {
+	if len(args) != 1 {
+		fmt.Fprintln(os.Stderr, migrateUsage)
+		return 2
+	}
+	database, err := db.Connect(dsn)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to open db: %v\n", err)
+		return 1
+	}
+	defer database.Close()
+	m, err := db.NewMigrator(database)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "load migrations: %v\n", err)
+		return 1
+	}
+
+	switch args[0] {
+	case "up":
+		applied, err := m.Up()
+		for _, v := range applied {
+			fmt.Println("applied", v)
+		}
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		if len(applied) == 0 {
+			fmt.Println("nothing to apply")
+		}
+	case "down":
+		v, err := m.Down()
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		if v == "" {
+			fmt.Println("nothing to roll back")
+		} else {
+			fmt.Println("rolled back", v)
+		}
+	case "redo":
+		v, err := m.Redo()
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		if v == "" {
+			fmt.Println("nothing to redo")
+		} else {
+			fmt.Println("redone", v)
+		}
+	case "status":
+		st, err := m.Status()
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		for _, s := range st {
+			state := "pending"
+			if s.Applied {
+				state = "applied " + s.AppliedAt.Local().Format("2006-01-02 15:04:05")
+			}
+			if s.Modified {
+				state += " (checksum mismatch)"
+			}
+			fmt.Printf("%-45s %s\n", s.Version, state)
+		}
+	default:
+		fmt.Fprintln(os.Stderr, migrateUsage)
+		return 2
+	}
+	return 0
+}
@@ -1 +0,0 @@
-ALTER TABLE categories ADD COLUMN IF NOT EXISTS sequence_order INTEGER DEFAULT 0;
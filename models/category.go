@@ -1,8 +1,22 @@
 package models
 
+import "time"
+
 // Category represents a simple category with UUID and Name.
 type Category struct {
 	ID            string `json:"id,omitempty"`
+	CeremonyID    string `json:"ceremony_id,omitempty"`
 	Name          string `json:"name"`
 	SequenceOrder int    `json:"sequence_order,omitempty"`
+	// Points is the score weight of a correct pick in this category.
+	Points int `json:"points"`
+	// LocksAt optionally schedules when voting in this category closes.
+	LocksAt *time.Time `json:"locks_at,omitempty"`
+	// Locked is set by an admin when the category is announced.
+	Locked bool `json:"locked"`
+}
+
+// IsLocked reports whether votes in the category are closed at the given time.
+func (c *Category) IsLocked(now time.Time) bool {
+	return c.Locked || (c.LocksAt != nil && !now.Before(*c.LocksAt))
 }
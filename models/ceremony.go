@@ -0,0 +1,22 @@
+package models
+
+import "time"
+
+// Ceremony statuses. Exactly one ceremony is "active" at a time; it is the one
+// the pool votes on. Past ceremonies are "archived" so their history is kept.
+const (
+	CeremonyUpcoming = "upcoming"
+	CeremonyActive   = "active"
+	CeremonyArchived = "archived"
+)
+
+// Ceremony represents one edition of the awards (e.g. the 2026 Oscars).
+// Categories belong to a ceremony; nominees, votes and winners are scoped
+// through their category.
+type Ceremony struct {
+	ID       string    `json:"id,omitempty"`
+	Year     int       `json:"year"`
+	Name     string    `json:"name"`
+	Deadline time.Time `json:"deadline"`
+	Status   string    `json:"status,omitempty"`
+}
@@ -0,0 +1,13 @@
+package models
+
+import "time"
+
+// League is a private pool (e.g. one per office). Users join with the invite
+// code and get a leaderboard restricted to the league's members.
+type League struct {
+	ID         string    `json:"id,omitempty"`
+	Name       string    `json:"name"`
+	OwnerID    string    `json:"owner_id"`
+	InviteCode string    `json:"invite_code,omitempty"`
+	CreatedAt  time.Time `json:"created_at,omitempty"`
+}
@@ -0,0 +1,27 @@
+package models
+
+import "time"
+
+// Session is one login (a browser or API client). It holds the hash of the
+// current refresh token; access tokens carry the session id in the "sid"
+// claim and stop working as soon as the session is revoked.
+type Session struct {
+	ID         string     `json:"id"`
+	UserID     string     `json:"user_id"`
+	UserAgent  string     `json:"user_agent,omitempty"`
+	IP         string     `json:"ip,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+	LastUsedAt time.Time  `json:"last_used_at"`
+	ExpiresAt  time.Time  `json:"expires_at"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+	// TokenHash is the sha256 of the current refresh token. PreviousTokenHash
+	// is kept after a rotation to detect reuse of a stolen token.
+	TokenHash         string    `json:"-"`
+	PreviousTokenHash string    `json:"-"`
+	RotatedAt         time.Time `json:"-"`
+}
+
+// IsActive reports whether the session can still be used at the given time.
+func (s *Session) IsActive(now time.Time) bool {
+	return s.RevokedAt == nil && now.Before(s.ExpiresAt)
+}
@@ -0,0 +1,22 @@
+package models
+
+import "time"
+
+// Vote event actions.
+const (
+	VoteCreated = "created"
+	VoteChanged = "changed"
+)
+
+// VoteEvent is one append-only entry of a user's voting history. A "changed"
+// event records the pick that was replaced in PreviousNominatedID.
+type VoteEvent struct {
+	ID                  int64     `json:"id"`
+	VoteID              int64     `json:"vote_id"`
+	UserID              string    `json:"user_id"`
+	CategoryID          string    `json:"category_id"`
+	NominatedID         string    `json:"nominated_id"`
+	PreviousNominatedID string    `json:"previous_nominated_id,omitempty"`
+	Action              string    `json:"action"`
+	CreatedAt           time.Time `json:"created_at"`
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"votacao/internal/config"
+)
+
+// serve runs srv, over TLS when a certificate is configured, and the optional
+// metrics server until ctx is cancelled. It then stops accepting connections
+// and waits up to ShutdownTimeout (no limit when zero) for in-flight requests
+// to finish.
+func serve(ctx context.Context, srv, metricsSrv *http.Server, cfg config.HTTP) error {
+	errc := make(chan error, 2)
+	if metricsSrv != nil {
+		go func() {
+			slog.Info("serving metrics", "addr", metricsSrv.Addr)
+			if err := metricsSrv.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+				errc <- fmt.Errorf("metrics: %w", err)
+			}
+		}()
+	}
+	go func() {
+		if cfg.TLSCertFile != "" {
+			srv.TLSConfig = &tls.Config{MinVersion: tls.VersionTLS12}
+			slog.Info("listening", "addr", srv.Addr, "tls", true)
+			errc <- srv.ListenAndServeTLS(cfg.TLSCertFile, cfg.TLSKeyFile)
+			return
+		}
+		slog.Info("listening", "addr", srv.Addr, "tls", false)
+		errc <- srv.ListenAndServe()
+	}()
+
+	select {
+	case err := <-errc:
+		return err
+	case <-ctx.Done():
+	}
+
+	sctx := context.Background()
+	if timeout := time.Duration(cfg.ShutdownTimeout); timeout > 0 {
+		slog.Info("shutting down; draining requests", "timeout", timeout.String())
+		var cancel context.CancelFunc
+		sctx, cancel = context.WithTimeout(sctx, timeout)
+		defer cancel()
+	} else {
+		slog.Info("shutting down; draining requests")
+	}
+	if metricsSrv != nil {
+		_ = metricsSrv.Shutdown(sctx)
+	}
+	if err := srv.Shutdown(sctx); err != nil {
+		return fmt.Errorf("shutdown: %w", err)
+	}
+	if err := <-errc; !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
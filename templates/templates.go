@@ -0,0 +1,8 @@
+// Package templates embeds the HTML templates in the binary: layout.html,
+// the shared partials and one file per page.
+package templates
+
+import "embed"
+
+//go:embed *.html partials/*.html
+var FS embed.FS